@@ -47,7 +47,9 @@ const longAbis = `Purpose:
 
 const notesAbis = `
 Notes:
-  - Search for either four byte signatures or event signatures with the --find option.`
+  - Search for either four byte signatures or event signatures with the --find option.
+  - With --decode, any addresses (or paths to ABI files) are used to decode the data before the known ABIs and the four-byte database.
+  - With --sol, the arguments are paths to Solidity source files. No compiler is needed, but only relative imports are followed.`
 
 func init() {
 	abisCmd.Flags().SortFlags = false
@@ -56,15 +58,15 @@ func init() {
 	abisCmd.Flags().StringSliceVarP(&abisPkg.GetOptions().Find, "find", "f", nil, "search for function or event declarations given a four- or 32-byte code(s)")
 	abisCmd.Flags().StringSliceVarP(&abisPkg.GetOptions().Hint, "hint", "n", nil, "for the --find option only, provide hints to speed up the search")
 	abisCmd.Flags().StringVarP(&abisPkg.GetOptions().Encode, "encode", "e", "", "generate the 32-byte encoding for a given cannonical function or event signature")
+	abisCmd.Flags().StringSliceVarP(&abisPkg.GetOptions().Decode, "decode", "d", nil, "decode calldata, a four-byte followed by return data, or an event's topics followed by its data")
 	abisCmd.Flags().BoolVarP(&abisPkg.GetOptions().Clean, "clean", "c", false, "remove an abi file for an address or all zero-length files if no address is given")
-	abisCmd.Flags().BoolVarP(&abisPkg.GetOptions().Sol, "sol", "s", false, "please use the `solc --abi` tool instead")
+	abisCmd.Flags().BoolVarP(&abisPkg.GetOptions().Sol, "sol", "s", false, "convert the given Solidity source file(s) to their ABI")
 	globals.InitGlobals(abisCmd, &abisPkg.GetOptions().Globals)
 
 	abisCmd.SetUsageTemplate(UsageWithNotes(notesAbis))
 	abisCmd.SetOut(os.Stderr)
 
 	// EXISTING_CODE
-	abisCmd.Flags().MarkDeprecated("sol", "please use the `solc --abi` tool instead")
 	// EXISTING_CODE
 
 	chifraCmd.AddCommand(abisCmd)
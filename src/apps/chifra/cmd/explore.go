@@ -45,13 +45,17 @@ const shortExplore = "open a local or remote explorer for one or more addresses,
 const longExplore = `Purpose:
   Open a local or remote explorer for one or more addresses, blocks, or transactions.`
 
-const notesExplore = ``
+const notesExplore = `
+Notes:
+  - The pages written by --render and served by --serve are self-contained and may be viewed with no internet access.`
 
 func init() {
 	exploreCmd.Flags().SortFlags = false
 
 	exploreCmd.Flags().BoolVarP(&explorePkg.GetOptions().Local, "local", "l", false, "open the local TrueBlocks explorer")
 	exploreCmd.Flags().BoolVarP(&explorePkg.GetOptions().Google, "google", "g", false, "search google excluding popular blockchain explorers")
+	exploreCmd.Flags().StringVarP(&explorePkg.GetOptions().Render, "render", "", "", "write a self-contained HTML and JSON page for each term to the given folder")
+	exploreCmd.Flags().BoolVarP(&explorePkg.GetOptions().Serve, "serve", "", false, "serve the pages for the terms from a local server instead of opening an explorer")
 	globals.InitGlobals(exploreCmd, &explorePkg.GetOptions().Globals)
 
 	exploreCmd.SetUsageTemplate(UsageWithNotes(notesExplore))
@@ -48,7 +48,9 @@ const longNames = `Purpose:
 const notesNames = `
 Notes:
   - The tool will accept up to three terms, each of which must match against any field in the database.
-  - The --match_case option enables case sensitive matching.`
+  - The --match_case option enables case sensitive matching.
+  - Names exported with --expand --fmt csv (or txt or json) may be imported with --import.
+  - Every change to the custom names is recorded in a journal. Use --history to see the changes to an address and --rollback to undo them.`
 
 func init() {
 	namesCmd.Flags().SortFlags = false
@@ -60,8 +62,19 @@ func init() {
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Prefund, "prefund", "p", false, "include prefund accounts in the search")
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Addr, "addr", "a", false, "display only addresses in the results (useful for scripting, assumes --no_header)")
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Tags, "tags", "g", false, "export the list of tags and subtags only")
+	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Ens, "ens", "", false, "include the primary ENS name of each address in the results")
+	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Fuzzy, "fuzzy", "z", false, "rank the results by relevance to the terms, matching partial words and tolerating typos")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Sort, "sort", "", "", `the order of the results (defaults to score with --fuzzy, address otherwise)
+One of [ address | name | tags | score ]`)
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Clean, "clean", "C", false, "clean the data (addrs to lower case, sort by addr) (hidden)")
-	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Autoname, "autoname", "A", "", "an address assumed to be a token, added automatically to names database if true (hidden)")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Autoname, "autoname", "A", "", "an address (or a file of addresses) to name from on-chain data and add to the custom names database (hidden)")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Import, "import", "", "", "import names from a CSV, TSV, or JSON file into the custom names database")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Mapping, "mapping", "", "", "for --import only, map the file's columns to name fields (for example label=name,category=tags)")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Conflict, "conflict", "", "", `for --import only, keep the existing name, overwrite it, or merge the two (taking the non-empty fields of the imported name)
+One of [ keep | overwrite | merge ]`)
+	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().DryRun, "dry_run", "", false, "for --import or --rollback only, report the changes without making them")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().History, "history", "", "", "show the journal of changes made to the custom name of the address")
+	namesCmd.Flags().StringVarP(&namesPkg.GetOptions().Rollback, "rollback", "", "", "undo changes made to custom names after the given date or timestamp (only for the addresses given as terms, if any)")
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Create, "create", "", false, "create a new name record (hidden)")
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Update, "update", "", false, "edit an existing name (hidden)")
 	namesCmd.Flags().BoolVarP(&namesPkg.GetOptions().Delete, "delete", "", false, "delete a name, but do not remove it (hidden)")
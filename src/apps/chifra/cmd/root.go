@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient/ens"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +23,9 @@ var chifraCmd = &cobra.Command{
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := chifraCmd.Execute(); err != nil {
+	err := chifraCmd.Execute()
+	ens.Flush()
+	if err != nil {
 		// fmt.Fprintf(os.Stderr, "%s", err)
 		os.Exit(1)
 	}
@@ -49,7 +49,8 @@ const notesTransactions = `
 Notes:
   - The transactions list may be one or more transaction hashes, blockNumber.transactionID pairs, or a blockHash.transactionID pairs.
   - This tool checks for valid input syntax, but does not check that the transaction requested actually exists.
-  - If the queried node does not store historical state, the results for most older transactions are undefined.`
+  - If the queried node does not store historical state, the results for most older transactions are undefined.
+  - Transfers of ETH are found in the traces and are reported with an asset address of 0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee.`
 
 func init() {
 	transactionsCmd.Flags().SortFlags = false
@@ -59,6 +60,8 @@ func init() {
 	transactionsCmd.Flags().BoolVarP(&transactionsPkg.GetOptions().Uniq, "uniq", "u", false, "display a list of uniq addresses found in the transaction")
 	transactionsCmd.Flags().StringVarP(&transactionsPkg.GetOptions().Flow, "flow", "f", "", `for the uniq option only, export only from or to (including trace from or to)
 One of [ from | to ]`)
+	transactionsCmd.Flags().BoolVarP(&transactionsPkg.GetOptions().Transfers, "transfers", "", false, "export the transfers of ETH and tokens made by the transaction(s)")
+	transactionsCmd.Flags().StringSliceVarP(&transactionsPkg.GetOptions().Asset, "asset", "", nil, "for the --transfers option only, export only transfers of this asset(s)")
 	transactionsCmd.Flags().StringVarP(&transactionsPkg.GetOptions().Reconcile, "reconcile", "r", "", "please use --account_for option instead")
 	transactionsCmd.Flags().StringVarP(&transactionsPkg.GetOptions().AccountFor, "account_for", "A", "", "reconcile the transaction as per the provided address")
 	transactionsCmd.Flags().BoolVarP(&transactionsPkg.GetOptions().Cache, "cache", "o", false, "force the results of the query into the tx cache (and the trace cache if applicable)")
@@ -49,7 +49,10 @@ const notesWhen = `
 Notes:
   - The block list may contain any combination of number, hash, date, special named blocks.
   - Block numbers, timestamps, or dates in the future are estimated with 13 second blocks.
-  - Dates must be formatted in JSON format: YYYY-MM-DD[THH[:MM[:SS]]].`
+  - Dates must be formatted in JSON format: YYYY-MM-DD[THH[:MM[:SS]]].
+  - User-defined named blocks are stored per chain (in user_specials.csv) and may be used wherever a special block may be used.
+  - Blocks may be offset by a number of blocks or a duration (s, m, h, d, w) as in latest-1000, london+50, or 2023-01-01T00:00+6h.
+  - Version 2 timestamp files (see --migrate) may start at any block, have gaps, and hold 64-bit timestamps.`
 
 func init() {
 	whenCmd.Flags().SortFlags = false
@@ -61,7 +64,12 @@ func init() {
 	whenCmd.Flags().BoolVarP(&whenPkg.GetOptions().Repair, "repair", "r", false, "with --timestamps only, repairs block(s) in the block range by re-querying from the chain")
 	whenCmd.Flags().BoolVarP(&whenPkg.GetOptions().Check, "check", "c", false, "with --timestamps only, checks the validity of the timestamp data")
 	whenCmd.Flags().BoolVarP(&whenPkg.GetOptions().Update, "update", "", false, "with --timestamps only, bring the timestamp database forward to the latest block")
+	whenCmd.Flags().BoolVarP(&whenPkg.GetOptions().Migrate, "migrate", "", false, "with --timestamps only, migrates the timestamp file to the version 2 format")
 	whenCmd.Flags().BoolVarP(&whenPkg.GetOptions().Deep, "deep", "e", false, "with --timestamps --check only, verifies timestamps from on chain (slow) (hidden)")
+	whenCmd.Flags().StringVarP(&whenPkg.GetOptions().Create, "create", "", "", "create a user-defined named block with this name at the given block")
+	whenCmd.Flags().StringVarP(&whenPkg.GetOptions().Edit, "edit", "", "", "move the user-defined named block with this name to the given block")
+	whenCmd.Flags().StringVarP(&whenPkg.GetOptions().Remove, "remove", "", "", "remove the user-defined named block with this name")
+	whenCmd.Flags().StringVarP(&whenPkg.GetOptions().Forks, "forks", "", "", "add the chain's hard forks to its special blocks given a chain config (genesis) JSON file")
 	if os.Getenv("TEST_MODE") != "true" {
 		whenCmd.Flags().MarkHidden("truncate")
 		whenCmd.Flags().MarkHidden("deep")
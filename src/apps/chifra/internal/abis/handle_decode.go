@@ -0,0 +1,271 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package abisPkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	goEthAbi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// decodeKind is the kind of data given to --decode
+type decodeKind int
+
+const (
+	decodeCall   decodeKind = iota // calldata (a four-byte followed by the arguments)
+	decodeReturn                   // a four-byte followed (as a separate item) by the function's return data
+	decodeEvent                    // an event's topics followed (as a separate item) by its data
+)
+
+// decodeInput is the parsed value of the --decode option
+type decodeInput struct {
+	kind     decodeKind
+	encoding string
+	topics   []base.Hash
+	data     string
+}
+
+// Sources of candidate ABIs in order of preference
+const (
+	sourceGiven     = iota // the addresses or ABI files on the command line
+	sourceKnown            // the known ABIs
+	sourceFourBytes        // the four-byte database
+)
+
+// decoding is a candidate that decoded the data
+type decoding struct {
+	function *types.SimpleFunction
+	source   int
+	exact    bool
+}
+
+// HandleDecode decodes calldata, return data, or an event's topics and data against the ABIs of
+// the given addresses (or ABI files), the known ABIs, and the four-byte database. Every candidate
+// that decodes the data is reported, best first (see rankDecodings).
+func (opts *AbisOptions) HandleDecode() error {
+	input, err := parseDecodeInput(opts.Decode)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fetchData := func(modelChan chan types.Modeler[types.RawFunction], errorChan chan error) {
+		candidates, err := opts.decodeCandidates(input)
+		if err != nil {
+			errorChan <- err
+			cancel()
+			return
+		}
+
+		decodings := make([]decoding, 0, len(candidates))
+		seen := make(map[string]bool)
+		for _, candidate := range candidates {
+			if seen[candidate.function.Signature] {
+				continue
+			}
+			if exact, err := decodeCandidate(input, candidate.function); err == nil {
+				seen[candidate.function.Signature] = true
+				candidate.exact = exact
+				decodings = append(decodings, candidate)
+			}
+		}
+
+		if len(decodings) == 0 {
+			errorChan <- fmt.Errorf("no ABI decodes the data for encoding %s", input.encoding)
+			cancel()
+			return
+		}
+
+		for _, d := range rankDecodings(decodings) {
+			modelChan <- d.function
+		}
+	}
+
+	extra := map[string]interface{}{
+		"decode": true,
+	}
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
+}
+
+// parseDecodeInput parses the items given to --decode. A single item is calldata. A four-byte
+// followed by a second item is return data. Two or more items starting with a 32-byte topic are
+// an event's topics followed by its data (which may be 0x).
+func parseDecodeInput(items []string) (*decodeInput, error) {
+	cleaned := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.ToLower(item)
+		if !strings.HasPrefix(item, "0x") {
+			return nil, fmt.Errorf("%s is not hex data", item)
+		}
+		if _, err := hex.DecodeString(item[2:]); err != nil {
+			return nil, fmt.Errorf("%s is not hex data: %w", item, err)
+		}
+		cleaned = append(cleaned, item)
+	}
+
+	switch {
+	case len(cleaned) == 1 && len(cleaned[0]) >= 10:
+		return &decodeInput{kind: decodeCall, encoding: cleaned[0][:10], data: cleaned[0][10:]}, nil
+
+	case len(cleaned) == 2 && len(cleaned[0]) == 10:
+		return &decodeInput{kind: decodeReturn, encoding: cleaned[0], data: cleaned[1][2:]}, nil
+
+	case len(cleaned) >= 2 && len(cleaned[0]) == 66:
+		ret := &decodeInput{kind: decodeEvent, encoding: cleaned[0], data: cleaned[len(cleaned)-1][2:]}
+		for _, topic := range cleaned[:len(cleaned)-1] {
+			if len(topic) != 66 {
+				return nil, fmt.Errorf("%s is not a valid topic", topic)
+			}
+			ret.topics = append(ret.topics, base.HexToHash(topic))
+		}
+		return ret, nil
+	}
+
+	return nil, errors.New("--decode expects calldata, a four-byte followed by return data, or an event's topics followed by its data")
+}
+
+// decodeCandidates returns copies of the functions or events whose encoding matches the input
+// from each source in order of preference
+func (opts *AbisOptions) decodeCandidates(input *decodeInput) ([]decoding, error) {
+	chain := opts.Globals.Chain
+	ret := []decoding{}
+	add := func(function *types.SimpleFunction, source int) {
+		if function == nil {
+			return
+		}
+		if (input.kind == decodeEvent && function.FunctionType == "event") || (input.kind != decodeEvent && function.IsMethod()) {
+			ret = append(ret, decoding{function: function.Copy(), source: source})
+		}
+	}
+
+	given := make(abi.AbiInterfaceMap)
+	for _, item := range opts.Addrs {
+		var err error
+		if file.FileExists(item) {
+			err = abi.LoadAbiFromFile(item, given)
+		} else {
+			err = abi.LoadAbi(chain, base.HexToAddress(item), given)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while loading ABI for %s: %w", item, err)
+		}
+	}
+	add(given[input.encoding], sourceGiven)
+
+	known := make(abi.AbiInterfaceMap)
+	if err := abi.PreloadKnownAbis(chain, known); err != nil {
+		return nil, err
+	}
+	add(known[input.encoding], sourceKnown)
+
+	if input.kind == decodeReturn {
+		// Signatures say nothing of a function's outputs
+		return ret, nil
+	}
+	nIndexed := 0
+	if input.kind == decodeEvent {
+		nIndexed = len(input.topics) - 1
+	}
+	fourBytes, err := abi.FourBytesCandidates(chain, input.encoding, nIndexed)
+	if err != nil {
+		return nil, err
+	}
+	for _, function := range fourBytes {
+		add(function, sourceFourBytes)
+	}
+
+	return ret, nil
+}
+
+// decodeCandidate articulates the input into the function. It reports whether re-encoding the
+// decoded values reproduces the data exactly (i.e., there are no leftover or malformed bytes).
+func decodeCandidate(input *decodeInput, function *types.SimpleFunction) (exact bool, err error) {
+	var args goEthAbi.Arguments
+	switch input.kind {
+	case decodeCall, decodeReturn:
+		abiMethod, err := function.GetAbiMethod()
+		if err != nil {
+			return false, err
+		}
+		if input.kind == decodeCall {
+			args = abiMethod.Inputs
+			err = articulate.ArticulateArguments(args, input.data, nil, function.Inputs)
+		} else {
+			args = abiMethod.Outputs
+			err = articulate.ArticulateArguments(args, input.data, nil, function.Outputs)
+		}
+		if err != nil {
+			return false, err
+		}
+
+	case decodeEvent:
+		abiEvent, err := function.GetAbiEvent()
+		if err != nil {
+			return false, err
+		}
+		nIndexed := 0
+		for _, arg := range abiEvent.Inputs {
+			if arg.Indexed {
+				nIndexed++
+			}
+		}
+		if nIndexed != len(input.topics)-1 {
+			return false, fmt.Errorf("%s has %d indexed parameters", function.Signature, nIndexed)
+		}
+		args = abiEvent.Inputs
+		if err = articulate.ArticulateArguments(args, input.data, input.topics, function.Inputs); err != nil {
+			return false, err
+		}
+	}
+
+	return reencodes(args.NonIndexed(), input.data), nil
+}
+
+// reencodes returns true if packing the unpacked data reproduces the data
+func reencodes(args goEthAbi.Arguments, data string) bool {
+	dataBytes, err := hex.DecodeString(data)
+	if err != nil {
+		return false
+	}
+	values, err := args.Unpack(dataBytes)
+	if err != nil {
+		return false
+	}
+	packed, err := args.Pack(values...)
+	return err == nil && bytes.Equal(packed, dataBytes)
+}
+
+// rankDecodings orders the decodings best first: those that reproduce the data exactly come
+// before those that don't, then those from the given ABIs before known ABIs before the four-byte
+// database. If more than one remains, the signatures of all of them are listed in the best
+// decoding's Ambiguous field.
+func rankDecodings(decodings []decoding) []decoding {
+	sort.SliceStable(decodings, func(i, j int) bool {
+		if decodings[i].exact != decodings[j].exact {
+			return decodings[i].exact
+		}
+		return decodings[i].source < decodings[j].source
+	})
+
+	if len(decodings) > 1 {
+		best := decodings[0].function
+		for _, d := range decodings {
+			best.Ambiguous = append(best.Ambiguous, d.function.Signature)
+		}
+	}
+	return decodings
+}
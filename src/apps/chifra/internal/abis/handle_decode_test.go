@@ -0,0 +1,83 @@
+package abisPkg
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseDecodeInput(t *testing.T) {
+	topic := "0x" + strings.Repeat("ab", 32)
+	tests := []struct {
+		items []string
+		kind  decodeKind
+		ok    bool
+	}{
+		{[]string{"0xa9059cbb0000"}, decodeCall, true},
+		{[]string{"0x70a08231", "0x" + strings.Repeat("00", 32)}, decodeReturn, true},
+		{[]string{topic, topic, "0x"}, decodeEvent, true},
+		{[]string{topic, "0x1234", "0x"}, decodeEvent, false},
+		{[]string{"0x1234"}, decodeCall, false},
+		{[]string{"a9059cbb"}, decodeCall, false},
+		{[]string{"0xzz059cbb"}, decodeCall, false},
+	}
+	for _, test := range tests {
+		input, err := parseDecodeInput(test.items)
+		if (err == nil) != test.ok {
+			t.Error("unexpected result for", test.items, err)
+			continue
+		}
+		if test.ok && input.kind != test.kind {
+			t.Error("wrong kind for", test.items, input.kind)
+		}
+	}
+}
+
+func TestDecodeAndRank(t *testing.T) {
+	const abiJson = `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+	loaded, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := loaded.Methods["transfer"]
+	transfer := types.FunctionFromAbiMethod(&method, "test")
+
+	packed, _ := loaded.Pack("transfer", common.HexToAddress("0x00000000000000000000000000000000000000cc"), big.NewInt(7))
+	calldata := "0x" + hex.EncodeToString(packed)
+
+	input, _ := parseDecodeInput([]string{calldata})
+	exact := transfer.Copy()
+	if ok, err := decodeCandidate(input, exact); err != nil || !ok {
+		t.Fatal("expected an exact decoding", err)
+	}
+	if exact.Inputs[1].Value != "7" {
+		t.Fatal("unexpected value", exact.Inputs[1].Value)
+	}
+
+	// Trailing bytes still decode, but not exactly
+	input, _ = parseDecodeInput([]string{calldata + "00"})
+	loose := transfer.Copy()
+	loose.Signature = "transfer2(address,uint256)"
+	if ok, err := decodeCandidate(input, loose); err != nil || ok {
+		t.Fatal("expected an inexact decoding", err)
+	}
+
+	input, _ = parseDecodeInput([]string{"0xa9059cbb", "0x" + strings.Repeat("00", 31) + "01"})
+	output := transfer.Copy()
+	if ok, err := decodeCandidate(input, output); err != nil || !ok || output.Outputs[0].Value != true {
+		t.Fatal("expected the return data to decode", err, output.Outputs)
+	}
+
+	ranked := rankDecodings([]decoding{
+		{function: loose, source: sourceGiven, exact: false},
+		{function: exact, source: sourceFourBytes, exact: true},
+	})
+	if ranked[0].function != exact || len(exact.Ambiguous) != 2 {
+		t.Fatal("exact decodings should rank first and list the alternatives", ranked[0].function.Signature, exact.Ambiguous)
+	}
+}
@@ -0,0 +1,41 @@
+package abisPkg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// HandleSol converts each Solidity source file to its ABI and displays the functions, events, and
+// errors it declares (or inherits). Nothing is written to disc, so it is safe to serve from the API.
+func (opts *AbisOptions) HandleSol() (err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fetchData := func(modelChan chan types.Modeler[types.RawFunction], errorChan chan error) {
+		result := make(abi.AbiInterfaceMap)
+		for _, solFile := range opts.Addrs {
+			if err := abi.LoadAbiFromSolFile(solFile, result); err != nil {
+				errorChan <- err
+				cancel()
+				return
+			}
+		}
+
+		keys := make([]string, 0, len(result))
+		for k := range result {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			modelChan <- result[k]
+		}
+	}
+
+	extra := map[string]interface{}{
+		"verbose": opts.Globals.Verbose,
+	}
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
+}
@@ -25,8 +25,9 @@ type AbisOptions struct {
 	Find    []string              `json:"find,omitempty"`    // Search for function or event declarations given a four- or 32-byte code(s)
 	Hint    []string              `json:"hint,omitempty"`    // For the --find option only, provide hints to speed up the search
 	Encode  string                `json:"encode,omitempty"`  // Generate the 32-byte encoding for a given cannonical function or event signature
+	Decode  []string              `json:"decode,omitempty"`  // Decode calldata, a four-byte followed by return data, or an event's topics followed by its data
 	Clean   bool                  `json:"clean,omitempty"`   // Remove an abi file for an address or all zero-length files if no address is given
-	Sol     bool                  `json:"sol,omitempty"`     // Please use the `solc --abi` tool instead
+	Sol     bool                  `json:"sol,omitempty"`     // Convert the given Solidity source file(s) to their ABI
 	Globals globals.GlobalOptions `json:"globals,omitempty"` // The global options
 	BadFlag error                 `json:"badFlag,omitempty"` // An error flag if needed
 	// EXISTING_CODE
@@ -42,7 +43,9 @@ func (opts *AbisOptions) testLog() {
 	logger.TestLog(len(opts.Find) > 0, "Find: ", opts.Find)
 	logger.TestLog(len(opts.Hint) > 0, "Hint: ", opts.Hint)
 	logger.TestLog(len(opts.Encode) > 0, "Encode: ", opts.Encode)
+	logger.TestLog(len(opts.Decode) > 0, "Decode: ", opts.Decode)
 	logger.TestLog(opts.Clean, "Clean: ", opts.Clean)
+	logger.TestLog(opts.Sol, "Sol: ", opts.Sol)
 	opts.Globals.TestLog()
 }
 
@@ -77,6 +80,11 @@ func abisFinishParseApi(w http.ResponseWriter, r *http.Request) *AbisOptions {
 			}
 		case "encode":
 			opts.Encode = value[0]
+		case "decode":
+			for _, val := range value {
+				s := strings.Split(val, " ") // may contain space separated items
+				opts.Decode = append(opts.Decode, s...)
+			}
 		case "clean":
 			opts.Clean = true
 		case "sol":
@@ -55,6 +55,10 @@ func (opts *AbisOptions) AbisInternal() (err error, handled bool) {
 		err = opts.HandleAbiFind()
 	} else if len(opts.Encode) > 0 {
 		err = opts.HandleEncode()
+	} else if len(opts.Decode) > 0 {
+		err = opts.HandleDecode()
+	} else if opts.Sol {
+		err = opts.HandleSol()
 	} else if opts.Clean {
 		err = opts.HandleClean()
 	} else {
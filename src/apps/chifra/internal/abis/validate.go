@@ -5,6 +5,9 @@
 package abisPkg
 
 import (
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
 )
 
@@ -22,6 +25,9 @@ func (opts *AbisOptions) validateAbis() error {
 		if len(opts.Find) > 0 {
 			return validate.Usage("Please choose only one of {0}.", "--clean or --find")
 		}
+		if len(opts.Decode) > 0 {
+			return validate.Usage("Please choose only one of {0}.", "--clean or --decode")
+		}
 		if opts.Known {
 			return validate.Usage("Please choose only one of {0}.", "--clean or --known")
 		}
@@ -30,7 +36,25 @@ func (opts *AbisOptions) validateAbis() error {
 		}
 	}
 
-	if len(opts.Globals.File) == 0 && len(opts.Encode) == 0 && len(opts.Find) == 0 && !opts.Known && !opts.Clean {
+	if opts.Sol {
+		if opts.Globals.IsApiMode() {
+			return validate.Usage("The {0} option is not available in {1} mode.", "--sol", "API")
+		}
+		if len(opts.Find) > 0 || len(opts.Encode) > 0 || len(opts.Decode) > 0 || opts.Known || opts.Clean {
+			return validate.Usage("Please choose only one of {0}.", "--sol, --find, --encode, --decode, --known, or --clean")
+		}
+		if len(opts.Addrs) == 0 {
+			return validate.Usage("Please specify at least one {0}.", "Solidity source file")
+		}
+		for _, solFile := range opts.Addrs {
+			if !strings.HasSuffix(solFile, ".sol") || !file.FileExists(solFile) {
+				return validate.Usage("The {0} option requires existing Solidity source files ({1} is not one).", "--sol", solFile)
+			}
+		}
+		return opts.Globals.Validate()
+	}
+
+	if len(opts.Globals.File) == 0 && len(opts.Encode) == 0 && len(opts.Find) == 0 && len(opts.Decode) == 0 && !opts.Known && !opts.Clean {
 		// If we're not find and not known we better have at least one address
 		err := validate.ValidateAtLeastOneAddr(opts.Addrs)
 		if err != nil {
@@ -42,6 +66,15 @@ func (opts *AbisOptions) validateAbis() error {
 		return validate.Usage("Please choose only one of {0}.", "--find or --encode")
 	}
 
+	if len(opts.Decode) > 0 {
+		if len(opts.Find) > 0 || len(opts.Encode) > 0 || opts.Known {
+			return validate.Usage("Please choose only one of {0}.", "--decode, --find, --encode, or --known")
+		}
+		if _, err := parseDecodeInput(opts.Decode); err != nil {
+			return validate.Usage(err.Error())
+		}
+	}
+
 	for _, term := range opts.Find {
 		ok1, err1 := validate.IsValidFourByteE(term)
 		if !ok1 && len(term) < 10 {
@@ -0,0 +1,15 @@
+package daemonPkg
+
+import (
+	"net/http"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient/ens"
+)
+
+// EnsCacheHandler writes the ENS resolutions learned while serving the request to disc
+func EnsCacheHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		ens.Flush()
+	})
+}
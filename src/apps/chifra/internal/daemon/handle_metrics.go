@@ -0,0 +1,15 @@
+package daemonPkg
+
+import (
+	"net/http"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/metrics"
+)
+
+// RouteMetrics reports the scraper, monitor, RPC, and cache metrics in OpenMetrics text format
+func RouteMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	if err := metrics.WriteOpenMetrics(w); err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -1,11 +1,36 @@
 package daemonPkg
 
-// HandleMonitor starts and manages the monitor process
-func (opts *DaemonOptions) HandleMonitor() error {
-	// monitorOpts := monitorsPkg.GetMonitorsOptions([]string{}, &opts.Globals)
-	// monitorOpts.Watch:   true,
-	// monitorOpts.Sleep:   opts.Sleep,
-	// err, _ := monitorOpts.MonitorsInternal()
-	// return err
+import (
+	"time"
+
+	monitorsPkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/monitors"
+)
+
+// monitorService freshens the existing monitors under the daemon's supervisor
+type monitorService struct {
+	opts     *DaemonOptions
+	monitors *monitorsPkg.MonitorsOptions
+}
+
+func (s *monitorService) Name() string {
+	return "monitor"
+}
+
+func (s *monitorService) Start() (bool, error) {
+	s.monitors = monitorsPkg.GetMonitorsOptions([]string{}, &s.opts.Globals)
+	s.monitors.Watch = true
+	return true, nil
+}
+
+func (s *monitorService) RunPass() (bool, error) {
+	canceled, err := s.monitors.FreshenOnce()
+	return !canceled, err
+}
+
+func (s *monitorService) Sleep() time.Duration {
+	return time.Duration(s.monitors.Sleep * float64(time.Second))
+}
+
+func (s *monitorService) Details() any {
 	return nil
 }
@@ -1,15 +1,55 @@
 package daemonPkg
 
-import scrapePkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/scrape"
+import (
+	"sync"
+	"time"
 
-// HandleScraper starts and manages the scraper process
-func (opts *DaemonOptions) HandleScraper() error {
-	if len(opts.Scrape) == 0 {
-		return nil
+	scrapePkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/scrape"
+)
+
+// scraperService runs the block scraper one pass at a time under the daemon's supervisor
+type scraperService struct {
+	opts   *DaemonOptions
+	mutex  sync.Mutex
+	scrape *scrapePkg.ScrapeOptions
+	runner *scrapePkg.ScrapeRunner
+}
+
+func (s *scraperService) Name() string {
+	return "scraper"
+}
+
+func (s *scraperService) Start() (bool, error) {
+	scrapeOpts := scrapePkg.GetScrapeOptions([]string{}, &s.opts.Globals)
+	if err := scrapeOpts.Validate(); err != nil {
+		return false, err
+	}
+
+	runner, ok, err := scrapeOpts.NewScrapeRunner()
+	if !ok || err != nil {
+		return false, err
 	}
 
-	scrapeOpts := scrapePkg.GetScrapeOptions([]string{}, &opts.Globals)
-	// scrapeOpts.Sleep = opts.Sleep
-	err, _ := scrapeOpts.ScrapeInternal()
-	return err
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.scrape = scrapeOpts
+	s.runner = runner
+	return true, nil
+}
+
+func (s *scraperService) RunPass() (bool, error) {
+	return s.runner.RunPass()
+}
+
+func (s *scraperService) Sleep() time.Duration {
+	return s.scrape.SleepDuration(s.runner.Progress)
+}
+
+func (s *scraperService) Details() any {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.runner == nil {
+		return nil
+	}
+	return s.runner.Throttle()
 }
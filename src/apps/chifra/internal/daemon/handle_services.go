@@ -0,0 +1,67 @@
+package daemonPkg
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+)
+
+// supervisor manages the daemon's long-running services. It's created when the daemon starts.
+var supervisor *Supervisor
+
+// ServiceStateMessage is sent over the websocket each time a supervised service changes state
+const ServiceStateMessage MessageType = "service_state"
+
+// broadcastServiceState sends a service's new status to every websocket connection
+func broadcastServiceState(status ServiceStatus) {
+	bytes, err := json.Marshal(status)
+	if err != nil {
+		logger.Error("Could not marshal service status:", err)
+		return
+	}
+	msg := &Message{
+		Action:  ServiceStateMessage,
+		ID:      status.Name,
+		Content: string(bytes),
+	}
+	// The pool may be busy, but the supervisor should never wait on it
+	go func() {
+		connectionPool.broadcast <- msg
+	}()
+}
+
+// serviceRoutes returns the status, pause, and resume routes for the named service
+func serviceRoutes(name string) Routes {
+	respond := func(w http.ResponseWriter, op func(string) (ServiceStatus, error)) {
+		if supervisor == nil {
+			RespondWithError(w, http.StatusServiceUnavailable, errors.New("the daemon is not supervising any services"))
+			return
+		}
+		status, err := op(name)
+		if err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, ErrUnknownService) {
+				code = http.StatusNotFound
+			}
+			RespondWithError(w, code, err)
+			return
+		}
+		marshalled, _ := json.MarshalIndent(map[string][]ServiceStatus{"data": {status}}, "", "  ")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(marshalled)
+	}
+
+	return Routes{
+		Route{name + "Status", "GET", "/" + name + "/status", func(w http.ResponseWriter, r *http.Request) {
+			respond(w, func(n string) (ServiceStatus, error) { return supervisor.Status(n) })
+		}},
+		Route{name + "Pause", "POST", "/" + name + "/pause", func(w http.ResponseWriter, r *http.Request) {
+			respond(w, func(n string) (ServiceStatus, error) { return supervisor.Pause(n) })
+		}},
+		Route{name + "Resume", "POST", "/" + name + "/resume", func(w http.ResponseWriter, r *http.Request) {
+			respond(w, func(n string) (ServiceStatus, error) { return supervisor.Resume(n) })
+		}},
+	}
+}
@@ -10,6 +10,8 @@ package daemonPkg
 
 // EXISTING_CODE
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -20,6 +22,7 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	outputHelpers "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output/helpers"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/sigintTrap"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
 	"github.com/spf13/cobra"
 )
@@ -83,13 +86,36 @@ func (opts *DaemonOptions) DaemonInternal() (err error, handled bool) {
 		logger.InfoTable("Progress:          ", msg)
 	}
 
-	go opts.HandleScraper()
-	go opts.HandleMonitor()
-
 	// Start listening to the web sockets
 	RunWebsocketPool()
+
+	supervisor = NewSupervisor(broadcastServiceState)
+	if len(opts.Scrape) > 0 && opts.Scrape != "off" {
+		supervisor.Add(&scraperService{opts: opts})
+	}
+	if opts.Monitor {
+		supervisor.Add(&monitorService{opts: opts})
+	}
+
+	// On control+c we let the services finish their current pass and stop the server
+	server := &http.Server{Addr: opts.Port, Handler: NewRouter()}
+	ctx, cancel := context.WithCancel(context.Background())
+	cleanOnQuit := func() {
+		logger.Warn(colors.Yellow+"Shutting down. Waiting for services to finish their current pass...", colors.Off)
+	}
+	trapChannel := sigintTrap.Enable(ctx, cancel, cleanOnQuit)
+	defer sigintTrap.Disable(trapChannel)
+	go func() {
+		<-ctx.Done()
+		supervisor.Shutdown()
+		server.Shutdown(context.Background())
+	}()
+
 	// Start listening for requests
-	logger.Fatal(http.ListenAndServe(opts.Port, NewRouter()))
+	if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+	err = nil
 
 	// EXISTING_CODE
 
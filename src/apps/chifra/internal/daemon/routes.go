@@ -273,7 +273,9 @@ var routes = Routes{
 	Route{"RouteSlurp", "GET", "/slurp", RouteSlurp},
 	// END_ROUTE_ITEMS
 	Route{"RouteStatus", "GET", "/status", RouteStatus},
+	Route{"PostMonitors", "POST", "/monitors", RouteMonitors},
 	Route{"DeleteMonitors", "DELETE", "/monitors", RouteMonitors},
+	Route{"Metrics", "GET", "/metrics", RouteMetrics},
 }
 
 // By removing, inserting into, or altering any lines of code in this
@@ -306,11 +308,15 @@ type Routes []Route
 func NewRouter() *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
 	router.Use(CorsHandler)
+	router.Use(EnsCacheHandler)
 	router.
 		Methods("OPTIONS").
 		Handler(OptionsHandler)
 
-	for _, route := range routes {
+	all := routes
+	all = append(all, serviceRoutes("scraper")...)
+	all = append(all, serviceRoutes("monitor")...)
+	for _, route := range all {
 		var handler http.Handler
 		handler = route.HandlerFunc
 		handler = Logger(handler, route.Name)
@@ -0,0 +1,297 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package daemonPkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+)
+
+// Service is a long-running process such as the scraper or the monitor freshener that the
+// daemon supervises. The supervisor calls Start once (and again after a panic), then calls
+// RunPass repeatedly, sleeping for Sleep between passes, until the service reports it is
+// finished or the daemon shuts down.
+type Service interface {
+	// Name is used in the service's routes and messages
+	Name() string
+	// Start prepares the service to run. Returning false means the service should not run.
+	Start() (bool, error)
+	// RunPass does one unit of the service's work. Returning false means the service is finished.
+	RunPass() (bool, error)
+	// Sleep returns how long to rest after the most recent pass
+	Sleep() time.Duration
+	// Details returns service-specific information to include in the service's status
+	Details() any
+}
+
+// ServiceState is the state of a supervised service
+type ServiceState string
+
+const (
+	StateStarting   ServiceState = "starting"
+	StateRunning    ServiceState = "running"
+	StateSleeping   ServiceState = "sleeping"
+	StatePaused     ServiceState = "paused"
+	StateRestarting ServiceState = "restarting"
+	StateStopped    ServiceState = "stopped"
+)
+
+// ServiceStatus is the model returned by the service routes and sent over the websocket
+type ServiceStatus struct {
+	Name      string       `json:"name"`
+	State     ServiceState `json:"state"`
+	NPasses   uint64       `json:"nPasses"`
+	Restarts  uint64       `json:"restarts"`
+	LastPass  string       `json:"lastPass,omitempty"`
+	LastError string       `json:"lastError,omitempty"`
+	Details   any          `json:"details,omitempty"`
+}
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 5 * time.Minute
+)
+
+var ErrUnknownService = errors.New("unknown service")
+
+type supervised struct {
+	service  Service
+	mutex    sync.Mutex
+	state    ServiceState
+	paused   bool
+	nPasses  uint64
+	restarts uint64
+	lastPass time.Time
+	lastErr  string
+	wake     chan struct{}
+}
+
+// Supervisor manages the lifecycle of the daemon's long-running services
+type Supervisor struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	services map[string]*supervised
+	onChange func(ServiceStatus)
+}
+
+// NewSupervisor returns a supervisor that calls onChange (if not nil) each time a service
+// changes state
+func NewSupervisor(onChange func(ServiceStatus)) *Supervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Supervisor{
+		ctx:      ctx,
+		cancel:   cancel,
+		services: make(map[string]*supervised),
+		onChange: onChange,
+	}
+}
+
+// Add starts supervising the service
+func (s *Supervisor) Add(service Service) {
+	sv := &supervised{
+		service: service,
+		state:   StateStarting,
+		wake:    make(chan struct{}, 1),
+	}
+	s.services[service.Name()] = sv
+	s.wg.Add(1)
+	go s.run(sv)
+}
+
+// Names returns the names of the supervised services
+func (s *Supervisor) Names() []string {
+	ret := make([]string, 0, len(s.services))
+	for name := range s.services {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Status returns the current status of the named service
+func (s *Supervisor) Status(name string) (ServiceStatus, error) {
+	sv := s.services[name]
+	if sv == nil {
+		return ServiceStatus{}, fmt.Errorf("%w: %s", ErrUnknownService, name)
+	}
+	return sv.status(), nil
+}
+
+// Pause asks the named service to pause after its current pass
+func (s *Supervisor) Pause(name string) (ServiceStatus, error) {
+	return s.setPaused(name, true)
+}
+
+// Resume asks the named service to continue if it's paused
+func (s *Supervisor) Resume(name string) (ServiceStatus, error) {
+	return s.setPaused(name, false)
+}
+
+func (s *Supervisor) setPaused(name string, paused bool) (ServiceStatus, error) {
+	sv := s.services[name]
+	if sv == nil {
+		return ServiceStatus{}, fmt.Errorf("%w: %s", ErrUnknownService, name)
+	}
+
+	sv.mutex.Lock()
+	sv.paused = paused
+	sv.mutex.Unlock()
+
+	// wake the service if it's sleeping or paused so it notices right away
+	select {
+	case sv.wake <- struct{}{}:
+	default:
+	}
+
+	return sv.status(), nil
+}
+
+// Shutdown asks every service to stop after its current pass and waits for them to do so
+func (s *Supervisor) Shutdown() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Supervisor) run(sv *supervised) {
+	defer s.wg.Done()
+	defer s.setState(sv, StateStopped)
+
+	name := sv.service.Name()
+	backoff := minBackoff
+	started := false
+	for s.ctx.Err() == nil {
+		sv.mutex.Lock()
+		paused := sv.paused
+		sv.mutex.Unlock()
+
+		if paused {
+			s.setState(sv, StatePaused)
+			if !s.wait(sv, 0) {
+				return
+			}
+			continue
+		}
+
+		var ok, didPass bool
+		var err error
+		if !started {
+			s.setState(sv, StateStarting)
+			if ok, err = s.protect(sv, sv.service.Start); ok && err == nil {
+				started = true
+			}
+		} else {
+			s.setState(sv, StateRunning)
+			ok, err = s.protect(sv, sv.service.RunPass)
+			didPass = true
+			sv.mutex.Lock()
+			sv.nPasses++
+			sv.lastPass = time.Now()
+			sv.mutex.Unlock()
+		}
+
+		if errors.Is(err, errPanicked) {
+			// The service panicked. Restart it after a growing delay.
+			started = false
+			sv.mutex.Lock()
+			sv.restarts++
+			sv.mutex.Unlock()
+			s.setState(sv, StateRestarting)
+			logger.Warn("Service", name, "will restart in", backoff)
+			if !s.wait(sv, backoff) {
+				return
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		if err != nil || !ok {
+			if err != nil {
+				logger.Error("Service", name, "stopped:", err)
+			}
+			return
+		}
+
+		backoff = minBackoff
+		if d := sv.service.Sleep(); didPass && d > 0 {
+			s.setState(sv, StateSleeping)
+			if !s.wait(sv, d) {
+				return
+			}
+		}
+	}
+}
+
+var errPanicked = errors.New("service panicked")
+
+// protect calls f recovering from (and reporting) any panic
+func (s *Supervisor) protect(sv *supervised, f func() (bool, error)) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok, err = false, fmt.Errorf("%w: %v", errPanicked, r)
+		}
+		sv.mutex.Lock()
+		if err != nil {
+			sv.lastErr = err.Error()
+		}
+		sv.mutex.Unlock()
+	}()
+	return f()
+}
+
+// wait sleeps for the given duration (or, if zero, until woken). Returns false if the
+// supervisor is shutting down.
+func (s *Supervisor) wait(sv *supervised, d time.Duration) bool {
+	var timer <-chan time.Time
+	if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timer = t.C
+	}
+	select {
+	case <-s.ctx.Done():
+		return false
+	case <-sv.wake:
+	case <-timer:
+	}
+	return true
+}
+
+func (s *Supervisor) setState(sv *supervised, state ServiceState) {
+	sv.mutex.Lock()
+	changed := sv.state != state
+	sv.state = state
+	sv.mutex.Unlock()
+
+	if changed && s.onChange != nil {
+		s.onChange(sv.status())
+	}
+}
+
+func (sv *supervised) status() ServiceStatus {
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+	ret := ServiceStatus{
+		Name:      sv.service.Name(),
+		State:     sv.state,
+		NPasses:   sv.nPasses,
+		Restarts:  sv.restarts,
+		LastError: sv.lastErr,
+		Details:   sv.service.Details(),
+	}
+	if !sv.lastPass.IsZero() {
+		ret.LastPass = sv.lastPass.Format(time.RFC3339)
+	}
+	return ret
+}
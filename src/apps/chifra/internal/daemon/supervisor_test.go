@@ -0,0 +1,115 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package daemonPkg
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type testService struct {
+	mutex   sync.Mutex
+	starts  int
+	passes  int
+	panicAt int
+}
+
+func (s *testService) Name() string { return "test" }
+func (s *testService) Details() any { return nil }
+func (s *testService) Sleep() time.Duration {
+	return time.Millisecond
+}
+
+func (s *testService) Start() (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.starts++
+	return true, nil
+}
+
+func (s *testService) RunPass() (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.passes++
+	if s.passes == s.panicAt {
+		panic("boom")
+	}
+	return true, nil
+}
+
+func (s *testService) counts() (int, int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.starts, s.passes
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSupervisor(t *testing.T) {
+	var mutex sync.Mutex
+	seen := map[ServiceState]bool{}
+	s := NewSupervisor(func(status ServiceStatus) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		seen[status.State] = true
+	})
+
+	svc := &testService{panicAt: 3}
+	s.Add(svc)
+
+	// The service panics on its third pass and is restarted
+	waitFor(t, "restart", func() bool {
+		starts, passes := svc.counts()
+		return starts == 2 && passes > 3
+	})
+	if status, _ := s.Status("test"); status.Restarts != 1 || status.LastError == "" {
+		t.Error("expected one restart with an error, got", status)
+	}
+
+	if _, err := s.Pause("test"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "pause", func() bool {
+		status, _ := s.Status("test")
+		return status.State == StatePaused
+	})
+	_, before := svc.counts()
+	time.Sleep(20 * time.Millisecond)
+	if _, after := svc.counts(); after != before {
+		t.Error("expected no passes while paused", before, after)
+	}
+
+	s.Resume("test")
+	waitFor(t, "resume", func() bool {
+		_, after := svc.counts()
+		return after > before
+	})
+
+	if _, err := s.Status("unknown"); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+
+	s.Shutdown()
+	if status, _ := s.Status("test"); status.State != StateStopped {
+		t.Error("expected the service to be stopped, got", status.State)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, state := range []ServiceState{StateRunning, StateRestarting, StatePaused, StateStopped} {
+		if !seen[state] {
+			t.Error("expected a state change to", state)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package explorePkg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+)
+
+// HandleRender gathers a page for each term and writes it to the --render folder as both HTML
+// and JSON, along with an index of the pages. Pages link to each other if they were rendered
+// together.
+func (opts *ExploreOptions) HandleRender() error {
+	if err := file.EstablishFolder(opts.Render); err != nil {
+		return err
+	}
+
+	builder := newPageBuilder(opts.Globals.Chain)
+
+	pages := []*explorePage{}
+	rendered := map[string]bool{}
+	for _, url := range urls {
+		kind, id := url.pageKind(), strings.ToLower(url.term)
+		if rendered[pageFileName(kind, id)] {
+			continue
+		}
+		page, err := builder.build(kind, id)
+		if err != nil {
+			return fmt.Errorf("%s %s returned an error: %w", kind, id, err)
+		}
+		pages = append(pages, page)
+		rendered[pageFileName(kind, id)] = true
+	}
+
+	linker := func(kind, id string) string {
+		if len(kind) == 0 {
+			return "index.html"
+		}
+		if fileName := pageFileName(kind, id); rendered[fileName] {
+			return fileName + ".html"
+		}
+		return ""
+	}
+
+	for _, page := range append(pages, indexPage(opts.Globals.Chain, pages)) {
+		name := filepath.Join(opts.Render, pageFileName(page.Kind, page.Id))
+		if err := writePage(name+".html", func(w io.Writer) error { return renderHtml(w, page, linker) }); err != nil {
+			return err
+		}
+		if page.Kind == pageIndex {
+			continue
+		}
+		if err := writePage(name+".json", func(w io.Writer) error { return renderJson(w, page) }); err != nil {
+			return err
+		}
+		fmt.Printf("Rendered %s.html\n", name)
+	}
+	return nil
+}
+
+// pageFileName is the name (without extension) of the files a page is rendered to
+func pageFileName(kind, id string) string {
+	if kind == pageIndex {
+		return "index"
+	}
+	return kind + "-" + id
+}
+
+func writePage(path string, render func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return render(f)
+}
+
+// indexPage lists the pages
+func indexPage(chain string, pages []*explorePage) *explorePage {
+	table := pageTable{Title: "Pages", Columns: []string{"Page"}}
+	for _, page := range pages {
+		table.Rows = append(table.Rows, []pageCell{{Text: page.Title, Kind: page.Kind, Id: page.Id}})
+	}
+	return &explorePage{
+		Kind:   pageIndex,
+		Chain:  chain,
+		Title:  "Explorer",
+		Tables: []pageTable{table},
+	}
+}
+
+// pageKind returns the kind of page that shows the term
+func (u *ExploreUrl) pageKind() string {
+	switch u.termType {
+	case ExploreBlock:
+		return pageBlock
+	case ExploreTx:
+		return pageTx
+	case ExploreAddress:
+		return pageAddress
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package explorePkg
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// HandleServe serves pages for the terms (and anything they link to) from a local server on a
+// free port until the user quits. Pages are gathered the first time they are asked for. Adding
+// .json to a page's path returns the page's data instead of its HTML.
+func (opts *ExploreOptions) HandleServe() error {
+	builder := newPageBuilder(opts.Globals.Chain)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	server := &pageServer{
+		chain:   opts.Globals.Chain,
+		builder: builder,
+		pages:   map[string]*explorePage{},
+	}
+	for _, url := range urls {
+		server.terms = append(server.terms, pageCell{Text: url.term, Kind: url.pageKind(), Id: strings.ToLower(url.term)})
+	}
+
+	root := fmt.Sprintf("http://%s/", listener.Addr().String())
+	fmt.Printf("Serving %s\n", root)
+	if !opts.Globals.TestMode {
+		open := root
+		if len(server.terms) == 1 {
+			open += server.terms[0].Kind + "/" + server.terms[0].Id
+		}
+		utils.OpenBrowser(open)
+	}
+
+	return http.Serve(listener, server)
+}
+
+// pageServer serves the pages. Every page links to any other page, since any page may be
+// gathered on demand.
+type pageServer struct {
+	chain   string
+	builder *pageBuilder
+	terms   []pageCell
+	mutex   sync.Mutex
+	pages   map[string]*explorePage
+}
+
+func serverLinker(kind, id string) string {
+	if len(kind) == 0 {
+		return "/"
+	}
+	return "/" + kind + "/" + id
+}
+
+func (s *pageServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if len(path) == 0 {
+		table := pageTable{Title: "Pages", Columns: []string{"Page"}, Rows: [][]pageCell{}}
+		for _, term := range s.terms {
+			table.Rows = append(table.Rows, []pageCell{term})
+		}
+		index := &explorePage{Kind: pageIndex, Chain: s.chain, Title: "Explorer", Tables: []pageTable{table}}
+		s.respond(w, index, false)
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	kind, id := parts[0], parts[1]
+	asJson := strings.HasSuffix(id, ".json")
+	id = strings.TrimSuffix(id, ".json")
+
+	page, err := s.getPage(kind, id)
+	if err != nil {
+		logger.Warn("Could not build", kind, id, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.respond(w, page, asJson)
+}
+
+func (s *pageServer) respond(w http.ResponseWriter, page *explorePage, asJson bool) {
+	var err error
+	if asJson {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = renderJson(w, page)
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err = renderHtml(w, page, serverLinker)
+	}
+	if err != nil {
+		logger.Warn("Could not render", page.Kind, page.Id, err)
+	}
+}
+
+// getPage returns the page, gathering it if it has not been asked for before. Pages are gathered
+// one at a time (the builder is not safe for concurrent use).
+func (s *pageServer) getPage(kind, id string) (*explorePage, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if page, ok := s.pages[kind+"/"+id]; ok {
+		return page, nil
+	}
+
+	resolved, err := resolveId(s.chain, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("%s %s not found", kind, id)
+	}
+
+	page, ok := s.pages[kind+"/"+resolved]
+	if !ok {
+		if page, err = s.builder.build(kind, resolved); err != nil {
+			return nil, err
+		}
+		s.pages[kind+"/"+resolved] = page
+	}
+	// Remember the page under the id it was asked for as well (such as a block number)
+	s.pages[kind+"/"+id] = page
+	return page, nil
+}
@@ -24,6 +24,8 @@ type ExploreOptions struct {
 	Terms   []string              `json:"terms,omitempty"`   // One or more address, name, block, or transaction identifier
 	Local   bool                  `json:"local,omitempty"`   // Open the local TrueBlocks explorer
 	Google  bool                  `json:"google,omitempty"`  // Search google excluding popular blockchain explorers
+	Render  string                `json:"render,omitempty"`  // Write a self-contained HTML and JSON page for each term to the given folder
+	Serve   bool                  `json:"serve,omitempty"`   // Serve the pages for the terms from a local server instead of opening an explorer
 	Globals globals.GlobalOptions `json:"globals,omitempty"` // The global options
 	BadFlag error                 `json:"badFlag,omitempty"` // An error flag if needed
 	// EXISTING_CODE
@@ -37,6 +39,8 @@ func (opts *ExploreOptions) testLog() {
 	logger.TestLog(len(opts.Terms) > 0, "Terms: ", opts.Terms)
 	logger.TestLog(opts.Local, "Local: ", opts.Local)
 	logger.TestLog(opts.Google, "Google: ", opts.Google)
+	logger.TestLog(len(opts.Render) > 0, "Render: ", opts.Render)
+	logger.TestLog(opts.Serve, "Serve: ", opts.Serve)
 	opts.Globals.TestLog()
 }
 
@@ -63,6 +67,12 @@ func (opts *ExploreOptions) toCmdLine() string {
 	if opts.Google {
 		options += " --google"
 	}
+	if len(opts.Render) > 0 {
+		options += " --render " + opts.Render
+	}
+	if opts.Serve {
+		options += " --serve"
+	}
 	options += " " + strings.Join(opts.Terms, " ")
 	// EXISTING_CODE
 	// EXISTING_CODE
@@ -85,6 +95,10 @@ func exploreFinishParseApi(w http.ResponseWriter, r *http.Request) *ExploreOptio
 			opts.Local = true
 		case "google":
 			opts.Google = true
+		case "render":
+			opts.Render = value[0]
+		case "serve":
+			opts.Serve = true
 		default:
 			if !globals.IsGlobalOption(key) {
 				opts.BadFlag = validate.Usage("Invalid key ({0}) in {1} route.", key, "explore")
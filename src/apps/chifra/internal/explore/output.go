@@ -59,6 +59,12 @@ func (opts *ExploreOptions) ExploreInternal() (err error, handled bool) {
 		return validate.Usage("Cannot use explore route in API mode."), true
 	}
 
+	if len(opts.Render) > 0 {
+		return opts.HandleRender(), true
+	} else if opts.Serve {
+		return opts.HandleServe(), true
+	}
+
 	for _, url := range urls {
 		ret := url.getUrl(opts)
 		fmt.Printf("Opening %s\n", ret)
@@ -0,0 +1,436 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package explorePkg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
+)
+
+// The kinds of pages we can build. They are also the first part of the page's path when serving.
+const (
+	pageBlock   = "block"
+	pageTx      = "tx"
+	pageAddress = "address"
+	pageIndex   = "index"
+)
+
+// nRecentApps is the number of an address's most recent appearances shown on its page
+const nRecentApps = 25
+
+// explorePage is everything needed to render a single block, transaction, or address without
+// going back to the node. Data is the entity itself (for the JSON version of the page). Fields
+// and Tables are its human readable form (for the HTML version of the page).
+type explorePage struct {
+	Kind   string                      `json:"kind"`
+	Id     string                      `json:"id"`
+	Chain  string                      `json:"chain"`
+	Data   any                         `json:"data"`
+	Names  map[string]types.SimpleName `json:"names,omitempty"`
+	Title  string                      `json:"-"`
+	Fields []pageField                 `json:"-"`
+	Tables []pageTable                 `json:"-"`
+}
+
+// pageField is a single labeled value shown at the top of a page
+type pageField struct {
+	Label string
+	Value pageCell
+}
+
+// pageTable is a list of rows (transactions, logs, appearances, and so on) shown on a page
+type pageTable struct {
+	Title   string
+	Columns []string
+	Rows    [][]pageCell
+}
+
+// pageCell is a value shown on a page. If Kind is not empty, the value refers to another page
+// (identified by Kind and Id) which it links to if that page is available. Label is the name
+// of an address, if it has one. Link is filled in when the page is rendered.
+type pageCell struct {
+	Text  string
+	Kind  string
+	Id    string
+	Label string
+	Link  string
+}
+
+// addressSummary is the Data of an address page
+type addressSummary struct {
+	Address     base.Address             `json:"address"`
+	Name        *types.SimpleName        `json:"name,omitempty"`
+	Balance     string                   `json:"balance,omitempty"`
+	Abi         []types.SimpleFunction   `json:"abi"`
+	NApps       uint32                   `json:"nAppearances"`
+	Appearances []types.SimpleAppearance `json:"appearances"`
+}
+
+// pageBuilder gathers the pages. It keeps the ABIs it loads so building many pages is not much
+// slower than building one.
+type pageBuilder struct {
+	chain  string
+	abiMap abi.AbiInterfaceMap
+	loaded abi.LoadedAbis
+}
+
+func newPageBuilder(chain string) *pageBuilder {
+	return &pageBuilder{
+		chain:  chain,
+		abiMap: make(abi.AbiInterfaceMap),
+		loaded: make(abi.LoadedAbis),
+	}
+}
+
+// build gathers the page of the given kind for the id, which must be a block hash, a transaction
+// hash, or an address (see resolveId)
+func (b *pageBuilder) build(kind, id string) (*explorePage, error) {
+	page := &explorePage{
+		Kind:  kind,
+		Id:    strings.ToLower(id),
+		Chain: b.chain,
+		Names: map[string]types.SimpleName{},
+	}
+
+	var err error
+	switch kind {
+	case pageBlock:
+		err = b.buildBlock(page)
+	case pageTx:
+		err = b.buildTx(page)
+	case pageAddress:
+		err = b.buildAddress(page)
+	default:
+		err = fmt.Errorf("unknown page kind %s", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// resolveId turns any block or transaction identifier into the hash its page is known by
+func resolveId(chain, kind, id string) (string, error) {
+	id = strings.ToLower(id)
+	switch kind {
+	case pageBlock:
+		return rpcClient.Id_2_BlockHash(chain, id, validate.IsBlockHash)
+	case pageTx:
+		return rpcClient.Id_2_TxHash(chain, id, validate.IsBlockHash)
+	case pageAddress:
+		if ok, _ := validate.IsValidHex("address", id, 20); !ok {
+			return "", fmt.Errorf("%s is not a valid address", id)
+		}
+		address := base.HexToAddress(id)
+		return address.Hex(), nil
+	}
+	return "", fmt.Errorf("unknown page kind %s", kind)
+}
+
+func (b *pageBuilder) buildBlock(page *explorePage) error {
+	bn, err := rpcClient.BlockNumberFromHash(config.GetRpcProvider(b.chain), page.Id)
+	if err != nil {
+		return err
+	}
+	block, err := rpcClient.GetBlockByNumberWithTxs(b.chain, bn, false)
+	if err != nil {
+		return err
+	}
+
+	page.Data = &block
+	page.Title = fmt.Sprintf("Block %d", block.BlockNumber)
+	page.Fields = []pageField{
+		{"Block", textCell(fmt.Sprint(block.BlockNumber))},
+		{"Hash", textCell(block.Hash.Hex())},
+		{"Parent", pageCell{Text: block.ParentHash.Hex(), Kind: pageBlock, Id: block.ParentHash.Hex()}},
+		{"Date", textCell(utils.FormattedDate(block.Timestamp))},
+		{"Miner", b.addressCell(page, block.Miner)},
+		{"Gas used", textCell(fmt.Sprintf("%d of %d", block.GasUsed, block.GasLimit))},
+		{"Base fee", textCell(block.BaseFeePerGas.String())},
+		{"Transactions", textCell(fmt.Sprint(len(block.Transactions)))},
+	}
+
+	table := pageTable{Title: "Transactions", Columns: []string{"Index", "Hash", "From", "To", "Ether"}}
+	for _, tx := range block.Transactions {
+		table.Rows = append(table.Rows, []pageCell{
+			textCell(fmt.Sprint(tx.TransactionIndex)),
+			{Text: tx.Hash.Hex(), Kind: pageTx, Id: tx.Hash.Hex()},
+			b.addressCell(page, tx.From),
+			b.addressCell(page, tx.To),
+			textCell(utils.WeiToEther(&tx.Value).Text('f', 18)),
+		})
+	}
+	page.Tables = append(page.Tables, table)
+	return nil
+}
+
+func (b *pageBuilder) buildTx(page *explorePage) error {
+	bn, txId, err := rpcClient.GetAppearanceFromHash(b.chain, page.Id)
+	if err != nil {
+		return err
+	}
+	tx, err := rpcClient.GetTransactionByAppearance(b.chain, &types.RawAppearance{
+		BlockNumber:      uint32(bn),
+		TransactionIndex: uint32(txId),
+	}, false)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return fmt.Errorf("transaction %s not found", page.Id)
+	}
+	b.articulateTx(tx)
+
+	status := "success"
+	if tx.IsError {
+		status = "error"
+	}
+	page.Data = tx
+	page.Title = "Transaction " + tx.Hash.Hex()
+	page.Fields = []pageField{
+		{"Hash", textCell(tx.Hash.Hex())},
+		{"Block", pageCell{Text: fmt.Sprintf("%d.%d", tx.BlockNumber, tx.TransactionIndex), Kind: pageBlock, Id: tx.BlockHash.Hex()}},
+		{"Date", textCell(utils.FormattedDate(tx.Timestamp))},
+		{"From", b.addressCell(page, tx.From)},
+		{"To", b.addressCell(page, tx.To)},
+		{"Ether", textCell(utils.WeiToEther(&tx.Value).Text('f', 18))},
+		{"Gas used", textCell(fmt.Sprint(tx.GasUsed))},
+		{"Gas price", textCell(fmt.Sprint(tx.GasPrice))},
+		{"Status", textCell(status)},
+	}
+	if tx.Receipt != nil && !tx.Receipt.ContractAddress.IsZero() {
+		page.Fields = append(page.Fields, pageField{"Created", b.addressCell(page, tx.Receipt.ContractAddress)})
+	}
+
+	if tx.ArticulatedTx != nil {
+		page.Fields = append(page.Fields, pageField{"Function", textCell(tx.ArticulatedTx.Name)})
+		page.Tables = append(page.Tables, b.parameterTable(page, "Input", tx.ArticulatedTx.Inputs))
+	} else if len(tx.Input) > 2 {
+		page.Fields = append(page.Fields, pageField{"Input", textCell(tx.Input)})
+	}
+
+	if tx.Receipt != nil {
+		table := pageTable{Title: "Logs", Columns: []string{"Index", "Address", "Event", "Values"}}
+		for _, log := range tx.Receipt.Logs {
+			event, values := "", strings.Join(hashStrings(log.Topics), " ")+" "+log.Data
+			if log.ArticulatedLog != nil {
+				event, values = log.ArticulatedLog.Name, parameterString(log.ArticulatedLog.Inputs)
+			}
+			table.Rows = append(table.Rows, []pageCell{
+				textCell(fmt.Sprint(log.LogIndex)),
+				b.addressCell(page, log.Address),
+				textCell(event),
+				textCell(strings.TrimSpace(values)),
+			})
+		}
+		page.Tables = append(page.Tables, table)
+	}
+	return nil
+}
+
+// articulateTx articulates the transaction's input and its logs. Failures are reported but
+// otherwise ignored, since the page is still useful without them.
+func (b *pageBuilder) articulateTx(tx *types.SimpleTransaction) {
+	b.loadAbi(tx.To, uint64(tx.BlockNumber))
+	if len(tx.Input) >= 10 {
+		if found := b.abiMap[tx.Input[:10]]; found != nil && found.IsMethod() {
+			// Work on a copy so we don't write the values into the shared ABI
+			function := *found
+			function.Inputs = append([]types.SimpleParameter{}, found.Inputs...)
+			function.Outputs = append([]types.SimpleParameter{}, found.Outputs...)
+			if err := articulate.ArticulateFunction(&function, tx.Input[10:], ""); err != nil {
+				logger.Warn("Could not articulate", tx.Hash.Hex(), err)
+			} else {
+				tx.ArticulatedTx = &function
+			}
+		} else if function, err := articulate.FindFunction(b.chain, tx.Input); err == nil {
+			tx.ArticulatedTx = function
+		}
+	}
+
+	if tx.Receipt == nil {
+		return
+	}
+	for index, log := range tx.Receipt.Logs {
+		b.loadAbi(log.Address, uint64(log.BlockNumber))
+		articulated, err := articulate.ArticulateLog(&log, b.abiMap)
+		if err != nil {
+			logger.Warn("Could not articulate log", log.LogIndex, "of", tx.Hash.Hex(), err)
+		}
+		if articulated == nil {
+			articulated, _ = articulate.FindEvent(b.chain, &log)
+		}
+		tx.Receipt.Logs[index].ArticulatedLog = articulated
+	}
+}
+
+func (b *pageBuilder) loadAbi(address base.Address, bn uint64) {
+	if address.IsZero() {
+		return
+	}
+	if err := b.loaded.Load(b.chain, address, bn, b.abiMap); err != nil {
+		logger.Warn("Could not load the ABI for", address.Hex(), err)
+	}
+}
+
+func (b *pageBuilder) buildAddress(page *explorePage) error {
+	address := base.HexToAddress(page.Id)
+	summary := addressSummary{
+		Address:     address,
+		Abi:         []types.SimpleFunction{},
+		Appearances: []types.SimpleAppearance{},
+	}
+	if name, ok := names.FindName(b.chain, address); ok {
+		summary.Name = &name
+	}
+
+	client := rpcClient.GetClient(config.GetRpcProvider(b.chain))
+	defer client.Close()
+	ether := "unavailable"
+	if balance, err := client.BalanceAt(context.Background(), address.Address, nil); err != nil {
+		// The rest of the page is local, so it's still worth showing
+		logger.Warn("Could not get the balance of", address.Hex(), err)
+	} else {
+		summary.Balance = balance.String()
+		ether = utils.WeiToEther(balance).Text('f', 18)
+	}
+
+	// Only ABIs we already have, so building the page never needs the internet
+	abiMap := make(abi.AbiInterfaceMap)
+	if err := abi.LoadAbiFromAddress(b.chain, address, abiMap); err == nil {
+		for _, function := range abiMap {
+			summary.Abi = append(summary.Abi, *function)
+		}
+		sort.Slice(summary.Abi, func(i, j int) bool {
+			if summary.Abi[i].FunctionType != summary.Abi[j].FunctionType {
+				return summary.Abi[i].FunctionType < summary.Abi[j].FunctionType
+			}
+			return summary.Abi[i].Name < summary.Abi[j].Name
+		})
+	}
+
+	// Only appearances we already have, from the address's monitor if there is one
+	mon := monitor.NewMonitor(b.chain, address.Hex(), false)
+	defer mon.Close()
+	if summary.NApps = mon.Count(); summary.NApps > 0 {
+		apps := make([]index.AppearanceRecord, summary.NApps)
+		if err := mon.ReadAppearances(&apps); err != nil {
+			return err
+		}
+		sort.Slice(apps, func(i, j int) bool {
+			if apps[i].BlockNumber != apps[j].BlockNumber {
+				return apps[i].BlockNumber > apps[j].BlockNumber
+			}
+			return apps[i].TransactionId > apps[j].TransactionId
+		})
+		for _, app := range apps[:utils.Min(len(apps), nRecentApps)] {
+			summary.Appearances = append(summary.Appearances, types.SimpleAppearance{
+				Address:          address,
+				BlockNumber:      app.BlockNumber,
+				TransactionIndex: app.TransactionId,
+			})
+		}
+	}
+
+	page.Data = &summary
+	page.Title = "Address " + address.Hex()
+	page.Fields = []pageField{
+		{"Address", b.addressCell(page, address)},
+		{"Ether", textCell(ether)},
+		{"Appearances", textCell(fmt.Sprint(summary.NApps))},
+	}
+	if summary.Name != nil {
+		page.Fields = append(page.Fields,
+			pageField{"Name", textCell(summary.Name.Name)},
+			pageField{"Tags", textCell(summary.Name.Tags)},
+		)
+		if len(summary.Name.Symbol) > 0 {
+			page.Fields = append(page.Fields, pageField{"Symbol", textCell(summary.Name.Symbol)})
+		}
+	}
+
+	if len(summary.Appearances) > 0 {
+		table := pageTable{Title: "Recent appearances", Columns: []string{"Transaction"}}
+		for _, app := range summary.Appearances {
+			id := fmt.Sprintf("%d.%d", app.BlockNumber, app.TransactionIndex)
+			table.Rows = append(table.Rows, []pageCell{{Text: id, Kind: pageTx, Id: id}})
+		}
+		page.Tables = append(page.Tables, table)
+	}
+
+	if len(summary.Abi) > 0 {
+		table := pageTable{Title: "ABI", Columns: []string{"Type", "Encoding", "Signature"}}
+		for _, function := range summary.Abi {
+			table.Rows = append(table.Rows, []pageCell{
+				textCell(function.FunctionType),
+				textCell(function.Encoding),
+				textCell(function.Signature),
+			})
+		}
+		page.Tables = append(page.Tables, table)
+	}
+	return nil
+}
+
+// addressCell links to the address's page and labels it with its name, if it has one. The name
+// is also added to the page's names (for the JSON version of the page).
+func (b *pageBuilder) addressCell(page *explorePage, address base.Address) pageCell {
+	if address.IsZero() {
+		return textCell("")
+	}
+	cell := pageCell{Text: address.Hex(), Kind: pageAddress, Id: address.Hex()}
+	if name, ok := names.FindName(b.chain, address); ok {
+		cell.Label = name.Name
+		page.Names[address.Hex()] = name
+	}
+	return cell
+}
+
+func (b *pageBuilder) parameterTable(page *explorePage, title string, params []types.SimpleParameter) pageTable {
+	table := pageTable{Title: title, Columns: []string{"Name", "Type", "Value"}}
+	for _, param := range params {
+		value := textCell(fmt.Sprint(param.Value))
+		if param.ParameterType == "address" {
+			value = b.addressCell(page, base.HexToAddress(fmt.Sprint(param.Value)))
+		}
+		table.Rows = append(table.Rows, []pageCell{textCell(param.Name), textCell(param.ParameterType), value})
+	}
+	return table
+}
+
+func parameterString(params []types.SimpleParameter) string {
+	values := make([]string, 0, len(params))
+	for _, param := range params {
+		values = append(values, fmt.Sprintf("%s=%v", param.Name, param.Value))
+	}
+	return strings.Join(values, " ")
+}
+
+func hashStrings(hashes []base.Hash) []string {
+	ret := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		ret = append(ret, hash.Hex())
+	}
+	return ret
+}
+
+func textCell(text string) pageCell {
+	return pageCell{Text: text}
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package explorePkg
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+)
+
+// pageLinker returns the link to the page of the given kind and id or an empty string if that
+// page is not available (in which case the value is shown without a link)
+type pageLinker func(kind, id string) string
+
+// renderHtml writes the page as a self-contained HTML document. Everything the page needs
+// (including its styles) is inside the document, so it may be viewed with no internet access.
+func renderHtml(w io.Writer, page *explorePage, linker pageLinker) error {
+	view := struct {
+		*explorePage
+		Index  string
+		Fields []pageField
+		Tables []pageTable
+	}{explorePage: page, Index: linker("", "")}
+
+	for _, field := range page.Fields {
+		field.Value.Link = field.Value.link(linker)
+		view.Fields = append(view.Fields, field)
+	}
+	for _, table := range page.Tables {
+		rows := make([][]pageCell, 0, len(table.Rows))
+		for _, row := range table.Rows {
+			linked := make([]pageCell, 0, len(row))
+			for _, cell := range row {
+				cell.Link = cell.link(linker)
+				linked = append(linked, cell)
+			}
+			rows = append(rows, linked)
+		}
+		table.Rows = rows
+		view.Tables = append(view.Tables, table)
+	}
+	return pageTemplate.Execute(w, view)
+}
+
+// renderJson writes the page's data as an indented JSON document
+func renderJson(w io.Writer, page *explorePage) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(page)
+}
+
+// link returns the link to the page the cell refers to, if any
+func (c pageCell) link(linker pageLinker) string {
+	if len(c.Kind) == 0 || len(c.Id) == 0 {
+		return ""
+	}
+	return linker(c.Kind, c.Id)
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} ({{.Chain}})</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; background: #fafafa; }
+h1 { font-size: 1.3em; word-break: break-all; }
+h2 { font-size: 1.1em; margin-top: 2em; }
+table { border-collapse: collapse; background: #fff; }
+th, td { text-align: left; padding: 0.3em 0.8em; border-bottom: 1px solid #e4e4e4; vertical-align: top; }
+th { background: #f0f0f0; }
+td { font-family: Menlo, Consolas, monospace; font-size: 0.9em; word-break: break-all; }
+.fields th { width: 10em; }
+.label { color: #666; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; }
+a { color: #0b5cad; text-decoration: none; }
+a:hover { text-decoration: underline; }
+nav, footer { font-size: 0.85em; color: #666; }
+</style>
+</head>
+<body>
+{{- define "cell"}}{{if .Link}}<a href="{{.Link}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{with .Label}} <span class="label">({{.}})</span>{{end}}{{end}}
+{{with .Index}}<nav><a href="{{.}}">Index</a></nav>{{end}}
+<h1>{{.Title}}</h1>
+{{- with .Fields}}
+<table class="fields">
+{{- range .}}
+<tr><th>{{.Label}}</th><td>{{template "cell" .Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .Tables}}
+<h2>{{.Title}} ({{len .Rows}})</h2>
+<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{template "cell" .}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+<footer><p>Chain: {{.Chain}}. Generated by chifra explore.</p></footer>
+</body>
+</html>
+`))
@@ -0,0 +1,74 @@
+package explorePkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderPage(t *testing.T) {
+	page := &explorePage{
+		Kind:  pageTx,
+		Id:    "0xabc",
+		Chain: "mainnet",
+		Data:  map[string]string{"hash": "0xabc"},
+		Title: "Transaction 0xabc",
+		Fields: []pageField{
+			{"Block", pageCell{Text: "12.3", Kind: pageBlock, Id: "0xb10c"}},
+			{"From", pageCell{Text: "0xf00", Kind: pageAddress, Id: "0xf00", Label: "<Foo>"}},
+		},
+		Tables: []pageTable{{
+			Title:   "Logs",
+			Columns: []string{"Index", "Address"},
+			Rows:    [][]pageCell{{textCell("0"), {Text: "0xf00", Kind: pageAddress, Id: "0xf00"}}},
+		}},
+	}
+
+	// Only the block's page is available
+	linker := func(kind, id string) string {
+		if len(kind) == 0 {
+			return "index.html"
+		}
+		if kind == pageBlock {
+			return pageFileName(kind, id) + ".html"
+		}
+		return ""
+	}
+
+	var html bytes.Buffer
+	if err := renderHtml(&html, page, linker); err != nil {
+		t.Fatal(err)
+	}
+	got := html.String()
+	for _, expected := range []string{
+		`<title>Transaction 0xabc (mainnet)</title>`,
+		`<a href="block-0xb10c.html">12.3</a>`,
+		`<a href="index.html">Index</a>`,
+		`<span class="label">(&lt;Foo&gt;)</span>`,
+		`<h2>Logs (1)</h2>`,
+		`<td>0xf00</td>`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected the page to contain %s", expected)
+		}
+	}
+	if strings.Contains(got, "http://") || strings.Contains(got, "https://") {
+		t.Error("expected the page to be self-contained")
+	}
+	if page.Fields[0].Value.Link != "" {
+		t.Error("expected rendering not to modify the page")
+	}
+
+	var data bytes.Buffer
+	if err := renderJson(&data, page); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["kind"] != pageTx || decoded["id"] != "0xabc" || decoded["data"] == nil || decoded["fields"] != nil {
+		t.Error("unexpected JSON", data.String())
+	}
+}
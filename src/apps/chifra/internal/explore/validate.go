@@ -43,6 +43,25 @@ func (opts *ExploreOptions) validateExplore() error {
 		return validate.Usage("The {0} option is not available{1}.", "--local", " with the --google option")
 	}
 
+	mode := ""
+	if len(opts.Render) > 0 {
+		mode = "--render"
+		if opts.Serve {
+			return validate.Usage("The {0} option is not available{1}.", "--serve", " with the --render option")
+		}
+	} else if opts.Serve {
+		mode = "--serve"
+	}
+
+	if len(mode) > 0 {
+		if opts.Local {
+			return validate.Usage("The {0} option is not available{1}.", "--local", " with the "+mode+" option")
+		}
+		if opts.Google {
+			return validate.Usage("The {0} option is not available{1}.", "--google", " with the "+mode+" option")
+		}
+	}
+
 	for _, arg := range opts.Terms {
 		arg = strings.ToLower(arg)
 
@@ -95,6 +114,14 @@ func (opts *ExploreOptions) validateExplore() error {
 		urls = append(urls, ExploreUrl{"", ExploreNone})
 	}
 
+	if len(mode) > 0 {
+		for _, url := range urls {
+			if len(url.pageKind()) == 0 || len(url.term) == 0 {
+				return validate.Usage("The {0} option requires {1}.", mode, "address, block, or transaction terms")
+			}
+		}
+	}
+
 	return opts.Globals.Validate()
 }
 
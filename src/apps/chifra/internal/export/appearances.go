@@ -0,0 +1,67 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package exportPkg
+
+import (
+	"sort"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// readAppearances returns the monitor's appearances in block order honoring --first_block,
+// --last_block, --first_record, and --max_records. It is shared by the exports handled here.
+func (opts *ExportOptions) readAppearances(mon *monitor.Monitor) ([]index.AppearanceRecord, error) {
+	apps := make([]index.AppearanceRecord, mon.Count())
+	if err := mon.ReadAppearances(&apps); err != nil {
+		return nil, err
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].BlockNumber != apps[j].BlockNumber {
+			return apps[i].BlockNumber < apps[j].BlockNumber
+		}
+		return apps[i].TransactionId < apps[j].TransactionId
+	})
+
+	maxRecords := opts.MaxRecords
+	if maxRecords == 250 && !opts.Globals.IsApiMode() {
+		maxRecords = utils.NOPOS
+	}
+
+	exportRange := base.FileRange{First: opts.FirstBlock, Last: opts.LastBlock}
+	ret := make([]index.AppearanceRecord, 0, len(apps))
+	nSeen := uint64(0)
+	for _, app := range apps {
+		appRange := base.FileRange{First: uint64(app.BlockNumber), Last: uint64(app.BlockNumber)}
+		if !appRange.Intersects(exportRange) {
+			continue
+		}
+		nSeen++
+		if nSeen < opts.FirstRecord {
+			continue
+		}
+		if uint64(len(ret)) >= maxRecords {
+			break
+		}
+		ret = append(ret, app)
+	}
+	return ret, nil
+}
+
+// specialReason returns the reason for an appearance that is not a transaction (a block or uncle
+// reward, or a genesis allocation), or the empty string for a regular transaction
+func specialReason(app index.AppearanceRecord) string {
+	switch {
+	case app.BlockNumber == 0:
+		return "genesis"
+	case app.TransactionId == 99998:
+		return "uncle"
+	case app.TransactionId > 99995: // leave this here for searching: 99999
+		return "miner"
+	}
+	return ""
+}
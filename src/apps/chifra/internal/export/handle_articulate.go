@@ -0,0 +1,125 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package exportPkg
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	listPkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/list"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// HandleArticulate exports the articulated transactions in which the addresses appear, including
+// the calls nested in wrapper functions such as multicall (see articulate.ArticulateNested). A
+// transaction in which more than one of the addresses appears is exported once.
+func (opts *ExportOptions) HandleArticulate() error {
+	listOpts := listPkg.ListOptions{
+		Addrs:   opts.Addrs,
+		Silent:  true,
+		Globals: opts.Globals,
+	}
+
+	monitorArray := make([]monitor.Monitor, 0, len(opts.Addrs))
+	if canceled, err := listOpts.HandleFreshenMonitors(&monitorArray); err != nil || canceled {
+		return err
+	}
+
+	apps := []index.AppearanceRecord{}
+	for _, mon := range monitorArray {
+		monApps, err := opts.readAppearances(&mon)
+		mon.Close()
+		if err != nil {
+			return err
+		}
+		apps = append(apps, monApps...)
+	}
+	apps = uniqueAppearances(apps)
+	if opts.Reversed {
+		for i, j := 0, len(apps)-1; i < j; i, j = i+1, j-1 {
+			apps[i], apps[j] = apps[j], apps[i]
+		}
+	}
+
+	chain := opts.Globals.Chain
+	abiMap := make(abi.AbiInterfaceMap)
+	loadedMap := make(abi.LoadedAbis)
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawTransaction], errorChan chan error) {
+		for _, app := range apps {
+			raw := types.RawAppearance{BlockNumber: app.BlockNumber, TransactionIndex: app.TransactionId}
+			tx, err := rpcClient.GetTransactionByAppearance(chain, &raw, false)
+			if err != nil {
+				errorChan <- err
+				continue
+			}
+			if !opts.isExportedFourbyte(tx) {
+				continue
+			}
+
+			for _, err := range articulate.ArticulateTransaction(chain, tx, abiMap, loadedMap) {
+				// continue processing even with an error
+				errorChan <- err
+			}
+			modelChan <- tx
+		}
+	}
+
+	extra := map[string]interface{}{
+		"articulate": true,
+	}
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
+}
+
+// isArticulatedExport returns true if the transactions are to be articulated and nothing else
+// (such as other record types, accounting, caching, filtering other than --fourbytes, or value
+// formatting) was asked for. Only such exports are handled here, the rest are passed on.
+func (opts *ExportOptions) isArticulatedExport() bool {
+	return opts.Articulate &&
+		!opts.Appearances && !opts.Receipts && !opts.Logs && !opts.Traces &&
+		!opts.Transfers && !opts.Neighbors && !opts.Accounting && !opts.Statements &&
+		!opts.Cache && !opts.CacheTraces && !opts.Count && !opts.Ens && !opts.Relevant &&
+		len(opts.Emitter) == 0 && len(opts.Topic) == 0 && len(opts.Topics) == 0 &&
+		len(opts.Asset) == 0 && len(opts.Flow) == 0 &&
+		!opts.Globals.Ether && !opts.Globals.Wei &&
+		!opts.Factory && !opts.Unripe && len(opts.Load) == 0
+}
+
+// isExportedFourbyte returns true if there are no --fourbytes or the transaction calls one of them
+func (opts *ExportOptions) isExportedFourbyte(tx *types.SimpleTransaction) bool {
+	if len(opts.Fourbytes) == 0 {
+		return true
+	}
+	for _, fourbyte := range opts.Fourbytes {
+		if strings.HasPrefix(strings.ToLower(tx.Input), strings.ToLower(fourbyte)) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueAppearances sorts the appearances removing duplicates
+func uniqueAppearances(apps []index.AppearanceRecord) []index.AppearanceRecord {
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].BlockNumber != apps[j].BlockNumber {
+			return apps[i].BlockNumber < apps[j].BlockNumber
+		}
+		return apps[i].TransactionId < apps[j].TransactionId
+	})
+	ret := apps[:0]
+	for i, app := range apps {
+		if i == 0 || app != apps[i-1] {
+			ret = append(ret, app)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,49 @@
+package exportPkg
+
+import (
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestUniqueAppearances(t *testing.T) {
+	apps := []index.AppearanceRecord{{BlockNumber: 20, TransactionId: 1}, {BlockNumber: 10, TransactionId: 3}, {BlockNumber: 20, TransactionId: 1}, {BlockNumber: 10, TransactionId: 2}}
+	got := uniqueAppearances(apps)
+	expected := []index.AppearanceRecord{{BlockNumber: 10, TransactionId: 2}, {BlockNumber: 10, TransactionId: 3}, {BlockNumber: 20, TransactionId: 1}}
+	if len(got) != len(expected) {
+		t.Fatal("unexpected appearances", got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Error("unexpected appearance", i, got[i])
+		}
+	}
+}
+
+func TestIsArticulatedExport(t *testing.T) {
+	opts := ExportOptions{Articulate: true, Fourbytes: []string{"0xA9059CBB"}}
+	if !opts.isArticulatedExport() {
+		t.Error("expected an articulated transaction export")
+	}
+	if !opts.isExportedFourbyte(&types.SimpleTransaction{Input: "0xa9059cbb0000"}) || opts.isExportedFourbyte(&types.SimpleTransaction{Input: "0x095ea7b3"}) {
+		t.Error("unexpected --fourbytes filtering")
+	}
+
+	opts.Logs = true
+	if opts.isArticulatedExport() {
+		t.Error("did not expect articulated logs to be handled in Go")
+	}
+
+	opts.Logs = false
+	opts.Emitter = []string{"0x7d655c57f71464b6f83811c55d84009cd9f5221c"}
+	if opts.isArticulatedExport() {
+		t.Error("did not expect --emitter to be handled in Go")
+	}
+
+	opts.Emitter = nil
+	opts.Globals.Ether = true
+	if opts.isArticulatedExport() {
+		t.Error("did not expect --ether to be handled in Go")
+	}
+}
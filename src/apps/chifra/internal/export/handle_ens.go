@@ -0,0 +1,26 @@
+package exportPkg
+
+import (
+	listPkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/list"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+)
+
+// HandleEnsAppearances exports the appearances of the addresses along with their primary ENS
+// names. The (not yet ported) export tool knows nothing of ENS, so we list the appearances here.
+func (opts *ExportOptions) HandleEnsAppearances() error {
+	listOpts := listPkg.ListOptions{
+		Addrs:       opts.Addrs,
+		FirstRecord: opts.FirstRecord,
+		MaxRecords:  opts.MaxRecords,
+		FirstBlock:  opts.FirstBlock,
+		LastBlock:   opts.LastBlock,
+		Ens:         true,
+		Globals:     opts.Globals,
+	}
+
+	monitorArray := make([]monitor.Monitor, 0, len(opts.Addrs))
+	if canceled, err := listOpts.HandleFreshenMonitors(&monitorArray); err != nil || canceled {
+		return err
+	}
+	return listOpts.HandleListAppearances(monitorArray)
+}
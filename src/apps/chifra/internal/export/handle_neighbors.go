@@ -0,0 +1,194 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package exportPkg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	listPkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/list"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/uniq"
+)
+
+// HandleNeighbors exports the neighbor graph of the addresses, that is, every address that appears
+// in a transaction in which one of the addresses appears. Addresses are found using the same rules
+// the scraper uses to build the index. With --hops, the neighbors' own monitors (if they exist)
+// are walked in the same way. With --graph, the whole graph is written as a single document.
+// Plain --neighbors (one row per appearance) is handled by acctExport.
+func (opts *ExportOptions) HandleNeighbors() error {
+	listOpts := listPkg.ListOptions{
+		Addrs:   opts.Addrs,
+		Silent:  true,
+		Globals: opts.Globals,
+	}
+
+	monitorArray := make([]monitor.Monitor, 0, len(opts.Addrs))
+	if canceled, err := listOpts.HandleFreshenMonitors(&monitorArray); err != nil || canceled {
+		return err
+	}
+
+	graph, err := opts.buildNeighborGraph(monitorArray)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Graph) > 0 {
+		return graph.write(opts.Globals.Writer, opts.Graph)
+	}
+
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
+		for _, edge := range graph.sortedEdges() {
+			modelChan <- &simpleNeighbor{
+				Address:  edge.From,
+				Neighbor: edge.To,
+				Name:     graph.nodes[edge.To].Name,
+				Hop:      graph.nodes[edge.To].Hop,
+				Count:    edge.Weight(),
+				Reasons:  edge.sortedReasons(),
+			}
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
+
+// buildNeighborGraph walks the monitors' appearances (and, for more than one hop, the
+// appearances of any neighbor that is already monitored) adding each address found to the graph
+func (opts *ExportOptions) buildNeighborGraph(monitorArray []monitor.Monitor) (*neighborGraph, error) {
+	chain := opts.Globals.Chain
+	nameOf := func(address base.Address) string {
+		if name, ok := names.FindName(chain, address); ok {
+			return name.Name
+		}
+		return ""
+	}
+
+	var monitorMap map[base.Address]*monitor.Monitor
+	if opts.Hops > 1 {
+		monitorMap, _ = monitor.GetMonitorMap(chain)
+	}
+
+	graph := newNeighborGraph()
+	frontier := make([]*monitor.Monitor, 0, len(monitorArray))
+	for i := range monitorArray {
+		if graph.addNode(monitorArray[i].Address, nameOf(monitorArray[i].Address), 0) {
+			frontier = append(frontier, &monitorArray[i])
+		}
+	}
+
+	extractor := neighborExtractor{
+		chain:      chain,
+		withTraces: rpcClient.IsTracingNode(opts.Globals.TestMode, chain),
+		found:      map[string]map[base.Address][]string{},
+	}
+	for hop := uint64(1); hop <= opts.Hops && len(frontier) > 0; hop++ {
+		next := []*monitor.Monitor{}
+		for _, mon := range frontier {
+			apps, err := opts.readAppearances(mon)
+			mon.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, app := range apps {
+				logger.Progress(!opts.Globals.TestMode, "Extracting neighbors of", mon.Address.Hex(), "at", app.BlockNumber, ".", app.TransactionId)
+				found, err := extractor.extract(mon.Address, app)
+				if err != nil {
+					return nil, err
+				}
+
+				appKey := fmt.Sprintf("%d.%d", app.BlockNumber, app.TransactionId)
+				for address, reasons := range found {
+					if address == mon.Address {
+						continue
+					}
+					if graph.addNode(address, nameOf(address), hop) && hop < opts.Hops {
+						if neighborMon, ok := monitorMap[address]; ok && neighborMon.Count() > 0 {
+							next = append(next, neighborMon)
+						}
+					}
+					graph.addEdge(mon.Address, address, appKey, reasons)
+				}
+			}
+		}
+		sort.Slice(next, func(i, j int) bool {
+			return next[i].Address.Hex() < next[j].Address.Hex()
+		})
+		frontier = next
+	}
+
+	return graph, nil
+}
+
+// neighborExtractor finds the addresses in the transaction of an appearance along with the
+// reasons each was found. A transaction shared by more than one walked address is only
+// queried once.
+type neighborExtractor struct {
+	chain      string
+	withTraces bool
+	found      map[string]map[base.Address][]string
+}
+
+// extract returns the addresses found in the appearance's transaction. Block rewards and genesis
+// allocations have no transaction, so the only address found for them is the walked address itself.
+func (e *neighborExtractor) extract(address base.Address, app index.AppearanceRecord) (map[base.Address][]string, error) {
+	if reason := specialReason(app); reason != "" {
+		return map[base.Address][]string{address: {reason}}, nil
+	}
+
+	key := fmt.Sprintf("%d.%d", app.BlockNumber, app.TransactionId)
+	if found, ok := e.found[key]; ok {
+		return found, nil
+	}
+
+	raw := types.RawAppearance{BlockNumber: app.BlockNumber, TransactionIndex: app.TransactionId}
+	tx, err := rpcClient.GetTransactionByAppearance(e.chain, &raw, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.withTraces {
+		if traces, err := rpcClient.GetTracesByTransactionHash(e.chain, tx.Hash.Hex(), tx); err != nil {
+			// The node may not provide traces. Carry on without them.
+			logger.Warn("Could not get traces for", key, "continuing without traces:", err)
+			e.withTraces = false
+		} else {
+			tx.Traces = traces
+		}
+	}
+
+	reasonMap := map[base.Address]map[string]bool{}
+	uniq.UniqFromTransaction(tx, func(address base.Address, reason string) {
+		if reasonMap[address] == nil {
+			reasonMap[address] = map[string]bool{}
+		}
+		reasonMap[address][reason] = true
+	})
+
+	found := make(map[base.Address][]string, len(reasonMap))
+	for address, reasons := range reasonMap {
+		for reason := range reasons {
+			found[address] = append(found[address], reason)
+		}
+	}
+	e.found[key] = found
+	return found, nil
+}
+
+// isNeighborGraph returns true if the neighbors are to be walked as a graph (that is, with more
+// than one hop or with --graph). Only such exports are handled here, the rest are passed on.
+func (opts *ExportOptions) isNeighborGraph() bool {
+	return opts.Neighbors && (opts.Hops > 1 || len(opts.Graph) > 0)
+}
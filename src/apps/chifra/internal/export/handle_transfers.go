@@ -0,0 +1,114 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package exportPkg
+
+import (
+	"context"
+
+	listPkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/list"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/transfer"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// HandleTransfers exports the transfers of ETH and tokens to or from each address in the
+// transactions in which it appears. The --asset and --flow options limit the transfers to
+// the given assets and to incoming, outgoing, or zero-valued transfers respectively. Block rewards
+// and genesis allocations are not reported.
+func (opts *ExportOptions) HandleTransfers() error {
+	listOpts := listPkg.ListOptions{
+		Addrs:   opts.Addrs,
+		Silent:  true,
+		Globals: opts.Globals,
+	}
+
+	monitorArray := make([]monitor.Monitor, 0, len(opts.Addrs))
+	if canceled, err := listOpts.HandleFreshenMonitors(&monitorArray); err != nil || canceled {
+		return err
+	}
+
+	chain := opts.Globals.Chain
+	findName := func(addr base.Address) (types.SimpleName, bool) {
+		return names.FindName(chain, addr)
+	}
+
+	ethSymbol := config.GetSymbol(chain)
+	if len(ethSymbol) == 0 {
+		ethSymbol = "ETH"
+	}
+
+	assets := make(map[base.Address]bool, len(opts.Asset))
+	for _, asset := range opts.Asset {
+		assets[base.HexToAddress(asset)] = true
+	}
+
+	tokens := transfer.NewTokenCache(chain)
+	withTraces := rpcClient.IsTracingNode(opts.Globals.TestMode, chain)
+	if !withTraces {
+		logger.Warn("The node does not provide traces, so ETH transfers made by contracts will be missing.")
+	}
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawTransfer], errorChan chan error) {
+		for _, mon := range monitorArray {
+			apps, err := opts.readAppearances(&mon)
+			mon.Close()
+			if err != nil {
+				errorChan <- err
+				return
+			}
+
+			for _, app := range apps {
+				if specialReason(app) != "" {
+					// Block rewards and genesis allocations have no transaction to query
+					continue
+				}
+
+				raw := types.RawAppearance{BlockNumber: app.BlockNumber, TransactionIndex: app.TransactionId}
+				tx, err := rpcClient.GetTransactionByAppearance(chain, &raw, withTraces)
+				if err != nil {
+					errorChan <- err
+					continue
+				}
+
+				transfers := transfer.FromTransaction(tx, ethSymbol, findName)
+				tokens.Describe(transfers)
+				for _, t := range transfers {
+					t := t
+					if opts.isExportedTransfer(&t, mon.Address, assets) {
+						modelChan <- &t
+					}
+				}
+			}
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
+
+// isExportedTransfer returns true if the transfer is to or from the address and passes the
+// --asset and --flow filters
+func (opts *ExportOptions) isExportedTransfer(t *types.SimpleTransfer, address base.Address, assets map[base.Address]bool) bool {
+	if t.Sender != address && t.Recipient != address {
+		return false
+	}
+	if len(assets) > 0 && !assets[t.AssetAddr] {
+		return false
+	}
+	switch opts.Flow {
+	case "in":
+		return t.Recipient == address
+	case "out":
+		return t.Sender == address
+	case "zero":
+		return t.Amount.Sign() == 0
+	}
+	return true
+}
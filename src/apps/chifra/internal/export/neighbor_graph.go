@@ -0,0 +1,288 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package exportPkg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+)
+
+// neighborNode is an address in the graph. Hop is zero for the exported addresses, one for their
+// neighbors, two for the neighbors' neighbors, and so on.
+type neighborNode struct {
+	Address base.Address
+	Name    string
+	Hop     uint64
+}
+
+// neighborEdge connects an address to a neighbor. Its weight is the number of transactions in
+// which the two appear together. Reasons are the parts of those transactions in which the
+// neighbor (To) appears (for example `from` or `log_topic`).
+type neighborEdge struct {
+	From    base.Address
+	To      base.Address
+	Reasons map[string]bool
+	apps    map[string]bool
+}
+
+func (e *neighborEdge) Weight() uint64 {
+	return uint64(len(e.apps))
+}
+
+func (e *neighborEdge) sortedReasons() []string {
+	reasons := make([]string, 0, len(e.Reasons))
+	for reason := range e.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// neighborGraph is an undirected, weighted graph of addresses that appear in the same transactions
+type neighborGraph struct {
+	nodes map[base.Address]*neighborNode
+	edges map[string]*neighborEdge
+}
+
+func newNeighborGraph() *neighborGraph {
+	return &neighborGraph{
+		nodes: map[base.Address]*neighborNode{},
+		edges: map[string]*neighborEdge{},
+	}
+}
+
+// addNode adds the address at the given hop if it's not already in the graph. Returns true if
+// the address was added.
+func (g *neighborGraph) addNode(address base.Address, name string, hop uint64) bool {
+	if _, ok := g.nodes[address]; ok {
+		return false
+	}
+	g.nodes[address] = &neighborNode{Address: address, Name: name, Hop: hop}
+	return true
+}
+
+// addEdge records that the two addresses appear in the same transaction (identified by app). The
+// same pair of addresses found in the same transaction from either side is counted only once. The
+// edge's reasons are those of its To address, so reasons found from the other side are ignored.
+func (g *neighborGraph) addEdge(from, to base.Address, app string, reasons []string) {
+	key := edgeKey(from, to)
+	edge, ok := g.edges[key]
+	if !ok {
+		edge = &neighborEdge{From: from, To: to, Reasons: map[string]bool{}, apps: map[string]bool{}}
+		g.edges[key] = edge
+	}
+	edge.apps[app] = true
+	if edge.To == to {
+		for _, reason := range reasons {
+			edge.Reasons[reason] = true
+		}
+	}
+}
+
+func edgeKey(a, b base.Address) string {
+	aHex, bHex := a.Hex(), b.Hex()
+	if aHex > bHex {
+		aHex, bHex = bHex, aHex
+	}
+	return aHex + "-" + bHex
+}
+
+// sortedNodes returns the nodes closest first, then by address
+func (g *neighborGraph) sortedNodes() []*neighborNode {
+	nodes := make([]*neighborNode, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Hop != nodes[j].Hop {
+			return nodes[i].Hop < nodes[j].Hop
+		}
+		return nodes[i].Address.Hex() < nodes[j].Address.Hex()
+	})
+	return nodes
+}
+
+// sortedEdges returns the edges closest first, then heaviest first, then by address
+func (g *neighborGraph) sortedEdges() []*neighborEdge {
+	edges := make([]*neighborEdge, 0, len(g.edges))
+	for _, edge := range g.edges {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		hi, hj := g.nodes[edges[i].From].Hop, g.nodes[edges[j].From].Hop
+		if hi != hj {
+			return hi < hj
+		}
+		if edges[i].From != edges[j].From {
+			return edges[i].From.Hex() < edges[j].From.Hex()
+		}
+		if edges[i].Weight() != edges[j].Weight() {
+			return edges[i].Weight() > edges[j].Weight()
+		}
+		return edges[i].To.Hex() < edges[j].To.Hex()
+	})
+	return edges
+}
+
+// write writes the graph in the given format (one of graphml, dot, or json)
+func (g *neighborGraph) write(w io.Writer, format string) error {
+	switch format {
+	case "graphml":
+		return g.writeGraphMl(w)
+	case "dot":
+		return g.writeDot(w)
+	case "json":
+		return g.writeJsonGraph(w)
+	}
+	return fmt.Errorf("unknown graph format %s", format)
+}
+
+// writeGraphMl writes the graph as a GraphML document (http://graphml.graphdrawing.org)
+func (g *neighborGraph) writeGraphMl(w io.Writer) error {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type key struct {
+		Id   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	type node struct {
+		Id   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+	type edge struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+	type graph struct {
+		Id          string `xml:"id,attr"`
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []node `xml:"node"`
+		Edges       []edge `xml:"edge"`
+	}
+	doc := struct {
+		XMLName xml.Name `xml:"graphml"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Keys    []key    `xml:"key"`
+		Graph   graph    `xml:"graph"`
+	}{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []key{
+			{Id: "name", For: "node", Name: "name", Type: "string"},
+			{Id: "hop", For: "node", Name: "hop", Type: "long"},
+			{Id: "weight", For: "edge", Name: "weight", Type: "long"},
+			{Id: "reasons", For: "edge", Name: "reasons", Type: "string"},
+		},
+		Graph: graph{Id: "neighbors", EdgeDefault: "undirected"},
+	}
+
+	for _, n := range g.sortedNodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{
+			Id: n.Address.Hex(),
+			Data: []data{
+				{Key: "name", Value: n.Name},
+				{Key: "hop", Value: strconv.FormatUint(n.Hop, 10)},
+			},
+		})
+	}
+	for _, e := range g.sortedEdges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, edge{
+			Source: e.From.Hex(),
+			Target: e.To.Hex(),
+			Data: []data{
+				{Key: "weight", Value: strconv.FormatUint(e.Weight(), 10)},
+				{Key: "reasons", Value: strings.Join(e.sortedReasons(), "|")},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeDot writes the graph in Graphviz's DOT language
+func (g *neighborGraph) writeDot(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("graph neighbors {\n")
+	for _, n := range g.sortedNodes() {
+		label := n.Address.Hex()
+		if len(n.Name) > 0 {
+			label = n.Name + "\n" + label
+		}
+		sb.WriteString(fmt.Sprintf("  %s [label=%s, hop=%d];\n", strconv.Quote(n.Address.Hex()), strconv.Quote(label), n.Hop))
+	}
+	for _, e := range g.sortedEdges() {
+		sb.WriteString(fmt.Sprintf("  %s -- %s [weight=%d, label=\"%d\", reasons=%s];\n",
+			strconv.Quote(e.From.Hex()), strconv.Quote(e.To.Hex()), e.Weight(), e.Weight(), strconv.Quote(strings.Join(e.sortedReasons(), "|"))))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeJsonGraph writes the graph as a JSON Graph Format document (https://jsongraphformat.info)
+func (g *neighborGraph) writeJsonGraph(w io.Writer) error {
+	type nodeMetadata struct {
+		Hop uint64 `json:"hop"`
+	}
+	type node struct {
+		Label    string       `json:"label,omitempty"`
+		Metadata nodeMetadata `json:"metadata"`
+	}
+	type edgeMetadata struct {
+		Weight  uint64   `json:"weight"`
+		Reasons []string `json:"reasons"`
+	}
+	type edge struct {
+		Source   string       `json:"source"`
+		Target   string       `json:"target"`
+		Metadata edgeMetadata `json:"metadata"`
+	}
+	type graph struct {
+		Id       string          `json:"id"`
+		Directed bool            `json:"directed"`
+		Nodes    map[string]node `json:"nodes"`
+		Edges    []edge          `json:"edges"`
+	}
+
+	doc := struct {
+		Graph graph `json:"graph"`
+	}{
+		Graph: graph{Id: "neighbors", Nodes: map[string]node{}, Edges: []edge{}},
+	}
+	for _, n := range g.sortedNodes() {
+		doc.Graph.Nodes[n.Address.Hex()] = node{Label: n.Name, Metadata: nodeMetadata{Hop: n.Hop}}
+	}
+	for _, e := range g.sortedEdges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, edge{
+			Source:   e.From.Hex(),
+			Target:   e.To.Hex(),
+			Metadata: edgeMetadata{Weight: e.Weight(), Reasons: e.sortedReasons()},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,133 @@
+package exportPkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+)
+
+func TestNeighborGraph(t *testing.T) {
+	a := base.HexToAddress("0xf503017d7baf7fbc0fff7492b751025c6a78179b")
+	b := base.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	c := base.HexToAddress("0x054993ab0f2b1acc0fdc65405ee203b4271bebe6")
+
+	g := newNeighborGraph()
+	g.addNode(a, "Alice", 0)
+	g.addNode(b, "USDC", 1)
+	g.addNode(c, "", 1)
+	if g.addNode(c, "", 2) {
+		t.Error("expected a node to be added only once")
+	}
+	g.addEdge(a, b, "1.1", []string{"to"})
+	g.addEdge(a, b, "2.1", []string{"to", "log_topic"})
+	g.addEdge(a, c, "2.1", []string{"input"})
+	// The same transaction seen from the neighbor's side counts once
+	g.addEdge(b, a, "2.1", []string{"from"})
+
+	edges := g.sortedEdges()
+	if len(edges) != 2 {
+		t.Fatal("expected two edges, got", len(edges))
+	}
+	if edges[0].To != b || edges[0].Weight() != 2 || strings.Join(edges[0].sortedReasons(), "|") != "log_topic|to" {
+		t.Error("unexpected first edge", edges[0].To.Hex(), edges[0].Weight(), edges[0].sortedReasons())
+	}
+	if edges[1].To != c || edges[1].Weight() != 1 {
+		t.Error("unexpected second edge", edges[1].To.Hex(), edges[1].Weight())
+	}
+
+	var dot bytes.Buffer
+	if err := g.write(&dot, "dot"); err != nil {
+		t.Fatal(err)
+	}
+	expected := `"0xf503017d7baf7fbc0fff7492b751025c6a78179b" -- "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48" [weight=2, label="2", reasons="log_topic|to"];`
+	if !strings.HasPrefix(dot.String(), "graph neighbors {\n") || !strings.Contains(dot.String(), expected) {
+		t.Error("unexpected DOT", dot.String())
+	}
+
+	var graphMl bytes.Buffer
+	if err := g.write(&graphMl, "graphml"); err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Nodes []struct {
+			Id string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+		} `xml:"graph>edge"`
+	}
+	if err := xml.Unmarshal(graphMl.Bytes(), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Nodes) != 3 || len(parsed.Edges) != 2 || parsed.Nodes[0].Id != a.Hex() {
+		t.Error("unexpected GraphML", graphMl.String())
+	}
+
+	var jsonGraph bytes.Buffer
+	if err := g.write(&jsonGraph, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Graph struct {
+			Nodes map[string]map[string]any `json:"nodes"`
+			Edges []map[string]any          `json:"edges"`
+		} `json:"graph"`
+	}
+	if err := json.Unmarshal(jsonGraph.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Graph.Nodes) != 3 || len(decoded.Graph.Edges) != 2 || decoded.Graph.Nodes[b.Hex()]["label"] != "USDC" {
+		t.Error("unexpected JSON graph", jsonGraph.String())
+	}
+
+	if err := g.write(&jsonGraph, "csv"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestNeighborSpecialAppearances(t *testing.T) {
+	a := base.HexToAddress("0x05a56e2d52c817161883f50c441c3228cfe54d9f")
+	e := neighborExtractor{found: map[string]map[base.Address][]string{}}
+	tests := []struct {
+		app    index.AppearanceRecord
+		reason string
+	}{
+		{index.AppearanceRecord{BlockNumber: 0, TransactionId: 8888}, "genesis"},
+		{index.AppearanceRecord{BlockNumber: 1, TransactionId: 99999}, "miner"},
+		{index.AppearanceRecord{BlockNumber: 5, TransactionId: 99998}, "uncle"},
+		{index.AppearanceRecord{BlockNumber: 5, TransactionId: 99996}, "miner"},
+	}
+	for _, test := range tests {
+		// None of these may reach the node
+		found, err := e.extract(a, test.app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || len(found[a]) != 1 || found[a][0] != test.reason {
+			t.Error("unexpected neighbors", test.app, found)
+		}
+	}
+	if specialReason(index.AppearanceRecord{BlockNumber: 5, TransactionId: 12}) != "" {
+		t.Error("expected a regular transaction to have no special reason")
+	}
+}
+
+func TestIsNeighborGraph(t *testing.T) {
+	opts := ExportOptions{Neighbors: true, Hops: 1}
+	if opts.isNeighborGraph() {
+		t.Error("expected plain --neighbors to be passed on")
+	}
+	opts.Hops = 2
+	if !opts.isNeighborGraph() {
+		t.Error("expected --hops 2 to be handled in Go")
+	}
+	opts.Hops, opts.Graph = 1, "dot"
+	if !opts.isNeighborGraph() {
+		t.Error("expected --graph to be handled in Go")
+	}
+}
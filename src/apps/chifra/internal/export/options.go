@@ -29,20 +29,24 @@ type ExportOptions struct {
 	Receipts    bool                  `json:"receipts,omitempty"`    // Export receipts instead of transactional data
 	Logs        bool                  `json:"logs,omitempty"`        // Export logs instead of transactional data
 	Traces      bool                  `json:"traces,omitempty"`      // Export traces instead of transactional data
+	Transfers   bool                  `json:"transfers,omitempty"`   // Export the transfers of ETH and tokens to or from the given address(es)
 	Neighbors   bool                  `json:"neighbors,omitempty"`   // Export the neighbors of the given address
+	Hops        uint64                `json:"hops,omitempty"`        // For --neighbors only, the number of hops to walk through the neighbors' existing monitors
+	Graph       string                `json:"graph,omitempty"`       // For --neighbors only, export the neighbors as a graph in this format
 	Accounting  bool                  `json:"accounting,omitempty"`  // Attach accounting records to the exported data (applies to transactions export only)
 	Statements  bool                  `json:"statements,omitempty"`  // For the accounting options only, export only statements
 	Articulate  bool                  `json:"articulate,omitempty"`  // Articulate transactions, traces, logs, and outputs
 	Cache       bool                  `json:"cache,omitempty"`       // Write transactions to the cache (see notes)
 	CacheTraces bool                  `json:"cacheTraces,omitempty"` // Write traces to the cache (see notes)
 	Count       bool                  `json:"count,omitempty"`       // Only available for --appearances mode, if present, return only the number of records
+	Ens         bool                  `json:"ens,omitempty"`         // Only available for --appearances mode, include the primary ENS name of each address in the results
 	FirstRecord uint64                `json:"firstRecord,omitempty"` // The first record to process
 	MaxRecords  uint64                `json:"maxRecords,omitempty"`  // The maximum number of records to process
 	Relevant    bool                  `json:"relevant,omitempty"`    // For log and accounting export only, export only logs relevant to one of the given export addresses
 	Emitter     []string              `json:"emitter,omitempty"`     // For log export only, export only logs if emitted by one of these address(es)
 	Topic       []string              `json:"topic,omitempty"`       // For log export only, export only logs with this topic(s)
-	Asset       []string              `json:"asset,omitempty"`       // For the accounting options only, export statements only for this asset
-	Flow        string                `json:"flow,omitempty"`        // For the accounting options only, export statements with incoming, outgoing, or zero value
+	Asset       []string              `json:"asset,omitempty"`       // For the accounting and --transfers options only, export statements or transfers only for this asset
+	Flow        string                `json:"flow,omitempty"`        // For the accounting and --transfers options only, export statements or transfers with incoming, outgoing, or zero value
 	Factory     bool                  `json:"factory,omitempty"`     // For --traces only, report addresses created by (or self-destructed by) the given address(es)
 	Unripe      bool                  `json:"unripe,omitempty"`      // Export transactions labeled upripe (i.e. less than 28 blocks old)
 	Load        string                `json:"load,omitempty"`        // A comma separated list of dynamic traversers to load
@@ -56,6 +60,7 @@ type ExportOptions struct {
 }
 
 var defaultExportOptions = ExportOptions{
+	Hops:        1,
 	FirstRecord: 1,
 	MaxRecords:  250,
 	LastBlock:   utils.NOPOS,
@@ -70,13 +75,17 @@ func (opts *ExportOptions) testLog() {
 	logger.TestLog(opts.Receipts, "Receipts: ", opts.Receipts)
 	logger.TestLog(opts.Logs, "Logs: ", opts.Logs)
 	logger.TestLog(opts.Traces, "Traces: ", opts.Traces)
+	logger.TestLog(opts.Transfers, "Transfers: ", opts.Transfers)
 	logger.TestLog(opts.Neighbors, "Neighbors: ", opts.Neighbors)
+	logger.TestLog(opts.Hops != 1, "Hops: ", opts.Hops)
+	logger.TestLog(len(opts.Graph) > 0, "Graph: ", opts.Graph)
 	logger.TestLog(opts.Accounting, "Accounting: ", opts.Accounting)
 	logger.TestLog(opts.Statements, "Statements: ", opts.Statements)
 	logger.TestLog(opts.Articulate, "Articulate: ", opts.Articulate)
 	logger.TestLog(opts.Cache, "Cache: ", opts.Cache)
 	logger.TestLog(opts.CacheTraces, "CacheTraces: ", opts.CacheTraces)
 	logger.TestLog(opts.Count, "Count: ", opts.Count)
+	logger.TestLog(opts.Ens, "Ens: ", opts.Ens)
 	logger.TestLog(opts.FirstRecord != 1, "FirstRecord: ", opts.FirstRecord)
 	logger.TestLog(opts.MaxRecords != 250, "MaxRecords: ", opts.MaxRecords)
 	logger.TestLog(opts.Relevant, "Relevant: ", opts.Relevant)
@@ -192,6 +201,7 @@ func (opts *ExportOptions) toCmdLine() string {
 func exportFinishParseApi(w http.ResponseWriter, r *http.Request) *ExportOptions {
 	copy := defaultExportOptions
 	opts := &copy
+	opts.Hops = 1
 	opts.FirstRecord = 1
 	opts.MaxRecords = 250
 	opts.FirstBlock = 0
@@ -221,8 +231,14 @@ func exportFinishParseApi(w http.ResponseWriter, r *http.Request) *ExportOptions
 			opts.Logs = true
 		case "traces":
 			opts.Traces = true
+		case "transfers":
+			opts.Transfers = true
 		case "neighbors":
 			opts.Neighbors = true
+		case "hops":
+			opts.Hops = globals.ToUint64(value[0])
+		case "graph":
+			opts.Graph = value[0]
 		case "accounting":
 			opts.Accounting = true
 		case "statements":
@@ -235,6 +251,8 @@ func exportFinishParseApi(w http.ResponseWriter, r *http.Request) *ExportOptions
 			opts.CacheTraces = true
 		case "count":
 			opts.Count = true
+		case "ens":
+			opts.Ens = true
 		case "firstRecord":
 			opts.FirstRecord = globals.ToUint64(value[0])
 		case "maxRecords":
@@ -50,6 +50,22 @@ func (opts *ExportOptions) ExportInternal() (err error, handled bool) {
 	}
 
 	// EXISTING_CODE
+	if opts.Ens {
+		return opts.HandleEnsAppearances(), true
+	}
+
+	if opts.isNeighborGraph() {
+		return opts.HandleNeighbors(), true
+	}
+
+	if opts.Transfers {
+		return opts.HandleTransfers(), true
+	}
+
+	if opts.isArticulatedExport() {
+		return opts.HandleArticulate(), true
+	}
+
 	_, err = opts.FreshenMonitorsForExport()
 	if err != nil {
 		return err, true
@@ -78,6 +94,10 @@ func GetExportOptions(args []string, g *globals.GlobalOptions) *ExportOptions {
 
 func (opts *ExportOptions) IsPorted() (ported bool) {
 	// EXISTING_CODE
+	// Plain --neighbors (one row per appearance) is still handled by acctExport. --neighbors --graph
+	// writes a single GraphML, DOT, or JSON Graph document straight to the writer. It must not be
+	// wrapped by the JSON writer, so it reports as not ported even though it is handled in Go.
+	ported = opts.Ens || opts.Transfers || (opts.isNeighborGraph() && len(opts.Graph) == 0) || opts.isArticulatedExport()
 	// EXISTING_CODE
 	return
 }
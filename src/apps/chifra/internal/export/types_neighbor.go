@@ -0,0 +1,56 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package exportPkg
+
+import (
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// simpleNeighbor is a single edge of the neighbor graph: a neighbor of an address, how many
+// transactions the two share, and where in those transactions the neighbor appears
+type simpleNeighbor struct {
+	Address  base.Address `json:"address"`
+	Neighbor base.Address `json:"neighbor"`
+	Name     string       `json:"name,omitempty"`
+	Hop      uint64       `json:"hop"`
+	Count    uint64       `json:"count"`
+	Reasons  []string     `json:"reasons"`
+}
+
+func (s *simpleNeighbor) Raw() *types.RawModeler {
+	return nil
+}
+
+func (s *simpleNeighbor) Model(showHidden bool, format string, extraOptions map[string]any) types.Model {
+	model := map[string]interface{}{
+		"address":  s.Address,
+		"neighbor": s.Neighbor,
+		"name":     s.Name,
+		"hop":      s.Hop,
+		"count":    s.Count,
+	}
+	order := []string{
+		"address",
+		"neighbor",
+		"name",
+		"hop",
+		"count",
+		"reasons",
+	}
+
+	if format == "json" {
+		model["reasons"] = s.Reasons
+	} else {
+		model["reasons"] = strings.Join(s.Reasons, "|")
+	}
+
+	return types.Model{
+		Data:  model,
+		Order: order,
+	}
+}
@@ -51,7 +51,7 @@ func (opts *ExportOptions) validateExport() error {
 	}
 
 	if opts.Count {
-		if opts.Logs || opts.Receipts || opts.Traces || opts.Neighbors {
+		if opts.Logs || opts.Receipts || opts.Traces || opts.Neighbors || opts.Transfers {
 			return validate.Usage("The {0} option is only available with transactional options.", "--count")
 		}
 		if opts.MaxRecords > 0 && opts.MaxRecords != 250 {
@@ -59,6 +59,56 @@ func (opts *ExportOptions) validateExport() error {
 		}
 	}
 
+	if opts.Ens {
+		if !opts.Appearances {
+			return validate.Usage("The {0} option is only available with the {1} option.", "--ens", "--appearances")
+		}
+		if opts.Count {
+			return validate.Usage("The {0} option is not available with the {1} option.", "--ens", "--count")
+		}
+	}
+
+	if opts.Neighbors {
+		if opts.Appearances || opts.Logs || opts.Receipts || opts.Traces {
+			return validate.Usage("The {0} option is not available with other options.", "--neighbors")
+		}
+		if opts.Hops == 0 {
+			return validate.Usage("The {0} option ({1}) must {2}.", "--hops", "0", "be at least one")
+		}
+		if len(opts.Graph) > 0 {
+			if err := validate.ValidateEnum("--graph", opts.Graph, "[graphml|dot|json]"); err != nil {
+				return err
+			}
+			if opts.Globals.IsApiMode() {
+				return validate.Usage("The {0} option is not available in {1} mode.", "--graph", "API")
+			}
+		}
+
+	} else {
+		if opts.Hops != 1 {
+			return validate.Usage("The {0} option is only available with the {1} option.", "--hops", "--neighbors")
+		}
+		if len(opts.Graph) > 0 {
+			return validate.Usage("The {0} option is only available with the {1} option.", "--graph", "--neighbors")
+		}
+	}
+
+	if opts.Transfers {
+		if opts.Appearances || opts.Logs || opts.Receipts || opts.Traces || opts.Neighbors {
+			return validate.Usage("The {0} option is not available with other options.", "--transfers")
+		}
+		if len(opts.Flow) > 0 {
+			if err := validate.ValidateEnum("--flow", opts.Flow, "[in|out|zero]"); err != nil {
+				return err
+			}
+		}
+		for _, asset := range opts.Asset {
+			if !validate.IsValidAddress(asset) {
+				return validate.Usage("Invalid asset address {0}.", asset)
+			}
+		}
+	}
+
 	if !opts.Logs && len(opts.Emitter) > 0 {
 		return validate.Usage("The {0} option is only available with the {1} option.", "--emitter", "--logs")
 	}
@@ -80,7 +130,7 @@ func (opts *ExportOptions) validateExport() error {
 			return validate.Usage("The {0} option is allows with only a single address.", "--accounting")
 		}
 
-		if opts.Appearances || opts.Logs || opts.Receipts || opts.Traces || opts.Neighbors {
+		if opts.Appearances || opts.Logs || opts.Receipts || opts.Traces || opts.Neighbors || opts.Transfers {
 			return validate.Usage("The {0} option is not available with other options.", "--accounting")
 		}
 
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/globals"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
@@ -22,6 +23,7 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index/bloom"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/metrics"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/sigintTrap"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
@@ -41,6 +43,11 @@ type MonitorUpdate struct {
 
 const maxTestingBlock = 15000000
 
+var (
+	freshenSeconds  = metrics.NewSummary("monitors_freshen_seconds", "Time spent freshening monitors")
+	freshenMonitors = metrics.NewCounter("monitors_freshened", "Number of monitors freshened")
+)
+
 // mutexesPerAddress map stores mutex for each address that is being/has been freshened
 var mutexesPerAddress = make(map[string]*sync.Mutex)
 var mutexesPerAddressMutex sync.Mutex
@@ -71,6 +78,9 @@ func unlockForAddress(address string) {
 }
 
 func (opts *ListOptions) HandleFreshenMonitors(monitorArray *[]monitor.Monitor) (bool, error) {
+	defer freshenSeconds.ObserveSince(time.Now())
+	freshenMonitors.Add(uint64(len(opts.Addrs)))
+
 	for _, address := range opts.Addrs {
 		lockForAddress(address)
 		defer unlockForAddress(address) // reminder: this defers until the function returns, not this loop
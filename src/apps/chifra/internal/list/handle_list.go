@@ -14,6 +14,7 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient/ens"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
@@ -49,6 +50,11 @@ func (opts *ListOptions) HandleListAppearances(monitorArray []monitor.Monitor) e
 				return si < sj
 			})
 
+			ensName := ""
+			if opts.Ens {
+				ensName, _ = ens.ReverseResolve(chain, mon.Address)
+			}
+
 			currentBn := uint32(0)
 			currentTs := int64(0)
 			for i, app := range apps {
@@ -76,6 +82,7 @@ func (opts *ListOptions) HandleListAppearances(monitorArray []monitor.Monitor) e
 						TransactionIndex: app.TransactionId,
 						Timestamp:        currentTs,
 						Date:             utils.FormattedDate(currentTs),
+						EnsName:          ensName,
 					}
 
 					modelChan <- &s
@@ -86,7 +93,10 @@ func (opts *ListOptions) HandleListAppearances(monitorArray []monitor.Monitor) e
 		}
 	}
 
-	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+	extra := map[string]interface{}{
+		"ens": opts.Ens,
+	}
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
 }
 
 func (opts *ListOptions) IsMax(cnt uint64) bool {
@@ -0,0 +1,140 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package listPkg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient/ens"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/token"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// HandleSummary reports a single summary of each monitored address, combining its name, whether
+// it's a contract (and if so, a proxy or a token), its appearances, and its current balance.
+func (opts *ListOptions) HandleSummary(monitorArray []monitor.Monitor) error {
+	chain := opts.Globals.Chain
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
+		for _, mon := range monitorArray {
+			if opts.NoZero && mon.Count() == 0 {
+				mon.Close()
+				continue
+			}
+
+			s, err := summarize(chain, &mon)
+			mon.Close()
+			if err != nil {
+				errorChan <- err
+				continue
+			}
+			if opts.Ens {
+				s.EnsName, _ = ens.ReverseResolve(chain, s.Address)
+			}
+			modelChan <- s
+		}
+	}
+
+	extra := map[string]interface{}{
+		"ens": opts.Ens,
+	}
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
+}
+
+// summarize builds the summary of the monitor's address
+func summarize(chain string, mon *monitor.Monitor) (*simpleSummary, error) {
+	s := simpleSummary{
+		Address:     mon.Address,
+		AppsPerYear: []yearCount{},
+	}
+
+	if name, ok := names.FindName(chain, mon.Address); ok {
+		s.Name = &name
+		s.IsErc20 = name.IsErc20
+		s.IsErc721 = name.IsErc721
+	}
+
+	code, err := rpcClient.GetCodeAt(chain, mon.Address.Hex(), utils.NOPOS)
+	if err != nil {
+		return nil, err
+	}
+	if s.IsContract = len(code) > 0; s.IsContract {
+		impl, kind, err := abi.GetProxyImplementation(chain, mon.Address, utils.NOPOS)
+		if err != nil {
+			logger.Warn("Could not check if", mon.Address.Hex(), "is a proxy:", err)
+		} else if !impl.IsZero() && impl != mon.Address {
+			s.Proxy, s.ProxyKind = impl, string(kind)
+		}
+
+		// The names database only knows about tokens someone has named, so ask the contract
+		tok := token.GetToken(chain, mon.Address, utils.NOPOS)
+		s.IsErc20 = s.IsErc20 || tok.IsErc20
+		s.IsErc721 = s.IsErc721 || tok.IsErc721
+	}
+
+	balance, err := rpcClient.GetBalanceAt(chain, mon.Address.Hex(), utils.NOPOS)
+	if err != nil {
+		return nil, err
+	}
+	s.Balance = *balance
+
+	if s.Count = uint64(mon.Count()); s.Count > 0 {
+		apps := make([]index.AppearanceRecord, s.Count)
+		if err := mon.ReadAppearances(&apps); err != nil {
+			return nil, err
+		}
+		sort.Slice(apps, func(i, j int) bool {
+			if apps[i].BlockNumber != apps[j].BlockNumber {
+				return apps[i].BlockNumber < apps[j].BlockNumber
+			}
+			return apps[i].TransactionId < apps[j].TransactionId
+		})
+
+		first, latest := apps[0], apps[len(apps)-1]
+		s.FirstApp = fmt.Sprintf("%d.%d", first.BlockNumber, first.TransactionId)
+		s.LatestApp = fmt.Sprintf("%d.%d", latest.BlockNumber, latest.TransactionId)
+		if s.AppsPerYear, err = countPerYear(chain, apps); err != nil {
+			return nil, err
+		}
+		s.FirstTs, _ = tslib.FromBnToTs(chain, uint64(first.BlockNumber))
+		s.LatestTs, _ = tslib.FromBnToTs(chain, uint64(latest.BlockNumber))
+	}
+
+	return &s, nil
+}
+
+// countPerYear counts the (sorted) appearances in each year, oldest year first
+func countPerYear(chain string, apps []index.AppearanceRecord) ([]yearCount, error) {
+	counts := []yearCount{}
+	currentBn := uint32(0)
+	year := 0
+	for i, app := range apps {
+		if i == 0 || app.BlockNumber != currentBn {
+			ts, err := tslib.FromBnToTs(chain, uint64(app.BlockNumber))
+			if err != nil {
+				return nil, err
+			}
+			year = time.Unix(ts, 0).UTC().Year()
+			currentBn = app.BlockNumber
+		}
+		if len(counts) == 0 || counts[len(counts)-1].Year != year {
+			counts = append(counts, yearCount{Year: year})
+		}
+		counts[len(counts)-1].Count++
+	}
+	return counts, nil
+}
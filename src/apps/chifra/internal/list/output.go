@@ -66,6 +66,8 @@ func (opts *ListOptions) ListInternal() (err error, handled bool) {
 			err = opts.HandleListCount(monitorArray)
 		} else if opts.Bounds {
 			err = opts.HandleBounds(monitorArray)
+		} else if opts.Summary {
+			err = opts.HandleSummary(monitorArray)
 		} else if !opts.Silent {
 			err = opts.HandleListAppearances(monitorArray)
 		}
@@ -0,0 +1,127 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package listPkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// yearCount is the number of appearances of an address in a single (UTC) year
+type yearCount struct {
+	Year  int    `json:"year"`
+	Count uint64 `json:"count"`
+}
+
+// simpleSummary combines what we know about an address: its name, what kind of account it is,
+// where it appears, and its current balance
+type simpleSummary struct {
+	Address     base.Address      `json:"address"`
+	Name        *types.SimpleName `json:"name,omitempty"`
+	EnsName     string            `json:"ensName,omitempty"`
+	IsContract  bool              `json:"isContract"`
+	Proxy       base.Address      `json:"proxy,omitempty"`
+	ProxyKind   string            `json:"proxyKind,omitempty"`
+	IsErc20     bool              `json:"isErc20"`
+	IsErc721    bool              `json:"isErc721"`
+	Count       uint64            `json:"count"`
+	FirstApp    string            `json:"firstApp,omitempty"`
+	FirstTs     base.Timestamp    `json:"firstTs,omitempty"`
+	LatestApp   string            `json:"latestApp,omitempty"`
+	LatestTs    base.Timestamp    `json:"latestTs,omitempty"`
+	AppsPerYear []yearCount       `json:"appsPerYear"`
+	Balance     base.Wei          `json:"balance"`
+}
+
+func (s *simpleSummary) Raw() *types.RawModeler {
+	return nil
+}
+
+func (s *simpleSummary) Model(showHidden bool, format string, extraOptions map[string]any) types.Model {
+	name, tags := "", ""
+	if s.Name != nil {
+		name, tags = s.Name.Name, s.Name.Tags
+	}
+
+	model := map[string]interface{}{
+		"address":    s.Address,
+		"name":       name,
+		"tags":       tags,
+		"isContract": s.IsContract,
+		"isErc20":    s.IsErc20,
+		"isErc721":   s.IsErc721,
+		"count":      s.Count,
+		"firstApp":   s.FirstApp,
+		"firstDate":  "",
+		"latestApp":  s.LatestApp,
+		"latestDate": "",
+		"balance":    s.Balance.String(),
+		"ether":      utils.WeiToEther(&s.Balance).Text('f', 18),
+	}
+	order := []string{
+		"address",
+		"name",
+		"tags",
+		"isContract",
+		"proxy",
+		"isErc20",
+		"isErc721",
+		"count",
+		"firstApp",
+		"firstDate",
+		"latestApp",
+		"latestDate",
+		"appsPerYear",
+		"balance",
+		"ether",
+	}
+
+	if s.Count > 0 {
+		model["firstDate"] = utils.FormattedDate(s.FirstTs)
+		model["latestDate"] = utils.FormattedDate(s.LatestTs)
+	}
+
+	model["proxy"] = ""
+	if !s.Proxy.IsZero() {
+		model["proxy"] = s.Proxy.Hex()
+	}
+
+	if format == "json" {
+		model["appsPerYear"] = s.AppsPerYear
+		if s.Name != nil {
+			model["name"] = s.Name
+			delete(model, "tags")
+		}
+		if !s.Proxy.IsZero() {
+			model["proxyKind"] = s.ProxyKind
+		} else {
+			delete(model, "proxy")
+		}
+		if s.Count > 0 {
+			model["firstTs"] = s.FirstTs
+			model["latestTs"] = s.LatestTs
+		}
+	} else {
+		years := make([]string, 0, len(s.AppsPerYear))
+		for _, year := range s.AppsPerYear {
+			years = append(years, fmt.Sprintf("%d:%d", year.Year, year.Count))
+		}
+		model["appsPerYear"] = strings.Join(years, "|")
+	}
+
+	if extraOptions["ens"] == true {
+		model["ensName"] = s.EnsName
+		order = append(order, "ensName")
+	}
+
+	return types.Model{
+		Data:  model,
+		Order: order,
+	}
+}
@@ -0,0 +1,52 @@
+package listPkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestSummaryModel(t *testing.T) {
+	s := simpleSummary{
+		Address:     base.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7"),
+		Name:        &types.SimpleName{Name: "Tether USD", Tags: "50-Tokens:ERC20"},
+		IsContract:  true,
+		Proxy:       base.HexToAddress("0x0000000000000000000000000000000000000abc"),
+		ProxyKind:   "eip1967",
+		IsErc20:     true,
+		Count:       4,
+		FirstApp:    "100.1",
+		LatestApp:   "200.2",
+		AppsPerYear: []yearCount{{2019, 1}, {2021, 3}},
+		Balance:     *big.NewInt(1500000000000000000),
+	}
+
+	txt := s.Model(false, "txt", map[string]any{"ens": false}).Data
+	if txt["appsPerYear"] != "2019:1|2021:3" || txt["name"] != "Tether USD" || txt["tags"] != "50-Tokens:ERC20" {
+		t.Error("unexpected txt model", txt)
+	}
+	if txt["ether"] != "1.500000000000000000" || txt["balance"] != "1500000000000000000" {
+		t.Error("unexpected balance", txt["balance"], txt["ether"])
+	}
+	if txt["proxy"] != "0x0000000000000000000000000000000000000abc" {
+		t.Error("expected the proxy's implementation", txt["proxy"])
+	}
+	if _, ok := txt["ensName"]; ok {
+		t.Error("expected no ensName without --ens")
+	}
+
+	model := s.Model(false, "json", map[string]any{"ens": true})
+	if years, ok := model.Data["appsPerYear"].([]yearCount); !ok || len(years) != 2 {
+		t.Error("expected the years to be a list in json", model.Data["appsPerYear"])
+	}
+	if model.Data["proxyKind"] != "eip1967" || model.Order[len(model.Order)-1] != "ensName" {
+		t.Error("unexpected json model", model)
+	}
+
+	s.Proxy, s.Count = base.Address{}, 0
+	if data := s.Model(false, "json", nil).Data; data["proxy"] != nil || data["firstTs"] != nil {
+		t.Error("expected no proxy or dates", data)
+	}
+}
@@ -52,6 +52,14 @@ func (opts *ListOptions) validateList() error {
 		return validate.Usage("Please choose only one of {0} and {1}.", "--count", "--appearances")
 	}
 
+	if opts.Summary && (opts.Count || opts.Bounds || opts.Appearances || opts.Silent) {
+		return validate.Usage("The {0} option is not available{1}.", "--summary", " with --count, --bounds, --appearances, or --silent")
+	}
+
+	if opts.Ens && (opts.Count || opts.Bounds || opts.Silent) {
+		return validate.Usage("The {0} option is not available{1}.", "--ens", " with --count, --bounds, or --silent")
+	}
+
 	if len(opts.Globals.File) == 0 {
 		err := validate.ValidateAtLeastOneAddr(opts.Addrs)
 		if err != nil {
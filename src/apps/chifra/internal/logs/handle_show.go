@@ -8,7 +8,6 @@ import (
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
-	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
@@ -17,7 +16,7 @@ import (
 
 func (opts *LogsOptions) HandleShowLogs() (err error) {
 	abiMap := make(abi.AbiInterfaceMap)
-	loadedMap := make(map[base.Address]bool)
+	loadedMap := make(abi.LoadedAbis)
 	chain := opts.Globals.Chain
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -45,12 +44,10 @@ func (opts *LogsOptions) HandleShowLogs() (err error) {
 					log.Timestamp = tx.Timestamp
 					if opts.Articulate {
 						var err error
-						if !loadedMap[log.Address] {
-							if err = abi.LoadAbi(chain, log.Address, abiMap); err != nil {
-								// continue processing even with an error
-								errorChan <- err
-								err = nil
-							}
+						if err = loadedMap.Load(chain, log.Address, uint64(log.BlockNumber), abiMap); err != nil {
+							// continue processing even with an error
+							errorChan <- err
+							err = nil
 						}
 						if err == nil {
 							log.ArticulatedLog, err = articulate.ArticulateLog(&log, abiMap)
@@ -58,6 +55,12 @@ func (opts *LogsOptions) HandleShowLogs() (err error) {
 								// continue processing even with an error
 								errorChan <- err
 							}
+							if log.ArticulatedLog == nil {
+								if log.ArticulatedLog, err = articulate.FindEvent(chain, &log); err != nil {
+									// continue processing even with an error
+									errorChan <- err
+								}
+							}
 						}
 					}
 
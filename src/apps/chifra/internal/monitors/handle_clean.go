@@ -12,10 +12,18 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 )
 
-// HandleClean
+// HandleClean removes duplicate appearances from the given monitors or, if none are given, from
+// every monitor
 func (opts *MonitorsOptions) HandleClean() error {
 	testMode := opts.Globals.TestMode
 	_, monArray := monitor.GetMonitorMap(opts.Globals.Chain)
+	if len(opts.Addrs) > 0 {
+		monArray = make([]*monitor.Monitor, 0, len(opts.Addrs))
+		for _, addr := range opts.Addrs {
+			mon := monitor.NewMonitor(opts.Globals.Chain, addr, false)
+			monArray = append(monArray, &mon)
+		}
+	}
 
 	ctx := context.Background()
 	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
@@ -5,6 +5,7 @@
 package monitorsPkg
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
 )
 
@@ -22,112 +25,168 @@ import (
 // Not Deleted | Delete	| Error    | Error  | Delete, Remove and Decache |
 // Deleted     | Error  | Undelete | Remove | Remove and Decache         |
 // ------------|--------|------------------------------------------------|
+//
+// Every address is validated before any monitor is changed. Each changed monitor produces a
+// simpleMonitorCrud describing what was done.
 func (opts *MonitorsOptions) HandleCrudCommands() error {
-	testMode := opts.Globals.TestMode
+	chain := opts.Globals.Chain
 	for _, addr := range opts.Addrs {
-		m := monitor.NewMonitor(opts.Globals.Chain, addr, false)
-		if !file.FileExists(m.Path()) {
-			return validate.Usage("No monitor was found for address " + addr + ".")
-
-		} else {
-			if opts.Decache {
-				logger.Info("Decaching", addr)
-				if opts.Globals.TestMode {
-					logger.Info("Decaching monitor for address", addr, "not tested.")
-					return nil
-				}
-
-				itemsSeen := int64(0)
-				itemsRemoved := int64(0)
-				bytesRemoved := int64(0)
-				processorFunc := func(fileName string) bool {
-					itemsSeen++
-					if !file.FileExists(fileName) {
-						logger.Progress(!testMode && itemsSeen%203 == 0, "Already removed ", fileName)
-						return true // continue processing
-					}
-
-					itemsRemoved++
-					bytesRemoved += file.FileSize(fileName)
-					logger.Progress(!testMode && itemsRemoved%20 == 0, "Removed ", itemsRemoved, " items and ", bytesRemoved, " bytes.", fileName)
-
-					os.Remove(fileName)
-					if opts.Globals.Verbose {
-						logger.Info(fileName, "was removed.")
-					}
-					path, _ := filepath.Split(fileName)
-					if empty, _ := file.IsFolderEmpty(path); empty {
-						os.RemoveAll(path)
-						if opts.Globals.Verbose {
-							logger.Info("Empty folder", path, "was removed.")
-						}
-					}
-
-					return true
-				}
-
-				// Visits every item in the cache related to this monitor and calls into `processorFunc`
-				err := m.Decache(opts.Globals.Chain, processorFunc)
-				if err != nil {
-					return err
-				}
-				logger.Info(itemsRemoved, "items totaling", bytesRemoved, "bytes were removed from the cache.", strings.Repeat(" ", 60))
-
-				// We've visited them all, so delete the monitor itself
-				m.Delete()
-				logger.Info(("Monitor " + addr + " was deleted but not removed."))
-				wasRemoved, err := m.Remove()
-				if !wasRemoved || err != nil {
-					logger.Info(("Monitor for " + addr + " was not removed (" + err.Error() + ")"))
-				} else {
-					logger.Info(("Monitor for " + addr + " was permanently removed."))
-				}
-
-			} else if opts.Undelete && !m.IsDeleted() {
-				return validate.Usage("Monitor for {0} must be deleted before being undeleted.", addr)
-
-			} else {
-				if opts.Delete && opts.Remove {
-					// do nothing, it will be resolved below...
-
-				} else {
-					if opts.Delete && m.IsDeleted() {
-						return validate.Usage("Monitor for {0} is already deleted.", addr)
-
-					} else if opts.Remove && !m.IsDeleted() {
-						return validate.Usage("Cannot remove a file that has not previously been deleted.")
-					}
-				}
+		if err := opts.validateCrud(monitor.NewMonitor(chain, addr, false)); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
+		for _, addr := range opts.Addrs {
+			m := monitor.NewMonitor(chain, addr, false)
+			var s *simpleMonitorCrud
+			var err error
+			switch {
+			case opts.Decache:
+				s, err = opts.decacheMonitor(&m)
+			case opts.Undelete:
+				s = undeleteMonitor(&m)
+			default:
+				s = opts.deleteOrRemoveMonitor(&m)
+			}
+			if err != nil {
+				errorChan <- err
+				continue
 			}
+			modelChan <- s
 		}
 	}
 
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
+
+// validateCrud returns a usage error if the requested operation is invalid for the monitor's state
+func (opts *MonitorsOptions) validateCrud(m monitor.Monitor) error {
+	addr := m.Address.Hex()
+	if !file.FileExists(m.Path()) {
+		return validate.Usage("No monitor was found for address " + addr + ".")
+	}
+
 	if opts.Decache {
 		return nil
+
+	} else if opts.Undelete {
+		if !m.IsDeleted() {
+			return validate.Usage("Monitor for {0} must be deleted before being undeleted.", addr)
+		}
+
+	} else if opts.Delete && opts.Remove {
+		// do nothing, it will be resolved when processed...
+
+	} else if opts.Delete && m.IsDeleted() {
+		return validate.Usage("Monitor for {0} is already deleted.", addr)
+
+	} else if opts.Remove && !m.IsDeleted() {
+		return validate.Usage("Cannot remove a file that has not previously been deleted.")
 	}
 
-	for _, addr := range opts.Addrs {
-		m := monitor.NewMonitor(opts.Globals.Chain, addr, false)
-		if opts.Undelete {
-			m.UnDelete()
-			logger.Info(("Monitor " + addr + " was undeleted."))
-
-		} else {
-			if opts.Delete {
-				m.Delete()
-				logger.Info(("Monitor " + addr + " was deleted but not removed."))
-			}
+	return nil
+}
+
+// decacheMonitor removes every item in the cache related to the monitor, then deletes and removes
+// the monitor itself
+func (opts *MonitorsOptions) decacheMonitor(m *monitor.Monitor) (*simpleMonitorCrud, error) {
+	testMode := opts.Globals.TestMode
+	addr := m.Address.Hex()
+	s := simpleMonitorCrud{
+		Address: m.Address,
+		Action:  "decache",
+	}
+
+	logger.Info("Decaching", addr)
+	if testMode {
+		s.Msg = "Decaching monitor for address " + addr + " not tested."
+		return &s, nil
+	}
+
+	itemsSeen := int64(0)
+	processorFunc := func(fileName string) bool {
+		itemsSeen++
+		if !file.FileExists(fileName) {
+			logger.Progress(!testMode && itemsSeen%203 == 0, "Already removed ", fileName)
+			return true // continue processing
+		}
 
-			if opts.Remove {
-				wasRemoved, err := m.Remove()
-				if !wasRemoved || err != nil {
-					logger.Info(("Monitor for " + addr + " was not removed (" + err.Error() + ")"))
-				} else {
-					logger.Info(("Monitor for " + addr + " was permanently removed."))
-				}
+		s.ItemsRemoved++
+		s.BytesRemoved += file.FileSize(fileName)
+		logger.Progress(!testMode && s.ItemsRemoved%20 == 0, "Removed ", s.ItemsRemoved, " items and ", s.BytesRemoved, " bytes.", fileName)
+
+		os.Remove(fileName)
+		if opts.Globals.Verbose {
+			logger.Info(fileName, "was removed.")
+		}
+		path, _ := filepath.Split(fileName)
+		if empty, _ := file.IsFolderEmpty(path); empty {
+			os.RemoveAll(path)
+			if opts.Globals.Verbose {
+				logger.Info("Empty folder", path, "was removed.")
 			}
 		}
+
+		return true
 	}
 
-	return nil
+	// Visits every item in the cache related to this monitor and calls into `processorFunc`
+	if err := m.Decache(opts.Globals.Chain, processorFunc); err != nil {
+		return nil, err
+	}
+	logger.Info(s.ItemsRemoved, "items totaling", s.BytesRemoved, "bytes were removed from the cache.", strings.Repeat(" ", 60))
+
+	// We've visited them all, so delete the monitor itself
+	m.Delete()
+	s.Deleted = true
+	removeMonitor(m, &s)
+	return &s, nil
+}
+
+// undeleteMonitor clears the monitor's delete flag
+func undeleteMonitor(m *monitor.Monitor) *simpleMonitorCrud {
+	m.UnDelete()
+	return &simpleMonitorCrud{
+		Address: m.Address,
+		Action:  "undelete",
+		Msg:     "Monitor " + m.Address.Hex() + " was undeleted.",
+	}
+}
+
+// deleteOrRemoveMonitor deletes and/or removes the monitor depending on the options
+func (opts *MonitorsOptions) deleteOrRemoveMonitor(m *monitor.Monitor) *simpleMonitorCrud {
+	s := simpleMonitorCrud{
+		Address: m.Address,
+		Action:  "delete",
+	}
+
+	if opts.Delete {
+		m.Delete()
+		s.Msg = "Monitor " + m.Address.Hex() + " was deleted but not removed."
+	}
+	s.Deleted = m.IsDeleted()
+
+	if opts.Remove {
+		s.Action = "remove"
+		removeMonitor(m, &s)
+	}
+
+	return &s
+}
+
+// removeMonitor permanently removes a previously deleted monitor recording the result in s
+func removeMonitor(m *monitor.Monitor, s *simpleMonitorCrud) {
+	addr := m.Address.Hex()
+	wasRemoved, err := m.Remove()
+	if err != nil {
+		s.Msg = "Monitor for " + addr + " was not removed (" + err.Error() + ")"
+	} else if !wasRemoved {
+		s.Msg = "Monitor for " + addr + " was not removed."
+	} else {
+		s.Removed = true
+		s.Msg = "Monitor for " + addr + " was permanently removed."
+	}
+	logger.Info(s.Msg)
 }
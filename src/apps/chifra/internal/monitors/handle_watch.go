@@ -0,0 +1,230 @@
+package monitorsPkg
+
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// exportJob is a single line from the commands file. For each monitor with new appearances, the
+// job extracts data of the kind named by Folder (apps, txs, receipts, logs, or traces) for each new
+// appearance and writes it to the monitor's file in the job's folder in the job's format.
+type exportJob struct {
+	SemiParse
+	Articulate bool
+}
+
+// newExportJob converts a command file entry into an export job
+func newExportJob(sp SemiParse) (exportJob, error) {
+	job := exportJob{SemiParse: sp}
+	switch filepath.Base(sp.Folder) {
+	case "apps", "txs", "receipts", "logs", "traces":
+	default:
+		return job, fmt.Errorf("export command '%s' is not supported by --watch", sp.CmdLine)
+	}
+	job.Articulate = strings.Contains(sp.CmdLine+" ", "--articulate ") || strings.Contains(sp.CmdLine+" ", "-a ")
+	return job, nil
+}
+
+// kind returns the type of data the job extracts
+func (job *exportJob) kind() string {
+	return filepath.Base(job.Folder)
+}
+
+// outputPath returns the file to which the job writes data for the given monitor
+func (job *exportJob) outputPath(mon *monitor.Monitor) string {
+	return filepath.Join(job.Folder, mon.Address.Hex()+"."+job.Fmt)
+}
+
+// run writes the data for the given appearances to the monitor's output file, appending if the
+// file already exists.
+func (job *exportJob) run(chain string, mon *monitor.Monitor, apps []index.AppearanceRecord, abis *abiCache) error {
+	if len(apps) == 0 {
+		return nil
+	}
+
+	outputFn := job.outputPath(mon)
+	exists := file.FileExists(outputFn)
+	fp, err := os.OpenFile(outputFn, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	first := !exists
+	write := func(model types.Model) error {
+		err := job.writeModel(fp, model, first)
+		first = false
+		return err
+	}
+
+	for _, app := range apps {
+		switch job.kind() {
+		case "apps":
+			ts, _ := tslib.FromBnToTs(chain, uint64(app.BlockNumber))
+			s := types.SimpleAppearance{
+				Address:          mon.Address,
+				BlockNumber:      app.BlockNumber,
+				TransactionIndex: app.TransactionId,
+				Timestamp:        ts,
+				Date:             utils.FormattedDate(ts),
+			}
+			if err := write(s.Model(false, job.Fmt, nil)); err != nil {
+				return err
+			}
+
+		case "traces":
+			traces, err := rpcClient.GetTracesByTransactionId(chain, uint64(app.BlockNumber), uint64(app.TransactionId))
+			if err != nil {
+				return err
+			}
+			for i := range traces {
+				if job.Articulate {
+					traces[i].ArticulatedTrace, _ = articulate.ArticulateTrace(&traces[i], abis.load(chain, traces[i].Action.To, uint64(app.BlockNumber)))
+					if traces[i].ArticulatedTrace == nil {
+						traces[i].ArticulatedTrace, _ = articulate.FindFunction(chain, traces[i].Action.Input)
+					}
+					traces[i].ArticulatedTrace, _ = abis.nested(chain, traces[i].Action.To, uint64(app.BlockNumber), traces[i].ArticulatedTrace)
+					traces[i].ArticulatedError, _ = articulate.ArticulateTraceError(&traces[i], abis.abiMap)
+				}
+				if err := write(traces[i].Model(false, job.Fmt, nil)); err != nil {
+					return err
+				}
+			}
+
+		default:
+			raw := types.RawAppearance{
+				Address:          mon.Address.Hex(),
+				BlockNumber:      app.BlockNumber,
+				TransactionIndex: app.TransactionId,
+			}
+			tx, err := rpcClient.GetTransactionByAppearance(chain, &raw, false)
+			if err != nil {
+				return err
+			}
+
+			if job.Articulate {
+				abiMap := abis.load(chain, tx.To, uint64(app.BlockNumber))
+				if len(tx.Input) >= 10 {
+					if found := abiMap[tx.Input[:10]]; found != nil && found.IsMethod() {
+						tx.ArticulatedTx = found.Copy()
+						_ = articulate.ArticulateFunction(tx.ArticulatedTx, tx.Input[10:], "")
+						tx.ArticulatedTx, _ = abis.nested(chain, tx.To, uint64(app.BlockNumber), tx.ArticulatedTx)
+					} else {
+						tx.ArticulatedTx, _ = articulate.FindFunction(chain, tx.Input)
+					}
+				}
+				if tx.Receipt != nil {
+					for i := range tx.Receipt.Logs {
+						log := &tx.Receipt.Logs[i]
+						log.ArticulatedLog, _ = articulate.ArticulateLog(log, abis.load(chain, log.Address, uint64(app.BlockNumber)))
+						if log.ArticulatedLog == nil {
+							log.ArticulatedLog, _ = articulate.FindEvent(chain, log)
+						}
+					}
+				}
+			}
+
+			switch job.kind() {
+			case "txs":
+				err = write(tx.Model(false, job.Fmt, nil))
+			case "receipts":
+				if tx.Receipt != nil {
+					err = write(tx.Receipt.Model(false, job.Fmt, nil))
+				}
+			case "logs":
+				if tx.Receipt != nil {
+					for _, log := range tx.Receipt.Logs {
+						if err = write(log.Model(false, job.Fmt, nil)); err != nil {
+							break
+						}
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeModel writes a single model to w. Text formats carry a header only at the top of a new
+// file. JSON output is written one object per line so that later passes may append to it.
+func (job *exportJob) writeModel(w io.Writer, model types.Model, first bool) error {
+	if job.Fmt == "json" {
+		bytes, err := json.Marshal(model.Data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(bytes, '\n'))
+		return err
+	}
+	return output.StreamModel(w, model, output.OutputOptions{
+		NoHeader: !first,
+		Format:   job.Fmt,
+	})
+}
+
+// abiCache holds the ABIs loaded while articulating so each is loaded only once per pass
+type abiCache struct {
+	abiMap abi.AbiInterfaceMap
+	loaded abi.LoadedAbis
+}
+
+func newAbiCache() *abiCache {
+	return &abiCache{
+		abiMap: make(abi.AbiInterfaceMap),
+		loaded: make(abi.LoadedAbis),
+	}
+}
+
+// load makes sure the ABI for the address (and, if it's a proxy, its implementation at the
+// block) is in the map and returns the map. Errors are reported but ignored as articulation
+// is best effort.
+func (c *abiCache) load(chain string, addr base.Address, bn uint64) abi.AbiInterfaceMap {
+	if !addr.IsZero() {
+		if err := c.loaded.Load(chain, addr, bn, c.abiMap); err != nil {
+			logger.Warn("Could not load ABI for", addr.Hex(), err)
+		}
+	}
+	return c.abiMap
+}
+
+// nested unwraps the calls made by wrapper functions (see articulate.ArticulateNested)
+func (c *abiCache) nested(chain string, to base.Address, bn uint64, function *types.SimpleFunction) (*types.SimpleFunction, error) {
+	return articulate.ArticulateNested(chain, bn, to, function, c.abiMap, c.loaded)
+}
+
+// filterApps returns the appearances between the first and last block (inclusive)
+func filterApps(apps []index.AppearanceRecord, firstBlock, lastBlock uint64) []index.AppearanceRecord {
+	ret := make([]index.AppearanceRecord, 0, len(apps))
+	for _, app := range apps {
+		bn := uint64(app.BlockNumber)
+		if bn >= firstBlock && bn <= lastBlock {
+			ret = append(ret, app)
+		}
+	}
+	return ret
+}
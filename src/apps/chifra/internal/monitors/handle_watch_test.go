@@ -0,0 +1,34 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package monitorsPkg
+
+import (
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
+)
+
+func Test_NewExportJob(t *testing.T) {
+	job, err := newExportJob(SemiParse{CmdLine: "--logs --articulate --fmt csv", Fmt: "csv", Folder: "exports/mainnet/logs"})
+	if err != nil || job.kind() != "logs" || !job.Articulate {
+		t.Error("unexpected job", job, err)
+	}
+
+	if _, err := newExportJob(SemiParse{CmdLine: "--neighbors --fmt csv", Fmt: "csv", Folder: "exports/mainnet/neighbors"}); err == nil {
+		t.Error("expected an error for an unsupported export")
+	}
+}
+
+func Test_FilterApps(t *testing.T) {
+	apps := []index.AppearanceRecord{
+		{BlockNumber: 10, TransactionId: 1},
+		{BlockNumber: 20, TransactionId: 2},
+		{BlockNumber: 30, TransactionId: 3},
+	}
+	got := filterApps(apps, 15, 30)
+	if len(got) != 2 || got[0].BlockNumber != 20 || got[1].BlockNumber != 30 {
+		t.Error("unexpected filter result", got)
+	}
+}
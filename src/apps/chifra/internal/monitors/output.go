@@ -11,7 +11,6 @@ package monitorsPkg
 // EXISTING_CODE
 import (
 	"net/http"
-	"strings"
 	"sync"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/internal/globals"
@@ -40,19 +39,8 @@ func ServeMonitors(w http.ResponseWriter, r *http.Request) (err error, handled b
 	outputHelpers.SetEnabledForCmds("monitors", opts.IsPorted())
 	outputHelpers.InitJsonWriterApi("monitors", w, &opts.Globals)
 	// EXISTING_CODE
-	if !opts.Globals.TestMode { // our test harness does not use DELETE
-		delOptions := "--delete, --undelete, or --remove"
-		if r.Method == "DELETE" {
-			if !opts.Delete && !opts.Undelete && !opts.Remove {
-				err = validate.Usage("Specify one of {0} when using the DELETE route.", delOptions)
-			}
-		} else {
-			if opts.Delete || opts.Undelete || opts.Remove {
-				delOptions = strings.Replace(delOptions, " or ", " and ", -1)
-				err = validate.Usage("The {0} options are not valid when using the GET route.", delOptions)
-			}
-		}
-		if err != nil {
+	if !opts.Globals.TestMode { // our test harness does not use DELETE or POST
+		if err = opts.validateMethod(r.Method); err != nil {
 			return err, true
 		}
 	}
@@ -108,10 +96,39 @@ func GetMonitorsOptions(args []string, g *globals.GlobalOptions) *MonitorsOption
 
 func (opts *MonitorsOptions) IsPorted() (ported bool) {
 	// EXISTING_CODE
-	ported = opts.List
+	ported = !opts.Watch
 	// EXISTING_CODE
 	return
 }
 
 // EXISTING_CODE
+// validateMethod makes sure the options agree with the API route's method. DELETE changes the
+// state of existing monitors (defaulting to --delete), POST restores or cleans them (defaulting
+// to --undelete), and GET only reads them.
+func (opts *MonitorsOptions) validateMethod(method string) error {
+	switch method {
+	case http.MethodDelete:
+		if opts.Undelete {
+			return validate.Usage("The {0} option is not valid when using the DELETE route.", "--undelete")
+		}
+		if !opts.Delete && !opts.Remove && !opts.Decache {
+			opts.Delete = true
+		}
+
+	case http.MethodPost:
+		if opts.Delete || opts.Remove || opts.Decache {
+			return validate.Usage("The {0} options are not valid when using the POST route.", "--delete, --remove, and --decache")
+		}
+		if !opts.Clean && !opts.Undelete {
+			opts.Undelete = true
+		}
+
+	default:
+		if opts.Delete || opts.Undelete || opts.Remove || opts.Decache {
+			return validate.Usage("The {0} options are not valid when using the GET route.", "--delete, --undelete, --remove, and --decache")
+		}
+	}
+	return nil
+}
+
 // EXISTING_CODE
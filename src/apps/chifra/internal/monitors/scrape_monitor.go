@@ -17,6 +17,7 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/colors"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/monitor"
 )
@@ -27,9 +28,6 @@ var MonitorScraper Scraper
 func (opts *MonitorsOptions) RunMonitorScraper(wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	chain := opts.Globals.Chain
-	establishExportPaths(chain)
-
 	tmpPath := config.GetPathToCache(opts.Globals.Chain) + "tmp/"
 
 	var s *Scraper = &MonitorScraper
@@ -40,28 +38,7 @@ func (opts *MonitorsOptions) RunMonitorScraper(wg *sync.WaitGroup) {
 			s.Pause()
 
 		} else {
-			monitorChan := make(chan monitor.Monitor)
-			var monitors []monitor.Monitor
-			go monitor.ListMonitors(chain, "monitors", monitorChan)
-			count := 0
-			for result := range monitorChan {
-				switch result.Address {
-				case monitor.SentinalAddr:
-					close(monitorChan)
-				default:
-					if result.Count() > 500000 {
-						logger.Warn("Ignoring too-large address", result.Address)
-						continue
-					}
-					monitors = append(monitors, result)
-					count++
-					if result.Count() > 0 {
-						logger.Info("     ", count, ": ", result, "                                        ")
-					}
-				}
-			}
-
-			canceled, _ := opts.Refresh(monitors)
+			canceled, _ := opts.FreshenOnce()
 			if canceled || os.Getenv("RUN_ONCE") == "true" {
 				return
 			}
@@ -78,6 +55,36 @@ func (opts *MonitorsOptions) RunMonitorScraper(wg *sync.WaitGroup) {
 	}
 }
 
+// FreshenOnce makes a single pass over the existing monitors freshening each of them and running
+// the export commands for any that have new appearances. Returns true if the user canceled.
+func (opts *MonitorsOptions) FreshenOnce() (bool, error) {
+	chain := opts.Globals.Chain
+	establishExportPaths(chain)
+
+	monitorChan := make(chan monitor.Monitor)
+	var monitors []monitor.Monitor
+	go monitor.ListMonitors(chain, "monitors", monitorChan)
+	count := 0
+	for result := range monitorChan {
+		switch result.Address {
+		case monitor.SentinalAddr:
+			close(monitorChan)
+		default:
+			if result.Count() > 500000 {
+				logger.Warn("Ignoring too-large address", result.Address)
+				continue
+			}
+			monitors = append(monitors, result)
+			count++
+			if result.Count() > 0 {
+				logger.Info("     ", count, ": ", result, "                                        ")
+			}
+		}
+	}
+
+	return opts.Refresh(monitors)
+}
+
 type SemiParse struct {
 	CmdLine string `json:"cmdLine"`
 	Fmt     string `json:"fmt"`
@@ -91,9 +98,22 @@ func (sp SemiParse) String() string {
 
 const addrsPerBatch = 8
 
+// Refresh freshens the given monitors and, for any monitor with new appearances, runs each of
+// the export commands in-process appending the new data to the monitor's export files.
 func (opts *MonitorsOptions) Refresh(monitors []monitor.Monitor) (bool, error) {
+	chain := opts.Globals.Chain
 	theCmds, _ := getCommandsFromFile(opts.Globals)
 
+	jobs := make([]exportJob, 0, len(theCmds))
+	for _, sp := range theCmds {
+		if job, err := newExportJob(sp); err != nil {
+			logger.Warn(err)
+		} else {
+			jobs = append(jobs, job)
+		}
+	}
+
+	abis := newAbiCache()
 	batches := batchMonitors(monitors, addrsPerBatch)
 	for i := 0; i < len(batches); i++ {
 		addrs, countsBefore := preProcessBatch(batches[i], i, len(monitors))
@@ -116,29 +136,28 @@ func (opts *MonitorsOptions) Refresh(monitors []monitor.Monitor) (bool, error) {
 				continue
 			}
 
-			for _, sp := range theCmds {
-				outputFn := sp.Folder + "/" + mon.Address.Hex() + "." + sp.Fmt
-				exists := file.FileExists(outputFn)
-				countBefore := countsBefore[j]
-
-				if !exists || countAfter > countBefore {
-					cmd := sp.CmdLine + " --output " + outputFn
-					add := ""
-					if exists {
-						add += fmt.Sprintf(" --first_record %d", uint64(countBefore+1))
-						add += fmt.Sprintf(" --max_records %d", uint64(countAfter-countBefore+1)) // extra space won't hurt
-						add += " --append --no_header"
+			apps := make([]index.AppearanceRecord, countAfter)
+			if err := mon.ReadAppearances(&apps); err != nil {
+				logger.Error("Could not read appearances for", mon.Address, err)
+				mon.Close()
+				continue
+			}
+			mon.Close()
+
+			for _, job := range jobs {
+				// If the export file does not yet exist, we export everything, otherwise only
+				// the appearances added by this pass's freshen
+				todo := apps
+				if file.FileExists(job.outputPath(&mon)) {
+					if countAfter <= countsBefore[j] {
+						continue
 					}
-					cmd += add + " " + mon.Address.Hex()
-					cmd = strings.Replace(cmd, "  ", " ", -1)
-					o := opts
-					o.Globals.File = ""
-					o.Globals.PassItOn("acctExport", opts.Globals.Chain, cmd, []string{})
-					// fmt.Println("Processing:", colors.BrightYellow, outputFn, colors.BrightWhite, exists, countBefore, countAfter, colors.Off)
-					// } else {
-					// 	fmt.Println("Skipping:", colors.BrightYellow, outputFn, colors.BrightWhite, exists, countBefore, countAfter, colors.Off)
+					todo = apps[countsBefore[j]:]
+				}
+				todo = filterApps(todo, opts.FirstBlock, opts.LastBlock)
+				if err := job.run(chain, &mon, todo, abis); err != nil {
+					logger.Error("Export to", job.outputPath(&mon), "failed:", err)
 				}
-				// time.Sleep(500 * time.Millisecond)
 			}
 		}
 	}
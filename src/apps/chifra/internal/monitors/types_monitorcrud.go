@@ -0,0 +1,68 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+/*
+ * Parts of this file were generated with makeClass --run. Edit only those parts of
+ * the code inside of 'EXISTING_CODE' tags.
+ */
+
+package monitorsPkg
+
+// EXISTING_CODE
+import (
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// EXISTING_CODE
+
+type simpleMonitorCrud struct {
+	Address      base.Address `json:"address"`
+	Action       string       `json:"action"`
+	Deleted      bool         `json:"deleted"`
+	Removed      bool         `json:"removed"`
+	ItemsRemoved int64        `json:"itemsRemoved,omitempty"`
+	BytesRemoved int64        `json:"bytesRemoved,omitempty"`
+	Msg          string       `json:"msg,omitempty"`
+	// EXISTING_CODE
+	// EXISTING_CODE
+}
+
+func (s *simpleMonitorCrud) Raw() *types.RawModeler {
+	return nil
+}
+
+func (s *simpleMonitorCrud) Model(showHidden bool, format string, extraOptions map[string]any) types.Model {
+	var model = map[string]interface{}{}
+	var order = []string{}
+
+	// EXISTING_CODE
+	model = map[string]any{
+		"address": s.Address,
+		"action":  s.Action,
+		"deleted": s.Deleted,
+		"removed": s.Removed,
+	}
+	order = []string{
+		"address",
+		"action",
+		"deleted",
+		"removed",
+	}
+	if s.Action == "decache" {
+		model["itemsRemoved"] = s.ItemsRemoved
+		model["bytesRemoved"] = s.BytesRemoved
+		order = append(order, "itemsRemoved", "bytesRemoved")
+	}
+	model["msg"] = s.Msg
+	order = append(order, "msg")
+	// EXISTING_CODE
+
+	return types.Model{
+		Data:  model,
+		Order: order,
+	}
+}
+
+// EXISTING_CODE
+// EXISTING_CODE
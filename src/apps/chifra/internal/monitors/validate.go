@@ -79,10 +79,6 @@ func (opts *MonitorsOptions) validateMonitors() error {
 					}
 				}
 			}
-
-			if opts.Globals.IsApiMode() && opts.Decache {
-				return validate.Usage("The {0} option is not available in API mode.", "--decache")
-			}
 		}
 	}
 
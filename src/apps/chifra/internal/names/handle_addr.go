@@ -11,7 +11,7 @@ import (
 )
 
 func (opts *NamesOptions) HandleAddr() error {
-	namesArray, err := names.LoadNamesArray(opts.Globals.Chain, opts.getType(), names.SortByAddress, opts.Terms)
+	namesArray, err := names.LoadNamesArray(opts.Globals.Chain, opts.getType(), opts.getSortBy(), opts.Terms)
 	if err != nil {
 		return err
 	}
@@ -1,22 +1,137 @@
 package namesPkg
 
-import "fmt"
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
 
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/token"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
+)
+
+// HandleAutoname names the address (or each address in the file) given to --autoname from what
+// can be learned on chain and stores the result in the custom names database
 func (opts *NamesOptions) HandleAutoname() error {
-	return fmt.Errorf("the chifra names --autoname option is currently not available")
+	chain := opts.Globals.Chain
+
+	addrs, err := opts.getAutonameAddresses()
+	if err != nil {
+		return err
+	}
+
+	if _, err = names.LoadNamesMap(chain, names.Custom, nil); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fetchData := func(modelChan chan types.Modeler[types.RawName], errorChan chan error) {
+		for _, addr := range addrs {
+			name, err := autoname(chain, addr)
+			if err != nil {
+				errorChan <- err
+				cancel()
+				return
+			}
+			if err = names.CreateCustomName(chain, name); err != nil {
+				errorChan <- err
+				cancel()
+				return
+			}
+			modelChan <- name
+		}
+	}
+
+	extra := map[string]interface{}{
+		"crud": true,
+	}
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
 }
 
-// } else if (!autoname.empty()) {
-//     abi_spec.loadAbisFromKnown(true);
-//     crudCommands.push_back("create");
-//     terms.push_back(autoname);
-//     // ::setenv("TB_NAME_ADDRESS", autoname.c_str(), true);
-//     ::setenv("TB_NAME_NAME", addr_2_Petname(autoname, '-').c_str(), true);
-//     ::setenv("TB_NAME_TAG", "50-Tokens:ERC20", true);
-//     ::setenv("TB_NAME_SOURCE", "TrueBlocks.io", true);
-//     ::setenv("TB_NAME_SYMBOL", "", true);
-//     ::setenv("TB_NAME_DECIMALS", "18", true);
-//     ::setenv("TB_NAME_CUSTOM", "false", true);
-//     if (!handle_editcmds(true))  // returns true on success
-//         return false;
-// }
+// getAutonameAddresses returns the address given to --autoname or, if it is a file, the addresses
+// in the file (one per line, blank lines and lines starting with # are ignored)
+func (opts *NamesOptions) getAutonameAddresses() ([]base.Address, error) {
+	if validate.IsValidAddress(opts.Autoname) {
+		return []base.Address{base.HexToAddress(opts.Autoname)}, nil
+	}
+
+	fp, err := os.Open(opts.Autoname)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	ret := []base.Address{}
+	scanner := bufio.NewScanner(fp)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !validate.IsValidAddress(line) {
+			return nil, fmt.Errorf("line %d of %s is not an address: %s", lineNo, opts.Autoname, line)
+		}
+		ret = append(ret, base.HexToAddress(line))
+	}
+	return ret, scanner.Err()
+}
+
+// autoname builds a name for the address from the chain (see token.GetToken for how tokens are
+// recognized). Anything we can't learn falls back to the address's petname.
+func autoname(chain string, addr base.Address) (*types.SimpleName, error) {
+	petname := names.AddrToPetname(addr.Hex(), "-")
+	name := &types.SimpleName{
+		Address: addr,
+		Name:    petname,
+		Petname: petname,
+		Tags:    "90-Individuals:Other",
+		Source:  "TrueBlocks.io",
+	}
+
+	code, err := rpcClient.GetCodeAt(chain, addr.Hex(), utils.NOPOS)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		logger.Info("Address", addr.Hex(), "is not a contract, naming it", petname)
+		return name, nil
+	}
+
+	name.IsContract = true
+	name.Tags = "30-Contracts"
+
+	tok := token.GetToken(chain, addr, utils.NOPOS)
+	if len(tok.Name) > 0 {
+		name.Name = tok.Name
+	}
+	name.Symbol = tok.Symbol
+
+	switch {
+	case tok.IsErc721:
+		name.IsErc721 = true
+		name.Tags = "50-Tokens:ERC721"
+	case tok.IsErc1155:
+		name.Tags = "50-Tokens:ERC1155"
+	case tok.IsErc20:
+		name.IsErc20 = true
+		name.Tags = "50-Tokens:ERC20"
+		name.Decimals = 18
+		if tok.HasDecimals {
+			name.Decimals = tok.Decimals
+		}
+	}
+	if strings.HasPrefix(name.Tags, "50-Tokens") {
+		name.Source = "On chain"
+	}
+
+	logger.Info("Address", addr.Hex(), "named", name.Name, "with tags", name.Tags)
+	return name, nil
+}
@@ -0,0 +1,31 @@
+package namesPkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetAutonameAddresses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addresses.txt")
+	contents := "# tokens\n0x6b175474e89094c44da98b954eedeac495271d0f\n\n  0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2  \n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := NamesOptions{Autoname: path}
+	addrs, err := opts.getAutonameAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 || addrs[1].Hex() != "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2" {
+		t.Error("unexpected addresses", addrs)
+	}
+
+	if err = os.WriteFile(path, []byte("not-an-address\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = opts.getAutonameAddresses(); err == nil {
+		t.Error("expected an error for a line that is not an address")
+	}
+}
@@ -0,0 +1,33 @@
+package namesPkg
+
+import (
+	"context"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// HandleHistory shows the journal of changes made to the custom name of the --history address,
+// oldest first.
+func (opts *NamesOptions) HandleHistory() error {
+	entries, err := names.ReadJournal(opts.Globals.Chain)
+	if err != nil {
+		return err
+	}
+
+	addr := base.HexToAddress(opts.History)
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
+		for _, entry := range entries {
+			if entry.Address == addr {
+				modelChan <- &simpleJournalEntry{
+					JournalEntry: entry,
+				}
+			}
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
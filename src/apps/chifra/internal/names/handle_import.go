@@ -0,0 +1,65 @@
+package namesPkg
+
+import (
+	"context"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// HandleImport imports the names in the --import file into the custom names database, resolving
+// names that already exist per --conflict. With --dry_run, it reports the changes without making them.
+func (opts *NamesOptions) HandleImport() error {
+	chain := opts.Globals.Chain
+
+	mapping, err := names.ParseMapping(opts.Mapping)
+	if err != nil {
+		return err
+	}
+
+	imported, err := names.ReadImportFile(opts.Import, mapping)
+	if err != nil {
+		return err
+	}
+
+	existing, err := names.LoadNamesMap(chain, names.Regular|names.Custom, nil)
+	if err != nil {
+		return err
+	}
+
+	strategy := names.ConflictStrategy(opts.Conflict)
+	if len(strategy) == 0 {
+		strategy = names.ConflictKeep
+	}
+	changes := names.ResolveImport(existing, imported, strategy)
+
+	if !opts.DryRun {
+		toWrite := make([]types.SimpleName, 0, len(changes))
+		for _, change := range changes {
+			if change.Action == names.ImportAdd || change.Action == names.ImportUpdate {
+				toWrite = append(toWrite, change.Name)
+			}
+		}
+		if len(toWrite) > 0 {
+			if err = names.ImportCustomNames(chain, toWrite); err != nil {
+				return err
+			}
+		}
+		logger.Info("Imported", len(toWrite), "of", len(imported), "names from", opts.Import)
+	}
+
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
+		for _, change := range changes {
+			modelChan <- &simpleNameChange{
+				Action:  change.Action,
+				Name:    change.Name,
+				Changed: change.Changed,
+			}
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
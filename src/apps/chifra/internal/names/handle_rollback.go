@@ -0,0 +1,80 @@
+package namesPkg
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
+)
+
+// HandleRollback returns the custom names database (or the custom names of the addresses given as
+// terms) to its state at the --rollback time by undoing the journaled changes made since. With
+// --dry_run, it reports the changes without making them.
+func (opts *NamesOptions) HandleRollback() error {
+	chain := opts.Globals.Chain
+
+	ts, err := parseRollbackTime(opts.Rollback)
+	if err != nil {
+		return err
+	}
+
+	entries, err := names.ReadJournal(chain)
+	if err != nil {
+		return err
+	}
+
+	current, err := names.LoadNamesMap(chain, names.Custom, nil)
+	if err != nil {
+		return err
+	}
+
+	filter := map[base.Address]bool{}
+	for _, term := range opts.Terms {
+		filter[base.HexToAddress(term)] = true
+	}
+	changes := names.ResolveRollback(current, entries, ts, filter)
+
+	if !opts.DryRun {
+		nChanged := 0
+		for _, change := range changes {
+			if change.Action != names.ImportUnchanged {
+				nChanged++
+			}
+		}
+		if nChanged > 0 {
+			if err = names.RollbackCustomNames(chain, changes); err != nil {
+				return err
+			}
+		}
+		logger.Info("Rolled back", nChanged, "custom names to", opts.Rollback)
+	}
+
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
+		for _, change := range changes {
+			modelChan <- &simpleNameChange{
+				Action:  change.Action,
+				Name:    change.Name,
+				Changed: change.Changed,
+			}
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
+
+// parseRollbackTime returns the timestamp of a date (YYYY-MM-DD[THH[:MM[:SS]]]) or of a string of
+// digits, which is taken to be a timestamp
+func parseRollbackTime(str string) (base.Timestamp, error) {
+	if validate.IsDateTimeString(str) {
+		return tslib.FromDateToTs(str)
+	}
+	ts, err := strconv.ParseInt(str, 10, 64)
+	return base.Timestamp(ts), err
+}
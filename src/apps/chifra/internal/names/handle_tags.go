@@ -11,28 +11,24 @@ import (
 )
 
 func (opts *NamesOptions) HandleTags() error {
-	namesArray, err := names.LoadNamesArray(opts.Globals.Chain, opts.getType(), names.SortByTags, opts.Terms)
+	tags, err := names.LoadTags(opts.Globals.Chain, opts.getType(), opts.Terms)
 	if err != nil {
 		return err
 	}
-	if len(namesArray) == 0 {
+	if len(tags) == 0 {
 		logger.Warn("No results for", os.Args)
 		return nil
 	}
 
-	tagsMap := make(map[string]bool, len(namesArray)/10)
 	ctx := context.Background()
 
 	// Note: Make sure to add an entry to enabledForCmd in src/apps/chifra/pkg/output/helpers.go
 	fetchData := func(modelChan chan types.Modeler[types.RawName], errorChan chan error) {
-		for _, name := range namesArray {
-			if !tagsMap[name.Tags] {
-				s := types.SimpleName{
-					Tags: name.Tags,
-				}
-				modelChan <- &s
+		for _, tag := range tags {
+			s := types.SimpleName{
+				Tags: tag,
 			}
-			tagsMap[name.Tags] = true
+			modelChan <- &s
 		}
 	}
 
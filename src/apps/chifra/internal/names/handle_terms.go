@@ -7,11 +7,12 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient/ens"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 )
 
 func (opts *NamesOptions) HandleTerms() error {
-	namesArray, err := names.LoadNamesArray(opts.Globals.Chain, opts.getType(), names.SortByAddress, opts.Terms)
+	namesArray, err := names.LoadNamesArray(opts.Globals.Chain, opts.getType(), opts.getSortBy(), opts.Terms)
 	if err != nil {
 		return err
 	}
@@ -26,6 +27,9 @@ func (opts *NamesOptions) HandleTerms() error {
 	fetchData := func(modelChan chan types.Modeler[types.RawName], errorChan chan error) {
 		for _, name := range namesArray {
 			name := name
+			if opts.Ens {
+				name.EnsName, _ = ens.ReverseResolve(opts.Globals.Chain, name.Address)
+			}
 			modelChan <- &name
 		}
 	}
@@ -33,6 +37,8 @@ func (opts *NamesOptions) HandleTerms() error {
 	extra := map[string]interface{}{
 		"expand":  opts.Expand,
 		"prefund": opts.Prefund,
+		"ens":     opts.Ens,
+		"score":   opts.Fuzzy,
 	}
 	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOptsWithExtra(extra))
 }
@@ -28,8 +28,17 @@ type NamesOptions struct {
 	Prefund   bool                  `json:"prefund,omitempty"`   // Include prefund accounts in the search
 	Addr      bool                  `json:"addr,omitempty"`      // Display only addresses in the results (useful for scripting, assumes --no_header)
 	Tags      bool                  `json:"tags,omitempty"`      // Export the list of tags and subtags only
+	Ens       bool                  `json:"ens,omitempty"`       // Include the primary ENS name of each address in the results
+	Fuzzy     bool                  `json:"fuzzy,omitempty"`     // Rank the results by relevance to the terms, matching partial words and tolerating typos
+	Sort      string                `json:"sort,omitempty"`      // The order of the results (defaults to score with --fuzzy, address otherwise)
 	Clean     bool                  `json:"clean,omitempty"`     // Clean the data (addrs to lower case, sort by addr)
-	Autoname  string                `json:"autoname,omitempty"`  // An address assumed to be a token, added automatically to names database if true
+	Autoname  string                `json:"autoname,omitempty"`  // An address (or a file of addresses) to name from on-chain data and add to the custom names database
+	Import    string                `json:"import,omitempty"`    // Import names from a CSV, TSV, or JSON file into the custom names database
+	Mapping   string                `json:"mapping,omitempty"`   // For --import only, map the file's columns to name fields (for example label=name,category=tags)
+	Conflict  string                `json:"conflict,omitempty"`  // For --import only, keep the existing name, overwrite it, or merge the two (taking the non-empty fields of the imported name)
+	DryRun    bool                  `json:"dryRun,omitempty"`    // For --import or --rollback only, report the changes without making them
+	History   string                `json:"history,omitempty"`   // Show the journal of changes made to the custom name of the address
+	Rollback  string                `json:"rollback,omitempty"`  // Undo changes made to custom names after the given date or timestamp (only for the addresses given as terms, if any)
 	Create    bool                  `json:"create,omitempty"`    // Create a new name record
 	Update    bool                  `json:"update,omitempty"`    // Edit an existing name
 	Delete    bool                  `json:"delete,omitempty"`    // Delete a name, but do not remove it
@@ -56,8 +65,17 @@ func (opts *NamesOptions) testLog() {
 	logger.TestLog(opts.Prefund, "Prefund: ", opts.Prefund)
 	logger.TestLog(opts.Addr, "Addr: ", opts.Addr)
 	logger.TestLog(opts.Tags, "Tags: ", opts.Tags)
+	logger.TestLog(opts.Ens, "Ens: ", opts.Ens)
+	logger.TestLog(opts.Fuzzy, "Fuzzy: ", opts.Fuzzy)
+	logger.TestLog(len(opts.Sort) > 0, "Sort: ", opts.Sort)
 	logger.TestLog(opts.Clean, "Clean: ", opts.Clean)
 	logger.TestLog(len(opts.Autoname) > 0, "Autoname: ", opts.Autoname)
+	logger.TestLog(len(opts.Import) > 0, "Import: ", opts.Import)
+	logger.TestLog(len(opts.Mapping) > 0, "Mapping: ", opts.Mapping)
+	logger.TestLog(len(opts.Conflict) > 0, "Conflict: ", opts.Conflict)
+	logger.TestLog(opts.DryRun, "DryRun: ", opts.DryRun)
+	logger.TestLog(len(opts.History) > 0, "History: ", opts.History)
+	logger.TestLog(len(opts.Rollback) > 0, "Rollback: ", opts.Rollback)
 	logger.TestLog(opts.Create, "Create: ", opts.Create)
 	logger.TestLog(opts.Update, "Update: ", opts.Update)
 	logger.TestLog(opts.Delete, "Delete: ", opts.Delete)
@@ -99,10 +117,28 @@ func namesFinishParseApi(w http.ResponseWriter, r *http.Request) *NamesOptions {
 			opts.Addr = true
 		case "tags":
 			opts.Tags = true
+		case "ens":
+			opts.Ens = true
+		case "fuzzy":
+			opts.Fuzzy = true
+		case "sort":
+			opts.Sort = value[0]
 		case "clean":
 			opts.Clean = true
 		case "autoname":
 			opts.Autoname = value[0]
+		case "import":
+			opts.Import = value[0]
+		case "mapping":
+			opts.Mapping = value[0]
+		case "conflict":
+			opts.Conflict = value[0]
+		case "dryRun":
+			opts.DryRun = true
+		case "history":
+			opts.History = value[0]
+		case "rollback":
+			opts.Rollback = value[0]
 		case "create":
 			opts.Create = true
 		case "update":
@@ -68,6 +68,12 @@ func (opts *NamesOptions) NamesInternal() (err error, handled bool) {
 	handled = true
 	if opts.anyCrud() {
 		err = opts.HandleCrud()
+	} else if len(opts.Import) > 0 {
+		err = opts.HandleImport()
+	} else if len(opts.History) > 0 {
+		err = opts.HandleHistory()
+	} else if len(opts.Rollback) > 0 {
+		err = opts.HandleRollback()
 	} else if len(opts.Autoname) > 0 {
 		err = opts.HandleAutoname()
 	} else if opts.Clean {
@@ -129,9 +135,32 @@ func (opts *NamesOptions) getType() names.Parts {
 		ret |= names.Testing
 	}
 
+	if opts.Fuzzy {
+		ret |= names.Fuzzy
+	}
+
 	return ret
 }
 
+// getSortBy returns the order of the results, which is by relevance for fuzzy searches unless
+// the user says otherwise
+func (opts *NamesOptions) getSortBy() names.SortBy {
+	switch opts.Sort {
+	case "name":
+		return names.SortByName
+	case "tags":
+		return names.SortByTags
+	case "score":
+		return names.SortByScore
+	case "address":
+		return names.SortByAddress
+	}
+	if opts.Fuzzy {
+		return names.SortByScore
+	}
+	return names.SortByAddress
+}
+
 func (opts *NamesOptions) anyBase() bool {
 	return opts.Expand ||
 		opts.MatchCase ||
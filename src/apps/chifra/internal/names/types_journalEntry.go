@@ -0,0 +1,89 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+/*
+ * Parts of this file were generated with makeClass --run. Edit only those parts of
+ * the code inside of 'EXISTING_CODE' tags.
+ */
+
+package namesPkg
+
+// EXISTING_CODE
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// EXISTING_CODE
+
+type simpleJournalEntry struct {
+	names.JournalEntry
+	// EXISTING_CODE
+	// EXISTING_CODE
+}
+
+func (s *simpleJournalEntry) Raw() *types.RawModeler {
+	return nil
+}
+
+func (s *simpleJournalEntry) Model(showHidden bool, format string, extraOptions map[string]any) types.Model {
+	var model = map[string]interface{}{}
+	var order = []string{}
+
+	// EXISTING_CODE
+	name := ""
+	if s.New != nil {
+		name = s.New.Name
+	} else if s.Old != nil {
+		name = s.Old.Name
+	}
+
+	model = map[string]interface{}{
+		"timestamp": s.Timestamp,
+		"date":      utils.FormattedDate(int64(s.Timestamp)),
+		"operation": s.Operation,
+		"source":    s.Source,
+		"address":   s.Address.Hex(),
+		"name":      name,
+	}
+	order = []string{
+		"timestamp",
+		"date",
+		"operation",
+		"source",
+		"address",
+		"name",
+		"changes",
+	}
+
+	changed := s.Changes()
+	if format == "json" {
+		changes := map[string]interface{}{}
+		for _, change := range changed {
+			changes[change.Field] = map[string]interface{}{
+				"from": change.From,
+				"to":   change.To,
+			}
+		}
+		model["changes"] = changes
+	} else {
+		changes := []string{}
+		for _, change := range changed {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", change.Field, change.From, change.To))
+		}
+		model["changes"] = strings.Join(changes, "; ")
+	}
+	// EXISTING_CODE
+
+	return types.Model{
+		Data:  model,
+		Order: order,
+	}
+}
+
+// EXISTING_CODE
+// EXISTING_CODE
@@ -0,0 +1,87 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+/*
+ * Parts of this file were generated with makeClass --run. Edit only those parts of
+ * the code inside of 'EXISTING_CODE' tags.
+ */
+
+package namesPkg
+
+// EXISTING_CODE
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// EXISTING_CODE
+
+type simpleNameChange struct {
+	Action  string              `json:"action"`
+	Name    types.SimpleName    `json:"name"`
+	Changed []names.FieldChange `json:"changed"`
+	// EXISTING_CODE
+	// EXISTING_CODE
+}
+
+func (s *simpleNameChange) Raw() *types.RawModeler {
+	return nil
+}
+
+func (s *simpleNameChange) Model(showHidden bool, format string, extraOptions map[string]any) types.Model {
+	var model = map[string]interface{}{}
+	var order = []string{}
+
+	// EXISTING_CODE
+	model = map[string]interface{}{
+		"action":  s.Action,
+		"address": s.Name.Address.Hex(),
+		"tags":    s.Name.Tags,
+		"name":    s.Name.Name,
+		"symbol":  s.Name.Symbol,
+		"source":  s.Name.Source,
+	}
+	order = []string{
+		"action",
+		"address",
+		"tags",
+		"name",
+		"symbol",
+		"source",
+	}
+
+	if len(s.Changed) > 0 {
+		if format == "json" {
+			changes := map[string]interface{}{}
+			for _, change := range s.Changed {
+				changes[change.Field] = map[string]interface{}{
+					"from": change.From,
+					"to":   change.To,
+				}
+			}
+			model["changes"] = changes
+		} else {
+			changes := []string{}
+			for _, change := range s.Changed {
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", change.Field, change.From, change.To))
+			}
+			model["changes"] = strings.Join(changes, "; ")
+		}
+		order = append(order, "changes")
+	} else if format != "json" {
+		model["changes"] = ""
+		order = append(order, "changes")
+	}
+	// EXISTING_CODE
+
+	return types.Model{
+		Data:  model,
+		Order: order,
+	}
+}
+
+// EXISTING_CODE
+// EXISTING_CODE
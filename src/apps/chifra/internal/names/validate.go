@@ -6,6 +6,8 @@ package namesPkg
 
 import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
 )
 
@@ -24,6 +26,31 @@ func (opts *NamesOptions) validateNames() error {
 		return validate.Usage("The {0} option is not available{1}.", "--addr", " with any other option")
 	}
 
+	if opts.Ens && (opts.Tags || opts.Addr || opts.Clean || len(opts.Autoname) > 0 || len(opts.Import) > 0 || len(opts.Rollback) > 0 || opts.anyCrud()) {
+		return validate.Usage("The {0} option is not available{1}.", "--ens", " with --tags, --addr, or when editing names")
+	}
+
+	if opts.Fuzzy {
+		if len(opts.Terms) == 0 {
+			return validate.Usage("The {0} option requires at least one {1}.", "--fuzzy", "term")
+		}
+		if opts.Tags || opts.Clean || len(opts.Autoname) > 0 || len(opts.Import) > 0 || len(opts.History) > 0 || len(opts.Rollback) > 0 || opts.anyCrud() {
+			return validate.Usage("The {0} option is not available{1}.", "--fuzzy", " with --tags or when editing names")
+		}
+	}
+
+	if len(opts.Sort) > 0 {
+		if err := validate.ValidateEnum("--sort", opts.Sort, "[address|name|tags|score]"); err != nil {
+			return err
+		}
+		if opts.Sort == "score" && !opts.Fuzzy {
+			return validate.Usage("The {0} option requires the {1} option.", "--sort score", "--fuzzy")
+		}
+		if opts.Tags || opts.Clean || len(opts.Autoname) > 0 || len(opts.Import) > 0 || len(opts.History) > 0 || len(opts.Rollback) > 0 || opts.anyCrud() {
+			return validate.Usage("The {0} option is not available{1}.", "--sort", " with --tags or when editing names")
+		}
+	}
+
 	if opts.MatchCase && len(opts.Terms) == 0 {
 		return validate.Usage("The {0} option requires at least one {1}.", "--match_case", "term")
 	}
@@ -46,9 +73,61 @@ func (opts *NamesOptions) validateNames() error {
 		return validate.Usage("Use the {0} option only when editing names.", "--to_custom")
 	}
 
-	addr := base.HexToAddress(opts.Autoname)
-	if len(opts.Autoname) > 0 && (!validate.IsValidAddress(opts.Autoname) || addr.IsZero()) {
-		return validate.Usage("You must provide an address to the {0} option.", "--autoname")
+	if len(opts.Import) > 0 {
+		if len(opts.Terms) > 0 || opts.Tags || opts.Addr || opts.Clean || len(opts.Autoname) > 0 || opts.anyCrud() {
+			return validate.Usage("The {0} option is not available{1}.", "--import", " with search terms or any other editing option")
+		}
+		if opts.Globals.IsApiMode() {
+			return validate.Usage("The {0} option is not available in {1} mode.", "--import", "API")
+		}
+		if !file.FileExists(opts.Import) {
+			return validate.Usage("The file provided to the {0} option ({1}) was not found.", "--import", opts.Import)
+		}
+		if err := validate.ValidateEnum("--conflict", opts.Conflict, "[keep|overwrite|merge]"); err != nil {
+			return err
+		}
+		if _, err := names.ParseMapping(opts.Mapping); err != nil {
+			return validate.Usage("The {0} option is invalid: {1}.", "--mapping", err.Error())
+		}
+	} else if len(opts.Mapping) > 0 || len(opts.Conflict) > 0 {
+		return validate.Usage("The {0} options are only available with the {1} option.", "--mapping and --conflict", "--import")
+	}
+
+	if len(opts.History) > 0 {
+		if len(opts.Terms) > 0 || opts.Tags || opts.Addr || opts.Ens || opts.Clean || len(opts.Autoname) > 0 || len(opts.Import) > 0 || opts.anyCrud() {
+			return validate.Usage("The {0} option is not available{1}.", "--history", " with search terms or any other option")
+		}
+		if !validate.IsValidAddress(opts.History) {
+			return validate.Usage("The {0} option requires a valid address.", "--history")
+		}
+	}
+
+	if len(opts.Rollback) > 0 {
+		if opts.Tags || opts.Addr || opts.Ens || opts.Clean || len(opts.Autoname) > 0 || len(opts.Import) > 0 || len(opts.History) > 0 || opts.anyCrud() {
+			return validate.Usage("The {0} option is not available{1}.", "--rollback", " with any other editing option")
+		}
+		if _, err := parseRollbackTime(opts.Rollback); err != nil {
+			return validate.Usage("The {0} option requires a date (YYYY-MM-DD[THH[:MM[:SS]]]) or a timestamp.", "--rollback")
+		}
+		for _, term := range opts.Terms {
+			if !validate.IsValidAddress(term) {
+				return validate.Usage("The terms of the {0} option must be addresses, found {1}.", "--rollback", term)
+			}
+		}
+	}
+
+	if opts.DryRun && len(opts.Import) == 0 && len(opts.Rollback) == 0 {
+		return validate.Usage("The {0} option is only available with the {1} option.", "--dry_run", "--import or --rollback")
+	}
+
+	if len(opts.Autoname) > 0 {
+		if validate.IsValidAddress(opts.Autoname) {
+			if addr := base.HexToAddress(opts.Autoname); addr.IsZero() {
+				return validate.Usage("You must provide a non-zero address to the {0} option.", "--autoname")
+			}
+		} else if !file.FileExists(opts.Autoname) {
+			return validate.Usage("You must provide an address or a file of addresses to the {0} option.", "--autoname")
+		}
 	}
 
 	return opts.Globals.Validate()
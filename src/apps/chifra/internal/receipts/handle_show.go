@@ -7,7 +7,6 @@ import (
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
-	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/cache"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
@@ -18,7 +17,7 @@ import (
 func (opts *ReceiptsOptions) HandleShowReceipts() error {
 
 	abiMap := make(abi.AbiInterfaceMap)
-	loadedMap := make(map[base.Address]bool)
+	loadedMap := make(abi.LoadedAbis)
 	chain := opts.Globals.Chain
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -75,12 +74,10 @@ func (opts *ReceiptsOptions) HandleShowReceipts() error {
 				if opts.Articulate {
 					for index, log := range receipt.Logs {
 						var err error
-						if !loadedMap[log.Address] {
-							if err = abi.LoadAbi(chain, log.Address, abiMap); err != nil {
-								// continue processing even with an error
-								errorChan <- err
-								err = nil
-							}
+						if err = loadedMap.Load(chain, log.Address, uint64(log.BlockNumber), abiMap); err != nil {
+							// continue processing even with an error
+							errorChan <- err
+							err = nil
 						}
 						if err == nil {
 							receipt.Logs[index].ArticulatedLog, err = articulate.ArticulateLog(&log, abiMap)
@@ -88,6 +85,12 @@ func (opts *ReceiptsOptions) HandleShowReceipts() error {
 								// continue processing even with an error
 								errorChan <- err
 							}
+							if receipt.Logs[index].ArticulatedLog == nil {
+								if receipt.Logs[index].ArticulatedLog, err = articulate.FindEvent(chain, &log); err != nil {
+									// continue processing even with an error
+									errorChan <- err
+								}
+							}
 						}
 					}
 				}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/colors"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
@@ -17,6 +18,7 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpc"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/uniq"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
 	"github.com/ethereum/go-ethereum/common"
@@ -45,6 +47,7 @@ type BlazeOptions struct {
 	BlockWg       sync.WaitGroup             `json:"-"`
 	AppearanceWg  sync.WaitGroup             `json:"-"`
 	TsWg          sync.WaitGroup             `json:"-"`
+	Throttle      *throttle                  `json:"-"`
 }
 
 func (opts *BlazeOptions) String() string {
@@ -136,10 +139,11 @@ func (opts *BlazeOptions) BlazeProcessBlocks(meta *rpcClient.MetaData, blockChan
 			Method: "trace_block",
 			Params: rpc.Params{fmt.Sprintf("0x%x", blockNum)},
 		}
-		err = rpc.FromRpc(opts.RpcProvider, &tracePayload, &traces)
+		err = opts.timedFromRpc(&tracePayload, &traces)
 		if err != nil {
-			// fmt.Println("rpcCall failed at block", blockNum, err)
-			return err
+			// The block will be reported as unprocessed at the end of the pass. We continue
+			// so the remaining blocks drain from the channel and the error rate is measured.
+			continue
 		}
 
 		var logs rpcClient.Logs
@@ -148,10 +152,9 @@ func (opts *BlazeOptions) BlazeProcessBlocks(meta *rpcClient.MetaData, blockChan
 			Method: "eth_getLogs",
 			Params: rpc.Params{rpcClient.LogFilter{Fromblock: fmt.Sprintf("0x%x", blockNum), Toblock: fmt.Sprintf("0x%x", blockNum)}},
 		}
-		err = rpc.FromRpc(opts.RpcProvider, &logsPayload, &logs)
+		err = opts.timedFromRpc(&logsPayload, &logs)
 		if err != nil {
-			// fmt.Println("rpcCall failed at block", blockNum, err)
-			return err
+			continue
 		}
 
 		appearanceChannel <- ScrapedData{
@@ -161,8 +164,8 @@ func (opts *BlazeOptions) BlazeProcessBlocks(meta *rpcClient.MetaData, blockChan
 		}
 
 		ts := tslib.TimestampRecord{
-			Bn: uint32(blockNum),
-			Ts: uint32(rpc.GetBlockTimestamp(opts.Chain, uint64(blockNum))),
+			Bn: uint64(blockNum),
+			Ts: uint64(rpc.GetBlockTimestamp(opts.Chain, uint64(blockNum))),
 		}
 		tsChannel <- ts
 	}
@@ -170,6 +173,16 @@ func (opts *BlazeOptions) BlazeProcessBlocks(meta *rpcClient.MetaData, blockChan
 	return
 }
 
+// timedFromRpc calls the RPC and, if there is a throttle, records the call's latency and outcome
+func (opts *BlazeOptions) timedFromRpc(payload *rpc.Payload, result interface{}) error {
+	start := time.Now()
+	err := rpc.FromRpc(opts.RpcProvider, payload, result)
+	if opts.Throttle != nil {
+		opts.Throttle.record(time.Since(start), err)
+	}
+	return err
+}
+
 var blazeMutex sync.Mutex
 
 // BlazeProcessAppearances processes ScrapedData objects shoved down the appearanceChannel
@@ -240,11 +253,11 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 		if traces.Result[i].Type == "call" {
 			// If it's a call, get the to and from
 			from := traces.Result[i].Action.From
-			if isAddress(from) {
+			if uniq.IsAddress(from) {
 				opts.AddToMaps(from, bn, txid, addressMap)
 			}
 			to := traces.Result[i].Action.To
-			if isAddress(to) {
+			if uniq.IsAddress(to) {
 				opts.AddToMaps(to, bn, txid, addressMap)
 			}
 
@@ -259,7 +272,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 					opts.AddToMaps(author, bn, 99997, addressMap)
 
 				} else {
-					if isAddress(author) {
+					if uniq.IsAddress(author) {
 						opts.AddToMaps(author, bn, 99999, addressMap)
 					}
 				}
@@ -274,7 +287,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 					opts.AddToMaps(author, bn, 99998, addressMap)
 
 				} else {
-					if isAddress(author) {
+					if uniq.IsAddress(author) {
 						opts.AddToMaps(author, bn, 99998, addressMap)
 					}
 				}
@@ -282,7 +295,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 			} else if traces.Result[i].Action.RewardType == "external" {
 				// This only happens in xDai as far as we know...
 				author := traces.Result[i].Action.Author
-				if isAddress(author) {
+				if uniq.IsAddress(author) {
 					opts.AddToMaps(author, bn, 99996, addressMap)
 				}
 
@@ -294,22 +307,22 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 		} else if traces.Result[i].Type == "suicide" {
 			// add the contract that died, and where it sent it's money
 			address := traces.Result[i].Action.Address
-			if isAddress(address) {
+			if uniq.IsAddress(address) {
 				opts.AddToMaps(address, bn, txid, addressMap)
 			}
 			refundAddress := traces.Result[i].Action.RefundAddress
-			if isAddress(refundAddress) {
+			if uniq.IsAddress(refundAddress) {
 				opts.AddToMaps(refundAddress, bn, txid, addressMap)
 			}
 
 		} else if traces.Result[i].Type == "create" {
 			// add the creator, and the new address name
 			from := traces.Result[i].Action.From
-			if isAddress(from) {
+			if uniq.IsAddress(from) {
 				opts.AddToMaps(from, bn, txid, addressMap)
 			}
 			address := traces.Result[i].Result.Address
-			if isAddress(address) {
+			if uniq.IsAddress(address) {
 				opts.AddToMaps(address, bn, txid, addressMap)
 			}
 
@@ -320,7 +333,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 					initData := traces.Result[i].Action.Init[10:]
 					for i := 0; i < len(initData)/64; i++ {
 						addr := string(initData[i*64 : (i+1)*64])
-						if isImplicitAddress(addr) {
+						if uniq.IsImplicitAddress(addr) {
 							opts.AddToMaps(addr, bn, txid, addressMap)
 						}
 					}
@@ -377,7 +390,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 
 						} else {
 							addr := hexutil.Encode(receipt.ContractAddress.Bytes())
-							if isAddress(addr) {
+							if uniq.IsAddress(addr) {
 								opts.AddToMaps(addr, bn, txid, addressMap)
 							}
 						}
@@ -396,7 +409,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 			//fmt.Println("Input data:", inputData, len(inputData))
 			for i := 0; i < len(inputData)/64; i++ {
 				addr := string(inputData[i*64 : (i+1)*64])
-				if isImplicitAddress(addr) {
+				if uniq.IsImplicitAddress(addr) {
 					opts.AddToMaps(addr, bn, txid, addressMap)
 				}
 			}
@@ -407,7 +420,7 @@ func (opts *BlazeOptions) BlazeExtractFromTraces(bn int, traces *rpcClient.Trace
 			outputData := traces.Result[i].Result.Output[2:]
 			for i := 0; i < len(outputData)/64; i++ {
 				addr := string(outputData[i*64 : (i+1)*64])
-				if isImplicitAddress(addr) {
+				if uniq.IsImplicitAddress(addr) {
 					opts.AddToMaps(addr, bn, txid, addressMap)
 				}
 			}
@@ -427,7 +440,7 @@ func (opts *BlazeOptions) BlazeExtractFromLogs(bn int, logs *rpcClient.Logs, add
 		txid, _ := strconv.ParseInt(logs.Result[i].TransactionIndex, 0, 32)
 		for j := 0; j < len(logs.Result[i].Topics); j++ {
 			addr := string(logs.Result[i].Topics[j][2:])
-			if isImplicitAddress(addr) {
+			if uniq.IsImplicitAddress(addr) {
 				opts.AddToMaps(addr, bn, int(txid), addressMap)
 			}
 		}
@@ -436,7 +449,7 @@ func (opts *BlazeOptions) BlazeExtractFromLogs(bn int, logs *rpcClient.Logs, add
 			inputData := logs.Result[i].Data[2:]
 			for i := 0; i < len(inputData)/64; i++ {
 				addr := string(inputData[i*64 : (i+1)*64])
-				if isImplicitAddress(addr) {
+				if uniq.IsImplicitAddress(addr) {
 					opts.AddToMaps(addr, bn, int(txid), addressMap)
 				}
 			}
@@ -479,43 +492,6 @@ func (opts *BlazeOptions) WriteAppearancesBlaze(meta *rpcClient.MetaData, bn int
 	return
 }
 
-// isAddress Returns true if the address is not a precompile and not the zero address
-func isAddress(addr string) bool {
-	// As per EIP 1352, all addresses less or equal to the following value are reserved for pre-compiles.
-	// We don't index precompiles. https://eips.ethereum.org/EIPS/eip-1352
-	return addr > "0x000000000000000000000000000000000000ffff"
-}
-
-// isImplicitAddress processes a transaction's 'input' data and 'output' data or an event's data field.
-// Anything with 12 bytes of leading zeros but not more than 19 leading zeros (24 and 38 characters
-// respectively).
-func isImplicitAddress(addr string) bool {
-	// Any 32-byte value smaller than this number is assumed to be a 'value'. We call them baddresses.
-	// While this may seem like a lot of addresses being labeled as baddresses, it's not very many:
-	// ---> 2 out of every 10000000000000000000000000000000000000000000000 are baddresses.
-	small := "00000000000000000000000000000000000000ffffffffffffffffffffffffff"
-	//        -------+-------+-------+-------+-------+-------+-------+-------+
-	if addr <= small {
-		return false
-	}
-
-	// Any 32-byte value with less than this many leading zeros is not an address (address are 20-bytes and
-	// zero padded to the left)
-	largePrefix := "000000000000000000000000"
-	//              -------+-------+-------+
-	if !strings.HasPrefix(addr, largePrefix) {
-		return false
-	}
-
-	// Of the valid addresses, we assume any ending with this many trailing zeros is also a baddress.
-	if strings.HasSuffix(addr, "00000000") {
-		return false
-	}
-
-	// extract the potential address
-	return isAddress("0x" + string(addr[24:]))
-}
-
 var (
 	locker uint32
 )
@@ -535,7 +511,7 @@ func (opts *BlazeOptions) syncedReporting(bn int, force bool) {
 		if opts.RipeBlock > uint64(bn) {
 			dist = (opts.RipeBlock - uint64(bn))
 		}
-		msg := fmt.Sprintf("Scraping %-04d of %-04d at block %d of %d (%d blocks from head)", opts.NProcessed, opts.BlockCount, bn, opts.RipeBlock, dist)
+		msg := fmt.Sprintf("Scraping %-04d of %-04d at block %d of %d (%d blocks from head, %d channels)", opts.NProcessed, opts.BlockCount, bn, opts.RipeBlock, dist, opts.NChannels)
 		logger.Progress(true, msg)
 	}
 }
@@ -49,8 +49,8 @@ func (opts *ScrapeOptions) HandlePrepare(progressThen *rpcClient.MetaData, blaze
 
 	array := []tslib.TimestampRecord{}
 	array = append(array, tslib.TimestampRecord{
-		Bn: uint32(0),
-		Ts: uint32(rpc.GetBlockTimestamp(opts.Globals.Chain, 0)),
+		Bn: uint64(0),
+		Ts: uint64(rpc.GetBlockTimestamp(opts.Globals.Chain, 0)),
 	})
 	tslib.Append(opts.Globals.Chain, array)
 
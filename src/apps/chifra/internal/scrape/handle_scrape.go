@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/colors"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config/scrapeCfg"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/index"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
@@ -22,9 +24,37 @@ import (
 // TODO: We should repond to non-tracing (i.e. Geth) nodes better
 // TODO: Make sure we're not running acctScrape and/or pause if it's running
 func (opts *ScrapeOptions) HandleScrape() error {
+	runner, ok, err := opts.NewScrapeRunner()
+	if !ok || err != nil {
+		return err
+	}
+
+	for {
+		if ok, err := runner.RunPass(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		opts.Pause(runner.Progress)
+	}
+
+	return nil
+}
+
+// ScrapeRunner carries the scraper's state from one pass to the next. HandleScrape uses it to run
+// forever. Other processes (such as the daemon) may use it to run the scraper one pass at a time.
+type ScrapeRunner struct {
+	Progress *rpcClient.MetaData
+	opts     *ScrapeOptions
+	throttle *throttle
+}
+
+// NewScrapeRunner validates the options and performs the actions that need to happen prior to the
+// first pass. Returns false if the scraper should not continue.
+func (opts *ScrapeOptions) NewScrapeRunner() (*ScrapeRunner, bool, error) {
 	progress, err := rpcClient.GetMetaData(opts.Globals.Chain, opts.Globals.TestMode)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	blazeOpts := BlazeOptions{
@@ -41,86 +71,118 @@ func (opts *ScrapeOptions) HandleScrape() error {
 	}
 
 	if ok, err := opts.HandlePrepare(progress, &blazeOpts); !ok || err != nil {
-		return err
+		return nil, false, err
 	}
 
-	origBlockCnt := opts.BlockCnt
-	for {
-		progress, err = rpcClient.GetMetaData(opts.Globals.Chain, opts.Globals.TestMode)
-		if err != nil {
-			return err
-		}
+	// The throttle picks the number of channels and the number of blocks for each pass. If the
+	// scraper is not adaptive, these are the configured values.
+	return &ScrapeRunner{
+		Progress: progress,
+		opts:     opts,
+		throttle: newThrottle(&opts.Settings, opts.BlockCnt),
+	}, true, nil
+}
 
-		// We start the current round one block past the end of the previous round
-		opts.StartBlock = utils.Max(progress.Ripe, utils.Max(progress.Staging, progress.Finalized)) + 1
-		// And each round we assume we're going to process this many blocks...
-		opts.BlockCnt = origBlockCnt
-		if (opts.StartBlock + opts.BlockCnt) > progress.Latest {
-			// ...unless we're too close to the head, then we shorten the number of blocks to process
-			opts.BlockCnt = (progress.Latest - opts.StartBlock)
-		}
+// Throttle returns the channel count and block count the scraper chose for its next pass
+func (r *ScrapeRunner) Throttle() *scrapeCfg.ThrottleState {
+	return r.throttle.state()
+}
 
-		// The 'ripeBlock' is the head of the chain unless the chain is further along
-		// than 'UnripeDist.' If it is, the `ripeBlock` is 'UnripeDist' behind the
-		// head (i.e., 28 blocks usually - six minutes)
-		ripeBlock := progress.Latest
-		if ripeBlock > opts.Settings.Unripe_dist {
-			ripeBlock = progress.Latest - opts.Settings.Unripe_dist
-		}
+// RunPass runs a single pass of the scraper. Problems with the pass itself are reported and the
+// pass will be retried, so the only errors returned are those from which we cannot recover. Returns
+// false if the scraper should stop.
+func (r *ScrapeRunner) RunPass() (bool, error) {
+	opts := r.opts
+	throttle := r.throttle
 
-		blazeOpts = BlazeOptions{
-			Chain:         opts.Globals.Chain,
-			NChannels:     opts.Settings.Channel_count,
-			NProcessed:    0,
-			StartBlock:    opts.StartBlock,
-			BlockCount:    opts.BlockCnt,
-			RipeBlock:     ripeBlock,
-			UnripeDist:    opts.Settings.Unripe_dist,
-			RpcProvider:   config.GetRpcProvider(opts.Globals.Chain),
-			AppearanceMap: make(index.AddressAppearanceMap, opts.Settings.Apps_per_chunk),
-			TsArray:       make([]tslib.TimestampRecord, 0, opts.BlockCnt),
-			ProcessedMap:  make(map[int]bool, opts.BlockCnt),
+	progress, err := rpcClient.GetMetaData(opts.Globals.Chain, opts.Globals.TestMode)
+	if err != nil {
+		return false, err
+	}
+	r.Progress = progress
+
+	defer func() {
+		throttle.adjust()
+		state := throttle.state()
+		reportThrottle(state)
+		if err := scrapeCfg.WriteThrottleState(opts.Globals.Chain, state); err != nil {
+			logger.Warn("Could not write scraper throttle state:", err)
 		}
+	}()
+
+	// We start the current round one block past the end of the previous round
+	opts.StartBlock = utils.Max(progress.Ripe, utils.Max(progress.Staging, progress.Finalized)) + 1
+	// And each round we assume we're going to process this many blocks...
+	nChannels, blockCnt := throttle.current()
+	opts.BlockCnt = blockCnt
+	if (opts.StartBlock + opts.BlockCnt) > progress.Latest {
+		// ...unless we're too close to the head, then we shorten the number of blocks to process
+		opts.BlockCnt = (progress.Latest - opts.StartBlock)
+	}
 
-		// Remove whatever's in the unripePath before running each round. We do this
-		// because the chain may have re-organized (which it does frequently). This is
-		// why we have an unripePath.
-		unripePath := filepath.Join(config.GetPathToIndex(opts.Globals.Chain), "unripe")
-		err = os.RemoveAll(unripePath)
-		if err != nil {
-			return err
-		}
+	// The 'ripeBlock' is the head of the chain unless the chain is further along
+	// than 'UnripeDist.' If it is, the `ripeBlock` is 'UnripeDist' behind the
+	// head (i.e., 28 blocks usually - six minutes)
+	ripeBlock := progress.Latest
+	if ripeBlock > opts.Settings.Unripe_dist {
+		ripeBlock = progress.Latest - opts.Settings.Unripe_dist
+	}
 
-		// In some cases, the index may be already ahead of the chain tip. (For example,
-		// we may be dealing with a node installation that is being re-synced, but the
-		// index already exists.) In this case, we sleep for a while to allow the chain
-		// to catch up.
-		m := utils.Max(progress.Ripe, utils.Max(progress.Staging, progress.Finalized)) + 1
-		if m > progress.Latest {
-			fmt.Println(validate.Usage("The index ({0}) is ahead of the chain ({1}).", fmt.Sprintf("%d", m), fmt.Sprintf("%d", progress.Latest)))
-			goto PAUSE
-		}
+	blazeOpts := BlazeOptions{
+		Chain:         opts.Globals.Chain,
+		NChannels:     nChannels,
+		NProcessed:    0,
+		StartBlock:    opts.StartBlock,
+		BlockCount:    opts.BlockCnt,
+		RipeBlock:     ripeBlock,
+		UnripeDist:    opts.Settings.Unripe_dist,
+		RpcProvider:   config.GetRpcProvider(opts.Globals.Chain),
+		AppearanceMap: make(index.AddressAppearanceMap, opts.Settings.Apps_per_chunk),
+		TsArray:       make([]tslib.TimestampRecord, 0, opts.BlockCnt),
+		ProcessedMap:  make(map[int]bool, opts.BlockCnt),
+		Throttle:      throttle,
+	}
 
-		// Here we do the actual scrape for this round. If anything goes wrong, the
-		// function will have cleaned up (i.e. remove the unstaged ripe blocks). Note
-		// that we don't quit, instead we sleep and we retry continually.
-		if err := opts.HandleScrapeBlaze(progress, &blazeOpts); err != nil {
-			logger.Error(colors.BrightRed, err, colors.Off)
-			goto PAUSE
-		}
-		blazeOpts.syncedReporting(int(blazeOpts.StartBlock+blazeOpts.BlockCount), true /* force */)
-
-		if ok, err := opts.HandleScrapeConsolidate(progress, &blazeOpts); !ok || err != nil {
-			logger.Error(err)
-			if !ok {
-				break
-			}
-			goto PAUSE
-		}
+	// Remove whatever's in the unripePath before running each round. We do this
+	// because the chain may have re-organized (which it does frequently). This is
+	// why we have an unripePath.
+	unripePath := filepath.Join(config.GetPathToIndex(opts.Globals.Chain), "unripe")
+	err = os.RemoveAll(unripePath)
+	if err != nil {
+		return false, err
+	}
 
-	PAUSE:
-		opts.Pause(progress)
+	// In some cases, the index may be already ahead of the chain tip. (For example,
+	// we may be dealing with a node installation that is being re-synced, but the
+	// index already exists.) In this case, we sleep for a while to allow the chain
+	// to catch up.
+	m := utils.Max(progress.Ripe, utils.Max(progress.Staging, progress.Finalized)) + 1
+	if m > progress.Latest {
+		fmt.Println(validate.Usage("The index ({0}) is ahead of the chain ({1}).", fmt.Sprintf("%d", m), fmt.Sprintf("%d", progress.Latest)))
+		return true, nil
 	}
 
-	return nil
+	// Here we do the actual scrape for this round. If anything goes wrong, the
+	// function will have cleaned up (i.e. remove the unstaged ripe blocks). Note
+	// that we don't quit, instead we sleep and we retry continually.
+	passStart := time.Now()
+	if err := opts.HandleScrapeBlaze(progress, &blazeOpts); err != nil {
+		logger.Error(colors.BrightRed, err, colors.Off)
+		scrapePassErrors.Inc()
+		return true, nil
+	}
+	blazeOpts.syncedReporting(int(blazeOpts.StartBlock+blazeOpts.BlockCount), true /* force */)
+	reportPass(progress, &blazeOpts, time.Since(passStart))
+
+	consolidateStart := time.Now()
+	if ok, err := opts.HandleScrapeConsolidate(progress, &blazeOpts); !ok || err != nil {
+		logger.Error(err)
+		if ok {
+			scrapePassErrors.Inc()
+		}
+		return ok, nil
+	}
+	scrapeConsolidation.ObserveSince(consolidateStart)
+
+	return true, nil
 }
@@ -5,10 +5,8 @@ package scrapePkg
 // be found in the LICENSE file.
 
 import (
-	"encoding/binary"
 	"errors"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
@@ -52,21 +50,25 @@ func WriteTimestamps(chain string, tsArray []tslib.TimestampRecord, endPoint uin
 	})
 
 	// Assume that the existing timestamps file always contains valid timestamps in a valid order so we can only append
-	tsPath := config.GetPathToIndex(chain) + "ts.bin"
-	fp, err := os.OpenFile(tsPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
-	if err != nil {
-		return err
+	nTs, _ := tslib.NextBn(chain)
+	if cnt, _ := tslib.NTimestamps(chain); cnt == 0 && len(tsArray) > 0 {
+		// A new file starts at the first block we scraped, which is not zero on chains with a later genesis
+		nTs = tsArray[0].Bn
 	}
 
-	defer func() {
-		tslib.DeCache(chain)
-		fp.Close()
-		// sigintTrap.Disable(trapCh)
-		// writeMutex.Unlock()
-	}()
-
-	nTs, _ := tslib.NTimestamps(chain)
+	if header, _ := tslib.Header(chain); header.Version == tslib.TsVersion2 {
+		// Version 2 files may have gaps, so we write what we scraped rather than filling in blocks
+		// the chain may not have
+		toWrite := make([]tslib.TimestampRecord, 0, len(tsArray))
+		for _, ts := range tsArray {
+			if ts.Bn >= nTs && ts.Bn < endPoint {
+				toWrite = append(toWrite, ts)
+			}
+		}
+		return tslib.Append(chain, toWrite)
+	}
 
+	toWrite := make([]tslib.TimestampRecord, 0, len(tsArray))
 	cnt := 0
 	for bn := nTs; bn < endPoint; bn++ {
 		// Append to the timestamps file all the new timestamps but as we do that make sure we're
@@ -74,26 +76,26 @@ func WriteTimestamps(chain string, tsArray []tslib.TimestampRecord, endPoint uin
 		ts := tslib.TimestampRecord{}
 		if cnt >= len(tsArray) {
 			ts = tslib.TimestampRecord{
-				Bn: uint32(bn),
-				Ts: uint32(rpc.GetBlockTimestamp(chain, bn)),
+				Bn: bn,
+				Ts: uint64(rpc.GetBlockTimestamp(chain, bn)),
 			}
 		} else {
 			ts = tsArray[cnt]
-			if tsArray[cnt].Bn != uint32(bn) {
+			if tsArray[cnt].Bn != bn {
 				ts = tslib.TimestampRecord{
-					Bn: uint32(bn),
-					Ts: uint32(rpc.GetBlockTimestamp(chain, bn)),
+					Bn: bn,
+					Ts: uint64(rpc.GetBlockTimestamp(chain, bn)),
 				}
 				cnt-- // set it back
 			}
 		}
 
 		logger.Progress((bn%13) == 0, fmt.Sprintf("Checking or updating timestamps %-04d of %-04d (%d remaining)%s", bn, endPoint, endPoint-bn, spaces))
-		if err = binary.Write(fp, binary.LittleEndian, &ts); err != nil {
-			return err
-		}
+		toWrite = append(toWrite, ts)
 
 		cnt++
 	}
-	return nil
+
+	// Append writes the records in the format of the existing file
+	return tslib.Append(chain, toWrite)
 }
@@ -0,0 +1,43 @@
+package scrapePkg
+
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+import (
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config/scrapeCfg"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/metrics"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+)
+
+var (
+	scrapeBlocks        = metrics.NewCounter("scraper_blocks", "Number of blocks scraped")
+	scrapePassErrors    = metrics.NewCounter("scraper_pass_errors", "Number of scraper passes that failed and were retried")
+	scrapeBlocksPerSec  = metrics.NewGauge("scraper_blocks_per_second", "Blocks per second processed during the most recent pass")
+	scrapeDistance      = metrics.NewGauge("scraper_distance_from_head", "Number of blocks between the end of the most recent pass and the head of the chain")
+	scrapeChannels      = metrics.NewGauge("scraper_channels", "Number of block processing channels chosen for the next pass")
+	scrapeBlockCnt      = metrics.NewGauge("scraper_block_cnt", "Number of blocks per pass chosen for the next pass")
+	scrapeConsolidation = metrics.NewSummary("scraper_consolidation_seconds", "Time spent consolidating ripe blocks into the staging file and chunks")
+)
+
+// reportPass updates the scraper's metrics after a successful pass
+func reportPass(progress *rpcClient.MetaData, blazeOpts *BlazeOptions, elapsed time.Duration) {
+	scrapeBlocks.Add(blazeOpts.NProcessed)
+	if elapsed > 0 {
+		scrapeBlocksPerSec.Set(float64(blazeOpts.NProcessed) / elapsed.Seconds())
+	}
+	end := blazeOpts.StartBlock + blazeOpts.BlockCount
+	dist := uint64(0)
+	if progress.Latest > end {
+		dist = progress.Latest - end
+	}
+	scrapeDistance.Set(float64(dist))
+}
+
+// reportThrottle updates the scraper's metrics with the throttle's most recent choices
+func reportThrottle(state *scrapeCfg.ThrottleState) {
+	scrapeChannels.Set(float64(state.Channels))
+	scrapeBlockCnt.Set(float64(state.BlockCnt))
+}
@@ -79,4 +79,8 @@ func (opts *ScrapeOptions) IsPorted() (ported bool) {
 }
 
 // EXISTING_CODE
+func (opts *ScrapeOptions) Validate() error {
+	return opts.validateScrape()
+}
+
 // EXISTING_CODE
@@ -10,12 +10,10 @@ import (
 func (opts *ScrapeOptions) Pause(progressThen *rpcClient.MetaData) {
 	// we always pause at least a quarter of a second to allow the node to 'rest'
 	time.Sleep(250 * time.Millisecond)
-	isDefaultSleep := opts.Sleep >= 13 && opts.Sleep <= 14
-	distanceFromHead := progressThen.Latest - progressThen.Staging
-	shouldSleep := !isDefaultSleep || distanceFromHead <= (2*opts.Settings.Unripe_dist)
-	if shouldSleep {
+	if opts.shouldSleep(progressThen) {
 		sleep := opts.Sleep
 		if sleep > 1 {
+			distanceFromHead := progressThen.Latest - progressThen.Staging
 			logger.Info("Sleeping for", sleep, "seconds -", distanceFromHead, "away from head.")
 		}
 		halfSecs := (sleep * 2) - 1 // we already slept one quarter of a second
@@ -25,3 +23,19 @@ func (opts *ScrapeOptions) Pause(progressThen *rpcClient.MetaData) {
 	}
 
 }
+
+// SleepDuration returns how long Pause would rest after a pass given the progress at the time of
+// the pass. It's used by callers that need to be able to interrupt the sleep.
+func (opts *ScrapeOptions) SleepDuration(progressThen *rpcClient.MetaData) time.Duration {
+	if !opts.shouldSleep(progressThen) {
+		return 250 * time.Millisecond
+	}
+	return time.Duration(opts.Sleep * float64(time.Second))
+}
+
+// shouldSleep returns true if we're close to the head or the user has asked for a non-default sleep
+func (opts *ScrapeOptions) shouldSleep(progressThen *rpcClient.MetaData) bool {
+	isDefaultSleep := opts.Sleep >= 13 && opts.Sleep <= 14
+	distanceFromHead := progressThen.Latest - progressThen.Staging
+	return !isDefaultSleep || distanceFromHead <= (2*opts.Settings.Unripe_dist)
+}
@@ -0,0 +1,140 @@
+package scrapePkg
+
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config/scrapeCfg"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// throttle adjusts the number of block processing channels and the number of blocks per
+// pass to the responsiveness of the node. It measures the latency and error rate of every
+// RPC call made during a pass and, between passes, applies additive-increase/multiplicative-decrease
+// to both values keeping them between the configured bounds. When not adaptive, the throttle
+// only measures and the configured values are used unchanged.
+type throttle struct {
+	adaptive    bool
+	channels    uint64
+	blockCnt    uint64
+	minChannels uint64
+	maxChannels uint64
+	minBlockCnt uint64
+	maxBlockCnt uint64
+
+	mutex     sync.Mutex
+	nCalls    uint64
+	nErrors   uint64
+	total     time.Duration
+	baseline  time.Duration // smoothed latency observed during healthy passes
+	lastCalls uint64
+	lastErrs  uint64
+	lastRate  float64
+	lastLat   time.Duration
+}
+
+const (
+	// maxErrorRate is the fraction of failed calls above which we back off
+	maxErrorRate = 0.01
+	// maxSlowdown is how much slower than the baseline calls may get before we back off
+	maxSlowdown = 2.0
+)
+
+func newThrottle(settings *scrapeCfg.ScrapeSettings, blockCnt uint64) *throttle {
+	maxChannels := utils.Max(1, settings.Channel_count)
+	maxBlockCnt := utils.Max(1, blockCnt)
+	t := throttle{
+		adaptive:    settings.Adaptive,
+		channels:    maxChannels,
+		blockCnt:    maxBlockCnt,
+		minChannels: utils.Min(utils.Max(1, settings.Channel_min), maxChannels),
+		maxChannels: maxChannels,
+		minBlockCnt: utils.Min(utils.Max(1, settings.Block_cnt_min), maxBlockCnt),
+		maxBlockCnt: maxBlockCnt,
+	}
+	if t.adaptive {
+		// Start conservatively and let the additive increase find the node's capacity
+		t.channels = t.minChannels
+		t.blockCnt = t.minBlockCnt
+	}
+	return &t
+}
+
+// record notes the duration and the outcome of a single RPC call
+func (t *throttle) record(latency time.Duration, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.nCalls++
+	t.total += latency
+	if err != nil {
+		t.nErrors++
+	}
+}
+
+// adjust is called at the end of each pass. It picks the channel count and block count for the
+// next pass based on the measurements taken during this one and resets the measurements.
+func (t *throttle) adjust() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.nCalls == 0 {
+		return
+	}
+
+	t.lastCalls, t.lastErrs = t.nCalls, t.nErrors
+	t.lastRate = float64(t.nErrors) / float64(t.nCalls)
+	t.lastLat = t.total / time.Duration(t.nCalls)
+	t.nCalls, t.nErrors, t.total = 0, 0, 0
+
+	healthy := t.lastRate <= maxErrorRate
+	if healthy && t.baseline > 0 {
+		healthy = float64(t.lastLat) <= maxSlowdown*float64(t.baseline)
+	}
+
+	if healthy {
+		if t.baseline == 0 {
+			t.baseline = t.lastLat
+		} else {
+			t.baseline = (3*t.baseline + t.lastLat) / 4
+		}
+	}
+
+	if !t.adaptive {
+		return
+	}
+
+	if healthy {
+		t.channels = utils.Min(t.maxChannels, t.channels+1)
+		t.blockCnt = utils.Min(t.maxBlockCnt, t.blockCnt+utils.Max(1, t.maxBlockCnt/10))
+	} else {
+		t.channels = utils.Max(t.minChannels, t.channels/2)
+		t.blockCnt = utils.Max(t.minBlockCnt, t.blockCnt/2)
+	}
+}
+
+// current returns the channel count and block count to use for the next pass
+func (t *throttle) current() (channels, blockCnt uint64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.channels, t.blockCnt
+}
+
+// state returns a snapshot of the throttle suitable for reporting
+func (t *throttle) state() *scrapeCfg.ThrottleState {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return &scrapeCfg.ThrottleState{
+		Adaptive:   t.adaptive,
+		Channels:   t.channels,
+		BlockCnt:   t.blockCnt,
+		LatencyMs:  float64(t.lastLat) / float64(time.Millisecond),
+		ErrorRate:  t.lastRate,
+		NCalls:     t.lastCalls,
+		NErrors:    t.lastErrs,
+		LastUpdate: time.Now().Unix(),
+	}
+}
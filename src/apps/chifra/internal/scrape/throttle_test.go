@@ -0,0 +1,70 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package scrapePkg
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config/scrapeCfg"
+)
+
+func Test_ThrottleAimd(t *testing.T) {
+	settings := scrapeCfg.ScrapeSettings{
+		Channel_count: 8,
+		Adaptive:      true,
+		Channel_min:   2,
+		Block_cnt_min: 100,
+	}
+	th := newThrottle(&settings, 1000)
+
+	if c, b := th.current(); c != 2 || b != 100 {
+		t.Error("expected adaptive throttle to start at its minimums, got", c, b)
+	}
+
+	// Healthy passes increase additively up to the maximums
+	for i := 0; i < 20; i++ {
+		th.record(10*time.Millisecond, nil)
+		th.adjust()
+	}
+	if c, b := th.current(); c != 8 || b != 1000 {
+		t.Error("expected healthy passes to reach the maximums, got", c, b)
+	}
+
+	// A pass with errors halves both values
+	th.record(10*time.Millisecond, nil)
+	th.record(10*time.Millisecond, errors.New("timeout"))
+	th.adjust()
+	if c, b := th.current(); c != 4 || b != 500 {
+		t.Error("expected an erroring pass to halve the values, got", c, b)
+	}
+
+	// A pass much slower than the baseline also backs off, but never below the minimums
+	for i := 0; i < 5; i++ {
+		th.record(time.Second, nil)
+		th.adjust()
+	}
+	if c, b := th.current(); c != 2 || b != 100 {
+		t.Error("expected slow passes to back off to the minimums, got", c, b)
+	}
+
+	state := th.state()
+	if !state.Adaptive || state.Channels != 2 || state.BlockCnt != 100 || state.NCalls != 1 {
+		t.Error("unexpected state", state)
+	}
+}
+
+func Test_ThrottleFixed(t *testing.T) {
+	settings := scrapeCfg.ScrapeSettings{
+		Channel_count: 20,
+	}
+	th := newThrottle(&settings, 2000)
+	th.record(time.Millisecond, errors.New("timeout"))
+	th.adjust()
+	if c, b := th.current(); c != 20 || b != 2000 {
+		t.Error("expected a non-adaptive throttle to keep the configured values, got", c, b)
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/colors"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config/scrapeCfg"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
@@ -48,6 +49,7 @@ type simpleStatus struct {
 	RPCProvider   string            `json:"rpcProvider,omitempty"`
 	Version       string            `json:"trueblocksVersion,omitempty"`
 	// EXISTING_CODE
+	Scraper string `json:"scraper,omitempty"`
 	// EXISTING_CODE
 }
 
@@ -96,6 +98,11 @@ func (s *simpleStatus) Model(showHidden bool, format string, extraOptions map[st
 		order = append(order, "progress")
 	}
 
+	if len(s.Scraper) > 0 {
+		model["scraper"] = s.Scraper
+		order = append(order, "scraper")
+	}
+
 	testMode := extraOptions["testMode"] == true
 	if len(s.Caches) > 0 {
 		if testMode {
@@ -185,6 +192,10 @@ func (opts *StatusOptions) GetSimpleStatus() (*simpleStatus, error) {
 		ChainId:       fmt.Sprint(meta.ChainId),
 	}
 
+	if throttle, err := scrapeCfg.ReadThrottleState(chain); err == nil && throttle != nil {
+		s.Scraper = throttle.String()
+	}
+
 	if testMode {
 		s.ClientVersion = "Client version"
 		s.Version = "GHC-TrueBlocks//vers-beta--git-hash---git-ts-"
@@ -195,6 +206,7 @@ func (opts *StatusOptions) GetSimpleStatus() (*simpleStatus, error) {
 		s.IndexPath = "--paths--"
 		s.Progress = "--client--, --final--, --staging--, --unripe-- ts: --ts--"
 		s.HasPinKey = false // the test machine doesn't have a key
+		s.Scraper = ""
 	}
 
 	return s, nil
@@ -244,7 +256,8 @@ INFO Chain Config Path: {{.ChainConfig}}
 INFO Cache Path:        {{.CachePath}}
 INFO Index Path:        {{.IndexPath}}
 INFO Progress:          {{.Progress}}
-`
+{{if .Scraper}}INFO Scraper:           {{.Scraper}}
+{{end}}`
 
 func mustParseUint(input any) (result uint64) {
 	result, _ = strconv.ParseUint(fmt.Sprint(input), 0, 64)
@@ -5,7 +5,6 @@ import (
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
-	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpc"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
@@ -14,7 +13,7 @@ import (
 
 func (opts *TracesOptions) HandleFilter() error {
 	abiMap := make(abi.AbiInterfaceMap)
-	loadedMap := make(map[base.Address]bool)
+	loadedMap := make(abi.LoadedAbis)
 	chain := opts.Globals.Chain
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -35,12 +34,10 @@ func (opts *TracesOptions) HandleFilter() error {
 			trace.Timestamp = rpc.GetBlockTimestamp(opts.Globals.Chain, uint64(trace.BlockNumber))
 			if opts.Articulate {
 				var err error
-				if !loadedMap[trace.Action.To] {
-					if err = abi.LoadAbi(chain, trace.Action.To, abiMap); err != nil {
-						// continue processing even with an error
-						errorChan <- err
-						err = nil
-					}
+				if err = loadedMap.Load(chain, trace.Action.To, uint64(trace.BlockNumber), abiMap); err != nil {
+					// continue processing even with an error
+					errorChan <- err
+					err = nil
 				}
 				if err == nil {
 					trace.ArticulatedTrace, err = articulate.ArticulateTrace(&trace, abiMap)
@@ -48,6 +45,22 @@ func (opts *TracesOptions) HandleFilter() error {
 						// continue processing even with an error
 						errorChan <- err
 					}
+					if trace.ArticulatedTrace == nil {
+						if trace.ArticulatedTrace, err = articulate.FindFunction(chain, trace.Action.Input); err != nil {
+							// continue processing even with an error
+							errorChan <- err
+						}
+					}
+					trace.ArticulatedTrace, err = articulate.ArticulateNested(chain, uint64(trace.BlockNumber), trace.Action.To, trace.ArticulatedTrace, abiMap, loadedMap)
+					if err != nil {
+						// continue processing even with an error
+						errorChan <- err
+					}
+					trace.ArticulatedError, err = articulate.ArticulateTraceError(&trace, abiMap)
+					if err != nil {
+						// continue processing even with an error
+						errorChan <- err
+					}
 				}
 			}
 			modelChan <- &trace
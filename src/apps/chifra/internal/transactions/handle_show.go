@@ -8,7 +8,6 @@ import (
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
-	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
@@ -17,7 +16,7 @@ import (
 
 func (opts *TransactionsOptions) HandleShowTxs() (err error) {
 	abiMap := make(abi.AbiInterfaceMap)
-	loadedMap := make(map[base.Address]bool)
+	loadedMap := make(abi.LoadedAbis)
 	chain := opts.Globals.Chain
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -41,76 +40,9 @@ func (opts *TransactionsOptions) HandleShowTxs() (err error) {
 				}
 
 				if opts.Articulate {
-					if !loadedMap[tx.To] {
-						if err = abi.LoadAbi(chain, tx.To, abiMap); err != nil {
-							// continue processing even with an error
-							errorChan <- err
-							err = nil
-						}
-					}
-
-					for index, log := range tx.Receipt.Logs {
-						var err error
-						if !loadedMap[log.Address] {
-							if err = abi.LoadAbi(chain, log.Address, abiMap); err != nil {
-								// continue processing even with an error
-								errorChan <- err
-								err = nil
-							}
-						}
-						if err == nil {
-							tx.Receipt.Logs[index].ArticulatedLog, err = articulate.ArticulateLog(&log, abiMap)
-							if err != nil {
-								// continue processing even with an error
-								errorChan <- err
-							}
-						}
-					}
-
-					for index, trace := range tx.Traces {
-						var err error
-						if !loadedMap[trace.Action.To] {
-							if err = abi.LoadAbi(chain, trace.Action.To, abiMap); err != nil {
-								// continue processing even with an error
-								errorChan <- err
-								err = nil
-							}
-						}
-						if err == nil {
-							tx.Traces[index].ArticulatedTrace, err = articulate.ArticulateTrace(&trace, abiMap)
-							if err != nil {
-								// continue processing even with an error
-								errorChan <- err
-							}
-						}
-					}
-
-					var found *types.SimpleFunction
-					var selector string
-					if len(tx.Input) >= 10 {
-						selector = tx.Input[:10]
-						inputData := tx.Input[10:]
-						found = abiMap[selector]
-						if found != nil {
-							tx.ArticulatedTx = found
-							var outputData string
-							if len(tx.Traces) > 0 && tx.Traces[0].Result != nil && len(tx.Traces[0].Result.Output) > 2 {
-								outputData = tx.Traces[0].Result.Output[2:]
-							}
-							if err = articulate.ArticulateFunction(tx.ArticulatedTx, inputData, outputData); err != nil {
-								// continue processing even with an error
-								errorChan <- err
-							}
-						}
-					}
-
-					if found == nil && len(tx.Input) > 0 {
-						if message, ok := articulate.ArticulateString(tx.Input); ok {
-							tx.Message = message
-							// } else if len(selector) > 0 {
-							// 	// don't report this error
-							// 	errorChan <- fmt.Errorf("method/event not found: %s", selector)
-						}
+					for _, err := range articulate.ArticulateTransaction(chain, tx, abiMap, loadedMap) {
+						// continue processing even with an error
+						errorChan <- err
 					}
 				}
 				modelChan <- tx
@@ -0,0 +1,81 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package transactionsPkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/names"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/transfer"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/ethereum/go-ethereum"
+)
+
+// HandleTransfers reports the transfers of ETH and tokens made by each transaction, optionally
+// only those of the --asset(s). ETH transfers come from the traces, so if the node does not
+// provide traces, only the transaction's own value is reported.
+func (opts *TransactionsOptions) HandleTransfers() error {
+	chain := opts.Globals.Chain
+	findName := func(addr base.Address) (types.SimpleName, bool) {
+		return names.FindName(chain, addr)
+	}
+
+	ethSymbol := config.GetSymbol(chain)
+	if len(ethSymbol) == 0 {
+		ethSymbol = "ETH"
+	}
+
+	assets := make(map[base.Address]bool, len(opts.Asset))
+	for _, asset := range opts.Asset {
+		assets[base.HexToAddress(asset)] = true
+	}
+
+	tokens := transfer.NewTokenCache(chain)
+	withTraces := rpcClient.IsTracingNode(opts.Globals.TestMode, chain)
+	if !withTraces {
+		logger.Warn("The node does not provide traces, so ETH transfers made by contracts will be missing.")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	fetchData := func(modelChan chan types.Modeler[types.RawTransfer], errorChan chan error) {
+		for _, rng := range opts.TransactionIds {
+			txIds, err := rng.ResolveTxs(chain)
+			if err != nil && !errors.Is(err, ethereum.NotFound) {
+				errorChan <- err
+				cancel()
+			}
+
+			for _, appearance := range txIds {
+				tx, err := rpcClient.GetTransactionByAppearance(chain, &appearance, withTraces)
+				if err != nil {
+					errorChan <- fmt.Errorf("transaction at %s returned an error: %w", strings.Replace(rng.Orig, "-", ".", -1), err)
+					continue
+				}
+				if tx == nil {
+					errorChan <- fmt.Errorf("transaction at %s not found", strings.Replace(rng.Orig, "-", ".", -1))
+					continue
+				}
+
+				transfers := transfer.FromTransaction(tx, ethSymbol, findName)
+				tokens.Describe(transfers)
+				for _, t := range transfers {
+					t := t
+					if len(assets) == 0 || assets[t.AssetAddr] {
+						modelChan <- &t
+					}
+				}
+			}
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
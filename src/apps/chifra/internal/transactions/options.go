@@ -28,6 +28,8 @@ type TransactionsOptions struct {
 	Traces         bool                     `json:"traces,omitempty"`         // Include the transaction's traces in the results
 	Uniq           bool                     `json:"uniq,omitempty"`           // Display a list of uniq addresses found in the transaction
 	Flow           string                   `json:"flow,omitempty"`           // For the uniq option only, export only from or to (including trace from or to)
+	Transfers      bool                     `json:"transfers,omitempty"`      // Export the transfers of ETH and tokens made by the transaction(s)
+	Asset          []string                 `json:"asset,omitempty"`          // For the --transfers option only, export only transfers of this asset(s)
 	Reconcile      string                   `json:"reconcile,omitempty"`      // Please use --account_for option instead
 	AccountFor     string                   `json:"accountFor,omitempty"`     // Reconcile the transaction as per the provided address
 	Cache          bool                     `json:"cache,omitempty"`          // Force the results of the query into the tx cache (and the trace cache if applicable)
@@ -48,6 +50,8 @@ func (opts *TransactionsOptions) testLog() {
 	logger.TestLog(opts.Traces, "Traces: ", opts.Traces)
 	logger.TestLog(opts.Uniq, "Uniq: ", opts.Uniq)
 	logger.TestLog(len(opts.Flow) > 0, "Flow: ", opts.Flow)
+	logger.TestLog(opts.Transfers, "Transfers: ", opts.Transfers)
+	logger.TestLog(len(opts.Asset) > 0, "Asset: ", opts.Asset)
 	logger.TestLog(len(opts.AccountFor) > 0, "AccountFor: ", opts.AccountFor)
 	logger.TestLog(opts.Cache, "Cache: ", opts.Cache)
 	logger.TestLog(opts.Decache, "Decache: ", opts.Decache)
@@ -116,6 +120,13 @@ func transactionsFinishParseApi(w http.ResponseWriter, r *http.Request) *Transac
 			opts.Uniq = true
 		case "flow":
 			opts.Flow = value[0]
+		case "transfers":
+			opts.Transfers = true
+		case "asset":
+			for _, val := range value {
+				s := strings.Split(val, " ") // may contain space separated items
+				opts.Asset = append(opts.Asset, s...)
+			}
 		case "reconcile":
 			opts.Reconcile = value[0]
 		case "accountFor":
@@ -136,6 +147,7 @@ func transactionsFinishParseApi(w http.ResponseWriter, r *http.Request) *Transac
 	opts.Globals = *globals.GlobalsFinishParseApi(w, r)
 	// EXISTING_CODE
 	opts.AccountFor, _ = ens.ConvertOneEns(opts.Globals.Chain, opts.AccountFor)
+	opts.Asset, _ = ens.ConvertEns(opts.Globals.Chain, opts.Asset)
 	if len(opts.AccountFor) == 0 && len(opts.Reconcile) > 0 {
 		opts.AccountFor = opts.Reconcile
 	}
@@ -152,6 +164,7 @@ func transactionsFinishParse(args []string) *TransactionsOptions {
 	// EXISTING_CODE
 	opts.Transactions = args
 	opts.AccountFor, _ = ens.ConvertOneEns(opts.Globals.Chain, opts.AccountFor)
+	opts.Asset, _ = ens.ConvertEns(opts.Globals.Chain, opts.Asset)
 	if len(opts.AccountFor) == 0 && len(opts.Reconcile) > 0 {
 		opts.AccountFor = opts.Reconcile
 	}
@@ -56,6 +56,8 @@ func (opts *TransactionsOptions) TransactionsInternal() (err error, handled bool
 			return opts.HandleDecache(), true
 		} else if opts.Source {
 			return opts.HandleSource(), true
+		} else if opts.Transfers {
+			return opts.HandleTransfers(), true
 		} else if opts.Uniq {
 			return opts.HandleUniq()
 		} else if len(opts.AccountFor) > 0 {
@@ -91,6 +93,10 @@ func (opts *TransactionsOptions) IsPorted() (ported bool) {
 		return true
 	}
 
+	if opts.Transfers {
+		return true
+	}
+
 	if opts.Uniq {
 		return os.Getenv("Uniq") == "true"
 	}
@@ -26,6 +26,25 @@ func (opts *TransactionsOptions) validateTransactions() error {
 		}
 	}
 
+	if opts.Transfers {
+		if opts.Uniq {
+			return validate.Usage("The {0} option is not available with the {1} option", "--uniq", "--transfers")
+		}
+		if len(opts.AccountFor) > 0 {
+			return validate.Usage("The {0} option is not available with the {1} option", "--account_for", "--transfers")
+		}
+		if opts.Cache {
+			return validate.Usage("The {0} option is not available with the {1} option", "--cache", "--transfers")
+		}
+		for _, asset := range opts.Asset {
+			if !validate.IsValidAddress(asset) {
+				return validate.Usage("Invalid asset address {0}.", asset)
+			}
+		}
+	} else if len(opts.Asset) > 0 {
+		return validate.Usage("The {0} option is only available with the {1} option", "--asset", "--transfers")
+	}
+
 	if len(opts.Globals.File) > 0 {
 		// Do nothing
 	} else {
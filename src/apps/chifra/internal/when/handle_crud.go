@@ -0,0 +1,61 @@
+package whenPkg
+
+import (
+	"context"
+	"os"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/output"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// HandleCrud creates, moves, or removes a user-defined named block, or adds the chain's hard forks
+// to its special blocks. The changed blocks are displayed.
+func (opts *WhenOptions) HandleCrud() (err error) {
+	chain := opts.Globals.Chain
+
+	var results []types.SimpleNamedBlock
+	if len(opts.Forks) > 0 {
+		var chainConfig []byte
+		if chainConfig, err = os.ReadFile(opts.Forks); err != nil {
+			return err
+		}
+		var forks []types.SimpleNamedBlock
+		if forks, err = tslib.GetForkSpecials(chain, chainConfig); err != nil {
+			return err
+		}
+		if results, err = tslib.WriteForkSpecials(chain, forks); err != nil {
+			return err
+		}
+
+	} else {
+		var special *types.SimpleNamedBlock
+		if len(opts.Remove) > 0 {
+			special, err = tslib.RemoveUserSpecial(chain, opts.Remove)
+		} else {
+			var bns []uint64
+			if bns, err = opts.BlockIds[0].ResolveBlocks(chain); err != nil {
+				return err
+			}
+			if len(opts.Create) > 0 {
+				special, err = tslib.CreateUserSpecial(chain, opts.Create, bns[0])
+			} else {
+				special, err = tslib.UpdateUserSpecial(chain, opts.Edit, bns[0])
+			}
+		}
+		if err != nil {
+			return err
+		}
+		results = append(results, *special)
+	}
+
+	ctx := context.Background()
+	fetchData := func(modelChan chan types.Modeler[types.RawNamedBlock], errorChan chan error) {
+		for _, result := range results {
+			result := result
+			modelChan <- &result
+		}
+	}
+
+	return output.StreamMany(ctx, fetchData, opts.Globals.OutputOpts())
+}
@@ -14,13 +14,23 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
 )
 
-// HandleTimestampsCheck handles chifra when --timestamps --check
+// HandleTimestampsCheck handles chifra when --timestamps --check. Both versions of the timestamp
+// file are understood. Version 1 files must hold every block at its position. Version 2 files may
+// have gaps, so only the ordering of their records (and, with --deep, their values) is checked.
 func (opts *WhenOptions) HandleTimestampsCheck() error {
 	cnt, err := tslib.NTimestamps(opts.Globals.Chain)
 	if err != nil {
 		return err
 	}
 
+	header, err := tslib.Header(opts.Globals.Chain)
+	if err != nil {
+		return err
+	}
+	if header.Version != tslib.TsVersion1 {
+		logger.Info("Checking version", header.Version, "timestamp file starting at block", header.FirstBn, "with", cnt, "records")
+	}
+
 	// For display only
 	skip := uint64(500)
 	if opts.Deep {
@@ -41,15 +51,25 @@ func (opts *WhenOptions) HandleTimestampsCheck() error {
 
 	if len(blockNums) > 0 {
 		for _, bn := range blockNums {
-			if bn < cnt { // ranges may include blocks after last block
-				if err = opts.checkOneBlock(scanBar, &prev, bn); err != nil {
+			// ranges may include blocks after last block (or, in version 2 files, in gaps)
+			if itemOnDisc, err := tslib.FromBn(opts.Globals.Chain, bn); err == nil {
+				if err = opts.checkOneBlock(scanBar, &prev, &header, bn, itemOnDisc); err != nil {
 					return err
 				}
 			}
 		}
 	} else {
-		for bn := uint64(0); bn < cnt; bn++ {
-			if err = opts.checkOneBlock(scanBar, &prev, bn); err != nil {
+		for index := uint64(0); index < cnt; index++ {
+			itemOnDisc, err := tslib.FromIndex(opts.Globals.Chain, index)
+			if err != nil {
+				return err
+			}
+			// In a version 1 file, the i'th item is the i'th block
+			bn := index
+			if header.Version != tslib.TsVersion1 {
+				bn = itemOnDisc.Bn
+			}
+			if err = opts.checkOneBlock(scanBar, &prev, &header, bn, itemOnDisc); err != nil {
 				return err
 			}
 		}
@@ -58,13 +78,7 @@ func (opts *WhenOptions) HandleTimestampsCheck() error {
 	return nil
 }
 
-func (opts *WhenOptions) checkOneBlock(scanBar *progress.ScanBar, prev *types.SimpleNamedBlock, bn uint64) error {
-	// The i'th item in the timestamp array on disc
-	itemOnDisc, err := tslib.FromBn(opts.Globals.Chain, bn)
-	if err != nil {
-		return err
-	}
-
+func (opts *WhenOptions) checkOneBlock(scanBar *progress.ScanBar, prev *types.SimpleNamedBlock, header *tslib.TimestampHeader, bn uint64, itemOnDisc *tslib.TimestampRecord) error {
 	// This just simplifies the code below by removing the need to type cast
 	onDisc := types.SimpleNamedBlock{
 		BlockNumber: uint64(itemOnDisc.Bn),
@@ -88,6 +102,10 @@ func (opts *WhenOptions) checkOneBlock(scanBar *progress.ScanBar, prev *types.Si
 		}
 
 		tsSequential := prev.Timestamp < onDisc.Timestamp
+		if header.Resolution > 1 {
+			// Sub-second timestamps are reported in seconds, so neighboring blocks may share one
+			tsSequential = prev.Timestamp <= onDisc.Timestamp
+		}
 		if !tsSequential {
 			msg := fmt.Sprintf("At block %d, timestamp %d does not increase over previous %d%s", bn, onDisc.Timestamp, prev.Timestamp, clear)
 			logger.Error(msg)
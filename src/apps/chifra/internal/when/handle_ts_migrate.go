@@ -0,0 +1,21 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package whenPkg
+
+import (
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
+)
+
+// HandleTimestampsMigrate handles chifra when --timestamps --migrate
+func (opts *WhenOptions) HandleTimestampsMigrate() error {
+	if err := tslib.Migrate(opts.Globals.Chain); err != nil {
+		return err
+	}
+
+	cnt, _ := tslib.NTimestamps(opts.Globals.Chain)
+	logger.Info("The timestamps file was migrated to version", tslib.TsVersion2, "with", cnt, "records")
+	return nil
+}
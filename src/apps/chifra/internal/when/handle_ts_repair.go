@@ -12,7 +12,7 @@ import (
 
 // HandleTimestampsRepair handles chifra when --timestamps --reset <bn> to reset a single block's timestamps (call repeatedly if needed)
 func (opts *WhenOptions) HandleTimestampsRepair() error {
-	cnt, err := tslib.NTimestamps(opts.Globals.Chain)
+	cnt, err := tslib.NextBn(opts.Globals.Chain)
 	if err != nil {
 		return err
 	}
@@ -26,7 +26,7 @@ func (opts *WhenOptions) HandleTimestampsRepair() error {
 	}
 
 	for _, bn := range blockNums {
-		if bn < cnt { // ranges may include blocks after last block
+		if _, err := tslib.FromBn(opts.Globals.Chain, bn); bn < cnt && err == nil { // ranges may include blocks after last block or in gaps
 			if err := tslib.Repair(opts.Globals.Chain, bn); err != nil {
 				return err
 			}
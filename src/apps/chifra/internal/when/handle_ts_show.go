@@ -30,18 +30,19 @@ func (opts *WhenOptions) HandleTimestampsShow() error {
 	ctx := context.Background()
 	prev := base.Timestamp(0)
 	fetchData := func(modelChan chan types.Modeler[types.RawModeler], errorChan chan error) {
-		for bn := uint64(0); bn < cnt; bn++ {
-			if len(bnMap) == 0 || bnMap[bn] {
-				ts, err := tslib.FromBn(opts.Globals.Chain, bn)
-				if err != nil {
-					errorChan <- err
-				}
+		for index := uint64(0); index < cnt; index++ {
+			ts, err := tslib.FromIndex(opts.Globals.Chain, index)
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			if len(bnMap) == 0 || bnMap[ts.Bn] {
 				s := simpleTimestamp{
 					BlockNumber: uint64(ts.Bn),
 					Timestamp:   base.Timestamp(ts.Ts),
 					Diff:        base.Timestamp(ts.Ts) - prev,
 				}
-				if bn == 0 {
+				if index == 0 {
 					s.Diff = 0
 				}
 				modelChan <- &s
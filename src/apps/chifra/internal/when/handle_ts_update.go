@@ -19,7 +19,7 @@ func (opts *WhenOptions) HandleTimestampUpdate() error {
 		return nil
 	}
 
-	cnt, err := tslib.NTimestamps(opts.Globals.Chain)
+	cnt, err := tslib.NextBn(opts.Globals.Chain)
 	if err != nil {
 		return err
 	}
@@ -39,7 +39,7 @@ func (opts *WhenOptions) HandleTimestampUpdate() error {
 	logger.Info("Updating timestamps file from", cnt, "to", meta.Latest, fmt.Sprintf("(%d blocks)", (meta.Latest-cnt)))
 	for bn := cnt; bn < meta.Latest; bn++ {
 		block, _ := rpcClient.GetBlockHeaderByNumber(opts.Globals.Chain, bn)
-		record := tslib.TimestampRecord{Bn: uint32(block.BlockNumber), Ts: uint32(block.Timestamp)}
+		record := tslib.TimestampRecord{Bn: block.BlockNumber, Ts: uint64(block.Timestamp)}
 		timestamps = append(timestamps, record)
 		logger.Progress(true, "Adding block", bn, "to timestamp array")
 		if bn%1000 == 0 {
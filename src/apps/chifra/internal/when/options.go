@@ -30,7 +30,12 @@ type WhenOptions struct {
 	Repair     bool                     `json:"repair,omitempty"`     // With --timestamps only, repairs block(s) in the block range by re-querying from the chain
 	Check      bool                     `json:"check,omitempty"`      // With --timestamps only, checks the validity of the timestamp data
 	Update     bool                     `json:"update,omitempty"`     // With --timestamps only, bring the timestamp database forward to the latest block
+	Migrate    bool                     `json:"migrate,omitempty"`    // With --timestamps only, migrates the timestamp file to the version 2 format
 	Deep       bool                     `json:"deep,omitempty"`       // With --timestamps --check only, verifies timestamps from on chain (slow)
+	Create     string                   `json:"create,omitempty"`     // Create a user-defined named block with this name at the given block
+	Edit       string                   `json:"edit,omitempty"`       // Move the user-defined named block with this name to the given block
+	Remove     string                   `json:"remove,omitempty"`     // Remove the user-defined named block with this name
+	Forks      string                   `json:"forks,omitempty"`      // Add the chain's hard forks to its special blocks given a chain config (genesis) JSON file
 	Globals    globals.GlobalOptions    `json:"globals,omitempty"`    // The global options
 	BadFlag    error                    `json:"badFlag,omitempty"`    // An error flag if needed
 	// EXISTING_CODE
@@ -51,7 +56,12 @@ func (opts *WhenOptions) testLog() {
 	logger.TestLog(opts.Repair, "Repair: ", opts.Repair)
 	logger.TestLog(opts.Check, "Check: ", opts.Check)
 	logger.TestLog(opts.Update, "Update: ", opts.Update)
+	logger.TestLog(opts.Migrate, "Migrate: ", opts.Migrate)
 	logger.TestLog(opts.Deep, "Deep: ", opts.Deep)
+	logger.TestLog(len(opts.Create) > 0, "Create: ", opts.Create)
+	logger.TestLog(len(opts.Edit) > 0, "Edit: ", opts.Edit)
+	logger.TestLog(len(opts.Remove) > 0, "Remove: ", opts.Remove)
+	logger.TestLog(len(opts.Forks) > 0, "Forks: ", opts.Forks)
 	opts.Globals.TestLog()
 }
 
@@ -87,8 +97,18 @@ func whenFinishParseApi(w http.ResponseWriter, r *http.Request) *WhenOptions {
 			opts.Check = true
 		case "update":
 			opts.Update = true
+		case "migrate":
+			opts.Migrate = true
 		case "deep":
 			opts.Deep = true
+		case "create":
+			opts.Create = value[0]
+		case "edit":
+			opts.Edit = value[0]
+		case "remove":
+			opts.Remove = value[0]
+		case "forks":
+			opts.Forks = value[0]
 		default:
 			if !globals.IsGlobalOption(key) {
 				opts.BadFlag = validate.Usage("Invalid key ({0}) in {1} route.", key, "when")
@@ -54,13 +54,19 @@ func (opts *WhenOptions) WhenInternal() (err error, handled bool) {
 	// TODO: This should use StreamMany for all cases
 	handled = true
 
-	if opts.List {
+	if opts.isCrud() {
+		err = opts.HandleCrud()
+
+	} else if opts.List {
 		err = opts.HandleList()
 
 	} else if opts.Timestamps {
 		if opts.Update {
 			err = opts.HandleTimestampUpdate()
 
+		} else if opts.Migrate {
+			err = opts.HandleTimestampsMigrate()
+
 		} else if opts.Count {
 			err = opts.HandleTimestampCount()
 
@@ -7,6 +7,9 @@ package whenPkg
 import (
 	"errors"
 
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/identifiers"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/tslib"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/validate"
 )
@@ -22,6 +25,10 @@ func (opts *WhenOptions) validateWhen() error {
 		return validate.Usage("The {0} option is not available for {1} command", "--raw", "when")
 	}
 
+	if opts.isCrud() {
+		return opts.validateCrud()
+	}
+
 	if opts.Timestamps {
 		if opts.List {
 			return validate.Usage("Please choose only one of {0}.", "--timestamps or --list")
@@ -35,6 +42,10 @@ func (opts *WhenOptions) validateWhen() error {
 			return validate.Usage("The {0} option requires at least one block identifier.", "--repair")
 		}
 
+		if opts.Migrate && len(opts.Blocks) > 0 {
+			return validate.Usage("The {0} option does not accept block identifiers.", "--migrate")
+		}
+
 	} else {
 		if opts.Check {
 			return validate.Usage("The {0} option is only available with the {1} option.", "--check", "--timestamps")
@@ -53,6 +64,10 @@ func (opts *WhenOptions) validateWhen() error {
 			return validate.Usage("The {0} option is only available with the {1} option.", "--update", "--timestamps")
 		}
 
+		if opts.Migrate {
+			return validate.Usage("The {0} option is only available with the {1} option.", "--migrate", "--timestamps")
+		}
+
 		if opts.Truncate != utils.NOPOS {
 			return validate.Usage("The {0} option is only available with the {1} option.", "--truncate", "--timestamps")
 		}
@@ -105,3 +120,55 @@ func (opts *WhenOptions) validateWhen() error {
 
 	return opts.Globals.Validate()
 }
+
+// isCrud returns true if the command changes the chain's named blocks
+func (opts *WhenOptions) isCrud() bool {
+	return len(opts.Create) > 0 || len(opts.Edit) > 0 || len(opts.Remove) > 0 || len(opts.Forks) > 0
+}
+
+func (opts *WhenOptions) validateCrud() error {
+	nOps := 0
+	for _, op := range []string{opts.Create, opts.Edit, opts.Remove, opts.Forks} {
+		if len(op) > 0 {
+			nOps++
+		}
+	}
+	if nOps > 1 || opts.List || opts.Timestamps {
+		return validate.Usage("Please choose only one of {0}.", "--create, --edit, --remove, --forks, --list, or --timestamps")
+	}
+
+	if len(opts.Remove) > 0 || len(opts.Forks) > 0 {
+		if len(opts.Blocks) > 0 {
+			return validate.Usage("The {0} option does not accept block identifiers.", "--remove or --forks")
+		}
+		if len(opts.Forks) > 0 && !file.FileExists(opts.Forks) {
+			return validate.Usage("The chain config file {0} was not found.", opts.Forks)
+		}
+		return opts.Globals.Validate()
+	}
+
+	if len(opts.Create) > 0 {
+		if err := tslib.ValidateSpecialName(opts.Create); err != nil {
+			return validate.Usage(err.Error())
+		}
+	}
+	if len(opts.Blocks) != 1 {
+		return validate.Usage("The {0} option requires exactly one block identifier.", "--create or --edit")
+	}
+
+	err := validate.ValidateIdentifiers(
+		opts.Globals.Chain,
+		opts.Blocks,
+		validate.ValidBlockIdWithRangeAndDate,
+		1,
+		&opts.BlockIds,
+	)
+	if err != nil {
+		return err
+	}
+	if opts.BlockIds[0].EndType != identifiers.NotDefined {
+		return validate.Usage("The {0} option requires a single block, not a range.", "--create or --edit")
+	}
+
+	return opts.Globals.Validate()
+}
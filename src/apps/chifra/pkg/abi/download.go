@@ -1,21 +1,19 @@
 package abi
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
-	"fmt"
 	"io"
-	"net/http"
 	"strings"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/cache"
-	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 )
 
-// TODO: This function should be easy to replace with "ABI providers" (different services like
-// Sourcify or custom ones configured by the user)
+// DownloadAbi asks each of the chain's ABI providers in turn for the address's ABI. The first
+// ABI found is loaded into destination and cached. If every provider reports that it doesn't
+// have the ABI, we cache an empty ABI so we don't keep asking.
 func DownloadAbi(chain string, address base.Address, destination AbiInterfaceMap) error {
 	if address.IsZero() {
 		return errors.New("address is 0x0")
@@ -24,60 +22,47 @@ func DownloadAbi(chain string, address base.Address, destination AbiInterfaceMap
 	// C++ code used do check if the address is contract in 2 places: here and in handle_addresses. We
 	// check only in handle_addresses.
 
-	key := config.GetRootConfig().Keys["etherscan"].ApiKey
-	if key == "" {
-		return errors.New("cannot read Etherscan API key")
-	}
-	url := fmt.Sprintf(
-		"http://api.etherscan.io/api?module=contract&action=getabi&address=%s&apikey=%s",
-		address.Hex(),
-		key,
-	)
-
-	resp, err := http.Get(url)
+	providers, err := GetAbiProviders(chain)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("etherscan API error: %s", resp.Status)
-	}
+	var lastErr error
+	notFound := true
+	for _, provider := range providers {
+		contents, err := provider.GetAbi(chain, address)
+		if err != nil {
+			if !errors.Is(err, ErrAbiNotFound) {
+				notFound = false
+				lastErr = err
+			}
+			logger.Info("ABI provider", provider.Name(), "did not return an ABI for", address.Hex(), "("+err.Error()+")")
+			continue
+		}
 
-	data := map[string]string{}
-	decoder := json.NewDecoder(resp.Body)
-	if err = decoder.Decode(&data); err != nil {
-		return err
+		if err = fromJson(bytes.NewReader(contents), address.Hex()+".json", destination); err != nil {
+			// The provider returned something we can't read, so we try the next one
+			notFound = false
+			lastErr = err
+			logger.Warn("ABI provider", provider.Name(), "returned an invalid ABI for", address.Hex(), err)
+			continue
+		}
+		return cache.InsertAbi(chain, address, bytes.NewReader(contents))
 	}
-	resp.Body.Close()
 
-	if data["message"] == "NOTOK" {
-		// Etherscan sends 200 OK responses even if there's an error. We want to cache the error
-		// response so we don't keep asking Etherscan for the same address. The user may later
-		// remove empty ABIs with chifra abis --clean.
-		logger.Warn("provider responded with:", address.Hex(), data["message"])
-
-		reader := strings.NewReader("[{\"name\": \"AbiNotFound\",\"type\": \"function\"}]")
-		fromJson(reader, address.Hex()+".json", destination)
-		if _, err = reader.Seek(0, io.SeekStart); err != nil {
-			return err
-		}
-		if err = cache.InsertAbi(chain, address, reader); err != nil {
-			return err
-		}
-		return nil
+	if !notFound {
+		// At least one provider failed for a reason other than not having the ABI (for example,
+		// a network error), so we don't cache anything and will try again later.
+		return lastErr
 	}
 
-	reader := strings.NewReader(data["result"])
+	// We want to cache the fact that no provider knows this address so we don't keep asking for
+	// it. The user may later remove empty ABIs with chifra abis --clean.
+	logger.Warn("No ABI provider has an ABI for", address.Hex())
+	reader := strings.NewReader("[{\"name\": \"AbiNotFound\",\"type\": \"function\"}]")
 	fromJson(reader, address.Hex()+".json", destination)
 	if _, err = reader.Seek(0, io.SeekStart); err != nil {
 		return err
 	}
-
-	// Write the body to file
-	if err = cache.InsertAbi(chain, address, reader); err != nil {
-		return err
-	}
-	return nil
+	return cache.InsertAbi(chain, address, reader)
 }
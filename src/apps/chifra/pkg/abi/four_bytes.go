@@ -0,0 +1,398 @@
+package abi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/manifest"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/pinning"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/unchained"
+	goEthAbi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The four-byte database (see src/other/four_bytes) is a cross-product of known function and
+// event names with known parameter lists. It is split into chunks by the first two bytes of the
+// encoding. Each chunk holds a header, a table of (encoding, offset, length) records, and the
+// string table holding the signatures. Chunks live in GetPathToFourBytes. They are either
+// installed there or downloaded on demand from IPFS using a manifest published to the Unchained
+// Index under the name fourBytesManifestName.
+
+// FourBytesSource is the AbiSource of functions and events built from the four-byte database
+const FourBytesSource = "four_bytes"
+
+const fourBytesManifestName = "four_bytes"
+
+type fourBytesHeader struct {
+	Magic          uint32
+	Hash           common.Hash
+	SignatureCount uint32
+}
+
+type fourBytesRecord struct {
+	Encoding common.Hash
+	Offset   uint32
+	Len      uint32
+}
+
+// FourBytesManifest lists the chunks of the four-byte database and where to find them on IPFS
+type FourBytesManifest struct {
+	Version string           `json:"version"`
+	Chunks  []FourBytesChunk `json:"chunks"`
+}
+
+// FourBytesChunk is a single chunk of the four-byte database. Name is the chunk's path relative
+// to the database's folder (for example, a9/a905).
+type FourBytesChunk struct {
+	Name string        `json:"name"`
+	Hash base.IpfsHash `json:"hash"`
+	Size int64         `json:"size"`
+}
+
+// fourBytesFailed is set the first time the database can't be downloaded. After that we warn no
+// more and stop trying, so every encoding not found locally is simply not found.
+var (
+	fourBytesMutex    sync.Mutex
+	fourBytesManifest *FourBytesManifest
+	fourBytesLoaded   bool
+	fourBytesFailed   bool
+)
+
+// GetPathToFourBytes returns the folder holding the four-byte database
+func GetPathToFourBytes() string {
+	return filepath.Join(config.GetPathToRootConfig(), "abis", "four_bytes")
+}
+
+// FourBytesCandidates returns the functions (if encoding is a four-byte selector) or events (if
+// encoding is a 32-byte topic) whose signatures are found in the four-byte database. Because
+// indexing is not part of an event's signature, the first nIndexed parameters of events are
+// marked as indexed and events with fewer parameters are skipped. Signatures that cannot be
+// parsed are skipped as well.
+func FourBytesCandidates(chain, encoding string, nIndexed int) ([]*types.SimpleFunction, error) {
+	signatures, err := GetFourBytesSignatures(chain, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	isEvent := len(encoding) == 66
+	ret := make([]*types.SimpleFunction, 0, len(signatures))
+	for _, signature := range signatures {
+		function, err := functionFromSignature(signature, isEvent, nIndexed)
+		if err != nil || function == nil {
+			continue
+		}
+		ret = append(ret, function)
+	}
+	return ret, nil
+}
+
+// GetFourBytesSignatures returns the signatures in the four-byte database whose encoding starts
+// with the given four-byte selector or matches the given 32-byte topic. The chunk holding the
+// encoding is downloaded if it isn't found locally and a manifest is available. If neither is
+// available, or the download fails, no signatures are returned.
+func GetFourBytesSignatures(chain, encoding string) ([]string, error) {
+	encBytes, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(encoding), "0x"))
+	if err != nil || (len(encBytes) != 4 && len(encBytes) != 32) {
+		return nil, fmt.Errorf("invalid encoding %s", encoding)
+	}
+
+	name := fourBytesChunkName(encBytes)
+	path := filepath.Join(GetPathToFourBytes(), name)
+	if !file.FileExists(path) {
+		if err := downloadFourBytesChunk(chain, name, path); err != nil {
+			fourBytesUnavailable("Could not download the four-byte database:", err)
+			return nil, nil
+		}
+		if !file.FileExists(path) {
+			return nil, nil
+		}
+	}
+
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return readFourBytesChunk(fp, encBytes)
+}
+
+// fourBytesChunkName returns the name of the chunk holding the encoding
+func fourBytesChunkName(encoding []byte) string {
+	name := hex.EncodeToString(encoding[:2])
+	return filepath.Join(name[:2], name)
+}
+
+// readFourBytesChunk returns the signatures in the chunk whose encoding matches (a four-byte
+// encoding matches on its first four bytes)
+func readFourBytesChunk(r io.Reader, encoding []byte) ([]string, error) {
+	header := fourBytesHeader{}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.SignatureCount == 0 {
+		return nil, nil
+	}
+
+	records := make([]fourBytesRecord, header.SignatureCount)
+	if err := binary.Read(r, binary.LittleEndian, records); err != nil {
+		return nil, err
+	}
+	strs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []string{}
+	for _, record := range records {
+		if !bytes.HasPrefix(record.Encoding.Bytes(), encoding) {
+			continue
+		}
+		end := uint64(record.Offset) + uint64(record.Len)
+		if end > uint64(len(strs)) {
+			return nil, errors.New("four-byte chunk is corrupted")
+		}
+		ret = append(ret, string(strs[record.Offset:end]))
+	}
+	return ret, nil
+}
+
+// fourBytesUnavailable warns (only once) that the four-byte database can't be downloaded and
+// turns off further downloads
+func fourBytesUnavailable(v ...any) {
+	fourBytesMutex.Lock()
+	defer fourBytesMutex.Unlock()
+
+	if !fourBytesFailed {
+		fourBytesFailed = true
+		logger.Warn(v...)
+	}
+}
+
+// downloadFourBytesChunk downloads the named chunk to path if the manifest lists it
+func downloadFourBytesChunk(chain, name, path string) error {
+	man := getFourBytesManifest(chain)
+	if man == nil {
+		return nil
+	}
+
+	fourBytesMutex.Lock()
+	failed := fourBytesFailed
+	fourBytesMutex.Unlock()
+	if failed {
+		return nil
+	}
+
+	var hash base.IpfsHash
+	for _, chunk := range man.Chunks {
+		if filepath.Clean(chunk.Name) == name {
+			hash = chunk.Hash
+			break
+		}
+	}
+	if hash == "" {
+		return nil
+	}
+
+	download, err := pinning.FetchFromGateway(context.Background(), config.GetIpfsGateway(chain), hash.String())
+	if err != nil {
+		return err
+	}
+	defer download.Body.Close()
+	contents, err := io.ReadAll(download.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := file.EstablishFolder(filepath.Dir(path)); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a failed download never leaves a partial chunk behind
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, contents, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// getFourBytesManifest returns the four-byte database's manifest, reading it from disc or (the first
+// time it is needed) from the Unchained Index. Returns nil if there is no manifest.
+func getFourBytesManifest(chain string) *FourBytesManifest {
+	fourBytesMutex.Lock()
+	defer fourBytesMutex.Unlock()
+
+	if fourBytesLoaded {
+		return fourBytesManifest
+	}
+	fourBytesLoaded = true
+
+	manifestPath := filepath.Join(GetPathToFourBytes(), "manifest.json")
+	contents, err := os.ReadFile(manifestPath)
+	if err != nil {
+		cid, err := manifest.ReadUnchainIndex("mainnet", fourBytesManifestName, unchained.PreferredPublisher)
+		if err == nil && len(cid) == 0 {
+			err = errors.New("it has not been published")
+		}
+		if err != nil {
+			fourBytesFailed = true
+			logger.Warn("The four-byte database is not available:", err)
+			return nil
+		}
+		download, err := pinning.FetchFromGateway(context.Background(), config.GetIpfsGateway(chain), cid)
+		if err != nil {
+			fourBytesFailed = true
+			logger.Warn("Could not download the four-byte database's manifest:", err)
+			return nil
+		}
+		defer download.Body.Close()
+		if contents, err = io.ReadAll(download.Body); err != nil {
+			fourBytesFailed = true
+			logger.Warn("Could not download the four-byte database's manifest:", err)
+			return nil
+		}
+		if err = file.EstablishFolder(GetPathToFourBytes()); err == nil {
+			_ = os.WriteFile(manifestPath, contents, 0644)
+		}
+	}
+
+	man := &FourBytesManifest{}
+	if err := json.Unmarshal(contents, man); err != nil {
+		logger.Warn("Could not read the four-byte database's manifest:", err)
+		return nil
+	}
+	fourBytesManifest = man
+	return fourBytesManifest
+}
+
+// functionFromSignature builds a function or event from a signature such as
+// transfer(address,uint256). The database does not carry parameter names, so parameters are
+// named as DisplayName would name them.
+func functionFromSignature(signature string, isEvent bool, nIndexed int) (*types.SimpleFunction, error) {
+	open := strings.Index(signature, "(")
+	if open < 1 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid signature %s", signature)
+	}
+	name := signature[:open]
+	params, err := splitParameterTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return nil, err
+	}
+	if isEvent && nIndexed > len(params) {
+		return nil, nil
+	}
+
+	inputs := make([]map[string]any, 0, len(params))
+	for i, param := range params {
+		input, err := parameterToJson(param, fmt.Sprintf("val_%d", i))
+		if err != nil {
+			return nil, err
+		}
+		if isEvent {
+			input["indexed"] = i < nIndexed
+		}
+		inputs = append(inputs, input)
+	}
+
+	item := map[string]any{
+		"type":   "function",
+		"name":   name,
+		"inputs": inputs,
+	}
+	if isEvent {
+		item["type"] = "event"
+	} else {
+		item["outputs"] = []any{}
+	}
+	abiJson, err := json.Marshal([]any{item})
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := goEthAbi.JSON(bytes.NewReader(abiJson))
+	if err != nil {
+		return nil, err
+	}
+
+	if isEvent {
+		for _, event := range loaded.Events {
+			event := event
+			return types.FunctionFromAbiEvent(&event, FourBytesSource), nil
+		}
+	} else {
+		for _, method := range loaded.Methods {
+			method := method
+			return types.FunctionFromAbiMethod(&method, FourBytesSource), nil
+		}
+	}
+	return nil, fmt.Errorf("could not build %s", signature)
+}
+
+// parameterToJson returns the JSON ABI description of a parameter type. Tuples, which appear in
+// signatures as parenthesized lists of types, are described by their components.
+func parameterToJson(paramType, name string) (map[string]any, error) {
+	if !strings.HasPrefix(paramType, "(") {
+		return map[string]any{"name": name, "type": paramType}, nil
+	}
+
+	closeParen := strings.LastIndex(paramType, ")")
+	components, err := splitParameterTypes(paramType[1:closeParen])
+	if err != nil {
+		return nil, err
+	}
+	componentJson := make([]map[string]any, 0, len(components))
+	for i, component := range components {
+		c, err := parameterToJson(component, fmt.Sprintf("val_%d", i))
+		if err != nil {
+			return nil, err
+		}
+		componentJson = append(componentJson, c)
+	}
+	return map[string]any{
+		"name":       name,
+		"type":       "tuple" + paramType[closeParen+1:],
+		"components": componentJson,
+	}, nil
+}
+
+// splitParameterTypes splits a comma separated list of types, leaving tuples intact
+func splitParameterTypes(list string) ([]string, error) {
+	ret := []string{}
+	if len(list) == 0 {
+		return ret, nil
+	}
+
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parameter list %s", list)
+			}
+		case ',':
+			if depth == 0 {
+				ret = append(ret, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parameter list %s", list)
+	}
+	return append(ret, list[start:]), nil
+}
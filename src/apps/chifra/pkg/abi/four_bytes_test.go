@@ -0,0 +1,80 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestReadFourBytesChunk(t *testing.T) {
+	signatures := []string{"transfer(address,uint256)", "approve(address,uint256)", "Transfer(address,address,uint256)"}
+
+	records := make([]fourBytesRecord, 0, len(signatures))
+	offset := uint32(0)
+	for _, signature := range signatures {
+		records = append(records, fourBytesRecord{
+			Encoding: common.BytesToHash(crypto.Keccak256([]byte(signature))),
+			Offset:   offset,
+			Len:      uint32(len(signature)),
+		})
+		offset += uint32(len(signature))
+	}
+	// A colliding signature (the database does not check) must be reported as well
+	records = append(records, fourBytesRecord{Encoding: records[0].Encoding, Offset: 0, Len: 8})
+
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, fourBytesHeader{Magic: 0xdeadbeef, SignatureCount: uint32(len(records))})
+	_ = binary.Write(buf, binary.LittleEndian, records)
+	for _, signature := range signatures {
+		buf.WriteString(signature)
+	}
+	chunk := buf.Bytes()
+
+	got, err := readFourBytesChunk(bytes.NewReader(chunk), crypto.Keccak256([]byte(signatures[0]))[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"transfer(address,uint256)", "transfer"}) {
+		t.Fatal("unexpected signatures", got)
+	}
+
+	got, err = readFourBytesChunk(bytes.NewReader(chunk), crypto.Keccak256([]byte(signatures[2])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"Transfer(address,address,uint256)"}) {
+		t.Fatal("unexpected event signatures", got)
+	}
+}
+
+func TestFunctionFromSignature(t *testing.T) {
+	function, err := functionFromSignature("swap((address,uint256)[],bytes,uint8)", false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if function.Encoding != "0x"+common.Bytes2Hex(crypto.Keccak256([]byte(function.Signature))[:4]) {
+		t.Fatal("unexpected encoding", function.Encoding, function.Signature)
+	}
+	if function.Signature != "swap((address,uint256)[],bytes,uint8)" || len(function.Inputs) != 3 || function.Inputs[0].Name != "val_0" {
+		t.Fatal("unexpected function", function.Signature, function.Inputs)
+	}
+
+	event, err := functionFromSignature("Transfer(address,address,uint256)", true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.FunctionType != "event" || !event.Inputs[0].Indexed || !event.Inputs[1].Indexed || event.Inputs[2].Indexed {
+		t.Fatal("unexpected event", event)
+	}
+
+	if event, _ := functionFromSignature("Transfer(address)", true, 2); event != nil {
+		t.Fatal("events with too few parameters should be skipped")
+	}
+	if _, err := functionFromSignature("broken((address)", false, 0); err == nil {
+		t.Fatal("expected an error for an unbalanced signature")
+	}
+}
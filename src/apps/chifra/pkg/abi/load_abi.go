@@ -1,6 +1,7 @@
 package abi
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/fs"
@@ -13,7 +14,9 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/contract"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 )
 
@@ -54,6 +57,15 @@ func fromJson(reader io.Reader, abiSource string, destination AbiInterfaceMap) (
 		destination[event.Encoding] = event
 	}
 
+	for _, ethError := range loadedAbi.Errors {
+		ethError := ethError
+		customError := types.FunctionFromAbiError(&ethError, abiSource)
+		// Functions win if a custom error's selector collides with a function's
+		if _, ok := destination[customError.Encoding]; !ok {
+			destination[customError.Encoding] = customError
+		}
+	}
+
 	return
 }
 
@@ -173,9 +185,20 @@ func getKnownAbiPaths() (filePaths []string, err error) {
 	return
 }
 
-// LoadAbiFromAddress loads ABI from local file or cache
+// LoadAbiFromAddress loads ABI from local file (a JSON ABI or a Solidity source file) or cache
 func LoadAbiFromAddress(chain string, address base.Address, destination AbiInterfaceMap) (err error) {
 	localFileName := address.Hex() + ".json"
+	if solFileName := address.Hex() + ".sol"; !file.FileExists(localFileName) && file.FileExists(solFileName) {
+		abiJson, err := SolToAbi(solFileName)
+		if err != nil {
+			return err
+		}
+		if err = fromJson(bytes.NewReader(abiJson), getAbiSourceByPath(solFileName), destination); err != nil {
+			return err
+		}
+		return cache.InsertAbi(chain, address, bytes.NewReader(abiJson))
+	}
+
 	localFile, err := os.OpenFile(localFileName, os.O_RDONLY, 0)
 	if os.IsNotExist(err) {
 		// There's no local file, so we try to load one from cache
@@ -186,6 +209,9 @@ func LoadAbiFromAddress(chain string, address base.Address, destination AbiInter
 
 		for _, loadedAbi := range loadedAbis {
 			loadedAbi := loadedAbi
+			if _, ok := destination[loadedAbi.Encoding]; ok && loadedAbi.IsError() {
+				continue
+			}
 			loadedAbi.Normalize()
 			destination[loadedAbi.Encoding] = &loadedAbi
 		}
@@ -209,8 +235,58 @@ func LoadAbiFromAddress(chain string, address base.Address, destination AbiInter
 	return
 }
 
-// LoadAbi tries to load ABI from any source (local file, cache, download from 3rd party)
+// LoadAbi tries to load ABI from any source (local file, cache, download from 3rd party). If the
+// address is currently a proxy, the implementation's ABI is loaded as well.
 func LoadAbi(chain string, address base.Address, destination AbiInterfaceMap) (err error) {
+	return LoadAbiAt(chain, address, utils.NOPOS, destination)
+}
+
+// LoadAbiAt loads the address's ABI as LoadAbi does. If the address was a proxy at the given
+// block (EIP-1967, transparent, UUPS, beacon, or EIP-1167 minimal proxies), the ABI of the
+// implementation at that block is merged in, so calls through the proxy may be articulated.
+func LoadAbiAt(chain string, address base.Address, bn uint64, destination AbiInterfaceMap) error {
+	err := loadAbi(chain, address, destination)
+
+	impl, kind, proxyErr := GetProxyImplementation(chain, address, bn)
+	if proxyErr != nil {
+		logger.Warn("Could not check if", address.Hex(), "is a proxy:", proxyErr)
+		return err
+	}
+	if impl.IsZero() || impl == address {
+		return err
+	}
+
+	reportProxy(address, kind, impl)
+	if implErr := loadAbi(chain, impl, destination); implErr != nil {
+		if err == nil {
+			return implErr
+		}
+		return err
+	}
+
+	// We found the implementation's ABI, which is what matters for articulation
+	return nil
+}
+
+// LoadedAbis remembers which ABIs were loaded into an AbiInterfaceMap so each is loaded only once.
+// A proxy is remembered together with its implementation, so after the proxy is upgraded the new
+// implementation's ABI is loaded for the blocks that follow.
+type LoadedAbis map[string]bool
+
+// Load loads the address's ABI at the block (see LoadAbiAt) unless it's already been loaded
+func (l LoadedAbis) Load(chain string, address base.Address, bn uint64, destination AbiInterfaceMap) error {
+	key := address.Hex()
+	if impl, _, err := GetProxyImplementation(chain, address, bn); err == nil && !impl.IsZero() {
+		key += "_" + impl.Hex()
+	}
+	if l[key] {
+		return nil
+	}
+	l[key] = true
+	return LoadAbiAt(chain, address, bn, destination)
+}
+
+func loadAbi(chain string, address base.Address, destination AbiInterfaceMap) (err error) {
 	if err = PreloadKnownAbis(chain, destination); err != nil {
 		return
 	}
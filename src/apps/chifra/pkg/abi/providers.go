@@ -0,0 +1,227 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AbiProvider is a source of contract ABIs such as Etherscan or Sourcify. GetAbi returns the
+// JSON ABI (an array of functions and events) for the address. If the provider does not know
+// the address, it returns ErrAbiNotFound so the next provider may be tried.
+type AbiProvider interface {
+	Name() string
+	GetAbi(chain string, address base.Address) ([]byte, error)
+}
+
+// ErrAbiNotFound is returned by providers that do not have the ABI for an address
+var ErrAbiNotFound = errors.New("abi not found")
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// GetAbiProviders returns the providers configured for the chain in the order they should be tried
+func GetAbiProviders(chain string) ([]AbiProvider, error) {
+	var providers []AbiProvider
+	for _, p := range config.GetAbiProviders(chain) {
+		switch strings.ToLower(p.Type) {
+		case "etherscan":
+			key := p.Key
+			if key == "" {
+				key = "etherscan"
+			}
+			providers = append(providers, &EtherscanProvider{BaseUrl: p.Url, ApiKey: config.GetApiKey(key)})
+		case "sourcify":
+			providers = append(providers, &SourcifyProvider{BaseUrl: p.Url})
+		case "sourcifylocal":
+			providers = append(providers, &SourcifyLocalProvider{Path: p.Path})
+		case "url":
+			providers = append(providers, &UrlProvider{Template: p.Url})
+		default:
+			return nil, fmt.Errorf("unknown ABI provider type %q for chain %s", p.Type, chain)
+		}
+	}
+	return providers, nil
+}
+
+// EtherscanProvider downloads ABIs from Etherscan or any API compatible with it
+type EtherscanProvider struct {
+	BaseUrl string
+	ApiKey  string
+}
+
+func (p *EtherscanProvider) Name() string {
+	return "etherscan"
+}
+
+func (p *EtherscanProvider) GetAbi(chain string, address base.Address) ([]byte, error) {
+	if p.ApiKey == "" {
+		return nil, errors.New("cannot read Etherscan API key")
+	}
+
+	url := fmt.Sprintf(
+		"%s?module=contract&action=getabi&address=%s&apikey=%s",
+		strings.TrimSuffix(p.BaseUrl, "/"),
+		address.Hex(),
+		p.ApiKey,
+	)
+	body, err := httpGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{}
+	if err = json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	// Etherscan sends 200 OK responses even if there's an error
+	if data["message"] == "NOTOK" {
+		return nil, fmt.Errorf("%w: %s", ErrAbiNotFound, data["result"])
+	}
+	return []byte(data["result"]), nil
+}
+
+// SourcifyProvider downloads the metadata of verified contracts from a Sourcify server
+type SourcifyProvider struct {
+	BaseUrl string
+}
+
+func (p *SourcifyProvider) Name() string {
+	return "sourcify"
+}
+
+func (p *SourcifyProvider) GetAbi(chain string, address base.Address) ([]byte, error) {
+	url := fmt.Sprintf(
+		"%s/files/any/%s/%s",
+		strings.TrimSuffix(p.BaseUrl, "/"),
+		config.GetChainId(chain),
+		checksummed(address),
+	)
+	body, err := httpGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Files []struct {
+			Name    string `json:"name"`
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	for _, f := range response.Files {
+		if f.Name == "metadata.json" {
+			return abiFromJson([]byte(f.Content))
+		}
+	}
+	return nil, ErrAbiNotFound
+}
+
+// SourcifyLocalProvider reads ABIs from a local copy of the Sourcify repository
+// (<Path>/contracts/{full_match,partial_match}/<chainId>/<address>/metadata.json)
+type SourcifyLocalProvider struct {
+	Path string
+}
+
+func (p *SourcifyLocalProvider) Name() string {
+	return "sourcifyLocal"
+}
+
+func (p *SourcifyLocalProvider) GetAbi(chain string, address base.Address) ([]byte, error) {
+	chainId := config.GetChainId(chain)
+	for _, match := range []string{"full_match", "partial_match"} {
+		for _, addr := range []string{checksummed(address), address.Hex()} {
+			fileName := filepath.Join(p.Path, "contracts", match, chainId, addr, "metadata.json")
+			contents, err := os.ReadFile(fileName)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			return abiFromJson(contents)
+		}
+	}
+	return nil, ErrAbiNotFound
+}
+
+// UrlProvider downloads ABIs from a URL built from a template. The strings [{CHAIN}],
+// [{CHAINID}], and [{ADDRESS}] are replaced with the chain's name, its id, and the address.
+// The response may be an ABI, a Sourcify metadata file, or an object with an `abi` field.
+type UrlProvider struct {
+	Template string
+}
+
+func (p *UrlProvider) Name() string {
+	return "url"
+}
+
+func (p *UrlProvider) GetAbi(chain string, address base.Address) ([]byte, error) {
+	url := p.Template
+	url = strings.Replace(url, "[{CHAINID}]", config.GetChainId(chain), -1)
+	url = strings.Replace(url, "[{CHAIN}]", chain, -1)
+	url = strings.Replace(url, "[{ADDRESS}]", address.Hex(), -1)
+	body, err := httpGet(url)
+	if err != nil {
+		return nil, err
+	}
+	return abiFromJson(body)
+}
+
+// httpGet returns the body of the response. A 404 response is reported as ErrAbiNotFound.
+func httpGet(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrAbiNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ABI provider error: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// abiFromJson extracts the ABI from a document that is either an ABI, a Sourcify (i.e. solc)
+// metadata file (where it's found in output.abi), or an object with an abi field
+func abiFromJson(contents []byte) ([]byte, error) {
+	trimmed := strings.TrimSpace(string(contents))
+	if strings.HasPrefix(trimmed, "[") {
+		return []byte(trimmed), nil
+	}
+
+	var doc struct {
+		Abi    json.RawMessage `json:"abi"`
+		Output struct {
+			Abi json.RawMessage `json:"abi"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Output.Abi) > 0 {
+		return doc.Output.Abi, nil
+	} else if len(doc.Abi) > 0 {
+		return doc.Abi, nil
+	}
+	return nil, ErrAbiNotFound
+}
+
+// checksummed returns the EIP-55 form of the address (which is how Sourcify stores them)
+func checksummed(address base.Address) string {
+	return common.BytesToAddress(address.Bytes()).Hex()
+}
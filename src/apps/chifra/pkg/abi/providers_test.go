@@ -0,0 +1,72 @@
+package abi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+)
+
+const testAbi = `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+
+var testAddr = base.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+func TestUrlProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mainnet/"+testAddr.Hex()+".json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"abi":` + testAbi + `}`))
+	}))
+	defer server.Close()
+
+	p := UrlProvider{Template: server.URL + "/[{CHAIN}]/[{ADDRESS}].json"}
+	contents, err := p.GetAbi("mainnet", testAddr)
+	if err != nil || string(contents) != testAbi {
+		t.Fatal("unexpected result", string(contents), err)
+	}
+
+	if _, err = p.GetAbi("gnosis", testAddr); !errors.Is(err, ErrAbiNotFound) {
+		t.Fatal("expected ErrAbiNotFound, got", err)
+	}
+}
+
+func TestSourcifyLocalProvider(t *testing.T) {
+	root := t.TempDir()
+	folder := filepath.Join(root, "contracts", "partial_match", config.GetChainId("mainnet"), checksummed(testAddr))
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := `{"compiler":{"version":"0.8.19"},"output":{"abi":` + testAbi + `}}`
+	if err := os.WriteFile(filepath.Join(folder, "metadata.json"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := SourcifyLocalProvider{Path: root}
+	contents, err := p.GetAbi("mainnet", testAddr)
+	if err != nil || string(contents) != testAbi {
+		t.Fatal("unexpected result", string(contents), err)
+	}
+
+	if _, err = p.GetAbi("mainnet", base.HexToAddress("0x1")); !errors.Is(err, ErrAbiNotFound) {
+		t.Fatal("expected ErrAbiNotFound, got", err)
+	}
+}
+
+func TestEtherscanProviderNotOk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`))
+	}))
+	defer server.Close()
+
+	p := EtherscanProvider{BaseUrl: server.URL, ApiKey: "key"}
+	if _, err := p.GetAbi("mainnet", testAddr); !errors.Is(err, ErrAbiNotFound) {
+		t.Fatal("expected ErrAbiNotFound, got", err)
+	}
+}
@@ -0,0 +1,302 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProxyKind names the proxy pattern used by a contract
+type ProxyKind string
+
+const (
+	ProxyNone        ProxyKind = ""
+	ProxyEip1967     ProxyKind = "eip1967"    // also transparent and UUPS proxies built on EIP-1967
+	ProxyBeacon      ProxyKind = "beacon"     // EIP-1967 beacon proxy
+	ProxyUups        ProxyKind = "uups"       // EIP-1822 proxiable
+	ProxyZeppelinos  ProxyKind = "zeppelinos" // pre-EIP-1967 OpenZeppelin transparent proxy
+	ProxyMinimal1167 ProxyKind = "eip1167"    // minimal (clone) proxy
+)
+
+var (
+	// bytes32(uint256(keccak256('eip1967.proxy.implementation')) - 1)
+	slotEip1967Impl = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// bytes32(uint256(keccak256('eip1967.proxy.beacon')) - 1)
+	slotEip1967Beacon = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	// keccak256('PROXIABLE')
+	slotUups = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7")
+	// keccak256('org.zeppelinos.proxy.implementation')
+	slotZeppelinos = common.HexToHash("0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3")
+	// implementation()
+	implementationSelector = []byte{0x5c, 0x60, 0xda, 0x1b}
+
+	minimalProxyPrefix, _ = hex.DecodeString("363d3d373d3d3d363d73")
+	minimalProxySuffix, _ = hex.DecodeString("5af43d82803e903d91602b57fd5bf3")
+)
+
+// ProxyRange records the implementation of a proxy over a range of blocks (inclusive). If the
+// address was not a proxy during the range, Kind and Implementation are empty.
+type ProxyRange struct {
+	First          uint64    `json:"first"`
+	Last           uint64    `json:"last"`
+	Kind           ProxyKind `json:"kind,omitempty"`
+	Implementation string    `json:"implementation,omitempty"`
+}
+
+// opDelegateCall is the DELEGATECALL opcode. Every proxy pattern forwards calls with it.
+const opDelegateCall = 0xf4
+
+// latestProxyTtl is how long what we learned about a proxy at the latest block is trusted
+const latestProxyTtl = time.Minute
+
+// latestProxy is what we learned about a proxy at the latest block
+type latestProxy struct {
+	impl    base.Address
+	kind    ProxyKind
+	expires time.Time
+}
+
+// proxyMutex protects the in-memory caches. It's never held while talking to the node. Addresses
+// whose code can't be a proxy (EOAs and contracts without DELEGATECALL) are remembered in
+// notProxies so we don't ask the node about them again at every block.
+var (
+	proxyMutex      sync.Mutex
+	proxyRanges     = map[string][]ProxyRange{}
+	latestProxies   = map[string]latestProxy{}
+	notProxies      = map[string]bool{}
+	reportedProxies = map[string]bool{}
+)
+
+// GetProxyImplementation returns the implementation behind the address at the given block (or, if
+// bn is utils.NOPOS, the latest block) if the address is a proxy. If it's not, the returned
+// address is zero. Results for historical blocks are cached on disc, results for the latest block
+// are kept in memory for a short while.
+func GetProxyImplementation(chain string, address base.Address, bn uint64) (base.Address, ProxyKind, error) {
+	key := chain + "_" + address.Hex()
+	if impl, kind, ok := findCachedProxy(chain, key, address, bn); ok {
+		return impl, kind, nil
+	}
+
+	impl, kind, canBeProxy, err := detectProxy(chain, address, bn)
+	if err != nil {
+		return impl, kind, err
+	}
+	if !canBeProxy {
+		proxyMutex.Lock()
+		notProxies[key] = true
+		proxyMutex.Unlock()
+		return impl, kind, nil
+	}
+	return impl, kind, cacheProxy(chain, key, address, bn, impl, kind)
+}
+
+// findCachedProxy returns the cached implementation of the proxy at the block if there is one
+func findCachedProxy(chain, key string, address base.Address, bn uint64) (base.Address, ProxyKind, bool) {
+	proxyMutex.Lock()
+	defer proxyMutex.Unlock()
+
+	if notProxies[key] {
+		return base.Address{}, ProxyNone, true
+	}
+
+	if bn == utils.NOPOS {
+		latest, ok := latestProxies[key]
+		if !ok || time.Now().After(latest.expires) {
+			return base.Address{}, ProxyNone, false
+		}
+		return latest.impl, latest.kind, true
+	}
+
+	ranges, ok := proxyRanges[key]
+	if !ok {
+		ranges = readProxyRanges(chain, address)
+		proxyRanges[key] = ranges
+	}
+	if r := findProxyRange(ranges, bn); r != nil {
+		return base.HexToAddress(r.Implementation), r.Kind, true
+	}
+	return base.Address{}, ProxyNone, false
+}
+
+// cacheProxy remembers what detectProxy found at the block. The range file is only rewritten if
+// the ranges changed.
+func cacheProxy(chain, key string, address base.Address, bn uint64, impl base.Address, kind ProxyKind) error {
+	proxyMutex.Lock()
+	defer proxyMutex.Unlock()
+
+	if bn == utils.NOPOS {
+		latestProxies[key] = latestProxy{impl: impl, kind: kind, expires: time.Now().Add(latestProxyTtl)}
+		return nil
+	}
+
+	r := ProxyRange{First: bn, Last: bn, Kind: kind}
+	if !impl.IsZero() {
+		r.Implementation = impl.Hex()
+	}
+	before := append([]ProxyRange{}, proxyRanges[key]...)
+	ranges := insertProxyRange(proxyRanges[key], r)
+	proxyRanges[key] = ranges
+	if reflect.DeepEqual(before, ranges) {
+		return nil
+	}
+	return writeProxyRanges(chain, address, ranges)
+}
+
+// detectProxy asks the node which (if any) of the proxy patterns the address follows. canBeProxy
+// is false if the address's code can't be a proxy at all, in which case we assume it never is one.
+// (This is wrong only for addresses whose code is deployed or replaced after we first look at them.)
+func detectProxy(chain string, address base.Address, bn uint64) (impl base.Address, kind ProxyKind, canBeProxy bool, err error) {
+	addr := address.Hex()
+	code, err := rpcClient.GetCodeAt(chain, addr, bn)
+	if err != nil {
+		return base.Address{}, ProxyNone, true, err
+	}
+
+	if impl, ok := minimalProxyTarget(code); ok {
+		return impl, ProxyMinimal1167, true, nil
+	}
+
+	if !bytes.Contains(code, []byte{opDelegateCall}) {
+		// Can't be a proxy, so there's no need to look at its storage
+		return base.Address{}, ProxyNone, false, nil
+	}
+
+	slots := []struct {
+		slot common.Hash
+		kind ProxyKind
+	}{
+		{slotEip1967Impl, ProxyEip1967},
+		{slotEip1967Beacon, ProxyBeacon},
+		{slotUups, ProxyUups},
+		{slotZeppelinos, ProxyZeppelinos},
+	}
+	for _, s := range slots {
+		value, err := rpcClient.GetStorageAt(chain, addr, s.slot, bn)
+		if err != nil {
+			return base.Address{}, ProxyNone, true, err
+		}
+		impl := base.BytesToAddress(common.BytesToHash(value).Bytes()[12:])
+		if impl.IsZero() {
+			continue
+		}
+
+		if s.kind == ProxyBeacon {
+			// The slot holds the beacon, which knows the implementation
+			output, err := rpcClient.CallContractAt(chain, impl.Hex(), implementationSelector, bn)
+			if err != nil {
+				return base.Address{}, ProxyNone, true, err
+			}
+			if len(output) < 32 {
+				continue
+			}
+			impl = base.BytesToAddress(output[12:32])
+			if impl.IsZero() {
+				continue
+			}
+		}
+		return impl, s.kind, true, nil
+	}
+
+	return base.Address{}, ProxyNone, true, nil
+}
+
+// reportProxy logs (once per implementation) that the address is a proxy
+func reportProxy(address base.Address, kind ProxyKind, impl base.Address) {
+	key := address.Hex() + "_" + impl.Hex()
+	proxyMutex.Lock()
+	reported := reportedProxies[key]
+	reportedProxies[key] = true
+	proxyMutex.Unlock()
+
+	if !reported {
+		logger.Debug("Address", address.Hex(), "is a", kind, "proxy for", impl.Hex())
+	}
+}
+
+// minimalProxyTarget returns the target of an EIP-1167 minimal proxy if the code is one
+func minimalProxyTarget(code []byte) (base.Address, bool) {
+	prefixLen := len(minimalProxyPrefix)
+	if len(code) != prefixLen+20+len(minimalProxySuffix) ||
+		!bytes.HasPrefix(code, minimalProxyPrefix) ||
+		!bytes.HasSuffix(code, minimalProxySuffix) {
+		return base.Address{}, false
+	}
+	return base.BytesToAddress(code[prefixLen : prefixLen+20]), true
+}
+
+// findProxyRange returns the range containing the block or nil if there isn't one
+func findProxyRange(ranges []ProxyRange, bn uint64) *ProxyRange {
+	i := sort.Search(len(ranges), func(i int) bool {
+		return ranges[i].Last >= bn
+	})
+	if i < len(ranges) && ranges[i].First <= bn {
+		return &ranges[i]
+	}
+	return nil
+}
+
+// insertProxyRange adds the range to the sorted list of ranges. A range is merged with its
+// neighbors if they have the same implementation, so the ranges grow to cover the blocks between
+// observations. (This assumes a proxy never returns to an earlier implementation between two
+// observations of it.)
+func insertProxyRange(ranges []ProxyRange, r ProxyRange) []ProxyRange {
+	i := sort.Search(len(ranges), func(i int) bool {
+		return ranges[i].First > r.First
+	})
+	ranges = append(ranges, ProxyRange{})
+	copy(ranges[i+1:], ranges[i:])
+	ranges[i] = r
+
+	merged := ranges[:0]
+	for _, cur := range ranges {
+		n := len(merged)
+		if n > 0 && merged[n-1].Implementation == cur.Implementation && merged[n-1].Kind == cur.Kind {
+			merged[n-1].Last = utils.Max(merged[n-1].Last, cur.Last)
+			continue
+		}
+		merged = append(merged, cur)
+	}
+	return merged
+}
+
+func getProxyCachePath(chain string, address base.Address) string {
+	return filepath.Join(config.GetPathToCache(chain), "proxies", address.Hex()+".json")
+}
+
+func readProxyRanges(chain string, address base.Address) []ProxyRange {
+	ranges := []ProxyRange{}
+	contents, err := os.ReadFile(getProxyCachePath(chain, address))
+	if err != nil {
+		return ranges
+	}
+	if err = json.Unmarshal(contents, &ranges); err != nil {
+		return []ProxyRange{}
+	}
+	return ranges
+}
+
+func writeProxyRanges(chain string, address base.Address, ranges []ProxyRange) error {
+	path := getProxyCachePath(chain, address)
+	if err := file.EstablishFolders(filepath.Dir(path), nil); err != nil {
+		return err
+	}
+	contents, err := json.MarshalIndent(ranges, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
@@ -0,0 +1,42 @@
+package abi
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestMinimalProxyTarget(t *testing.T) {
+	code, _ := hex.DecodeString("363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3")
+	target, ok := minimalProxyTarget(code)
+	if !ok || target.Hex() != "0xbebebebebebebebebebebebebebebebebebebebe" {
+		t.Fatal("expected a minimal proxy, got", ok, target.Hex())
+	}
+
+	if _, ok := minimalProxyTarget(code[1:]); ok {
+		t.Fatal("expected truncated code not to be a minimal proxy")
+	}
+}
+
+func TestProxyRanges(t *testing.T) {
+	implA := "0x000000000000000000000000000000000000000a"
+	implB := "0x000000000000000000000000000000000000000b"
+
+	ranges := []ProxyRange{}
+	ranges = insertProxyRange(ranges, ProxyRange{First: 100, Last: 100, Kind: ProxyEip1967, Implementation: implA})
+	ranges = insertProxyRange(ranges, ProxyRange{First: 300, Last: 300, Kind: ProxyEip1967, Implementation: implB})
+	ranges = insertProxyRange(ranges, ProxyRange{First: 200, Last: 200, Kind: ProxyEip1967, Implementation: implA})
+
+	if len(ranges) != 2 || ranges[0].First != 100 || ranges[0].Last != 200 || ranges[1].First != 300 {
+		t.Fatal("unexpected ranges", ranges)
+	}
+
+	if r := findProxyRange(ranges, 150); r == nil || r.Implementation != implA {
+		t.Fatal("expected block 150 to be implemented by A", r)
+	}
+	if r := findProxyRange(ranges, 250); r != nil {
+		t.Fatal("expected block 250 to be unknown", r)
+	}
+	if r := findProxyRange(ranges, 300); r == nil || r.Implementation != implB {
+		t.Fatal("expected block 300 to be implemented by B", r)
+	}
+}
@@ -0,0 +1,1072 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LoadAbiFromSolFile loads the ABI of the interfaces, contracts, and libraries declared in a
+// Solidity source file. No compiler is needed (see SolToAbi).
+func LoadAbiFromSolFile(filePath string, destination AbiInterfaceMap) (err error) {
+	abiJson, err := SolToAbi(filePath)
+	if err != nil {
+		return
+	}
+	return fromJson(bytes.NewReader(abiJson), getAbiSourceByPath(filePath), destination)
+}
+
+// LoadAbiFromFile loads a JSON ABI file or, if the file ends in .sol, a Solidity source file
+func LoadAbiFromFile(filePath string, destination AbiInterfaceMap) (err error) {
+	if strings.HasSuffix(filePath, ".sol") {
+		return LoadAbiFromSolFile(filePath, destination)
+	}
+	return LoadAbiFromJsonFile(filePath, destination)
+}
+
+// SolToAbi parses a Solidity source file and returns the JSON ABI of the interfaces, contracts,
+// and libraries it declares. Functions (including the getters of public state variables), events,
+// custom errors, constructors, fallback and receive functions are reported. Structs become tuples,
+// enums become uint8, contracts and interfaces become addresses, and user defined value types
+// become their underlying type. Types declared in imported files, and the declarations inherited
+// from contracts declared in them, are found if the import's path is relative to the file.
+// Function bodies are skipped, so the source need not compile.
+func SolToAbi(filePath string) ([]byte, error) {
+	unit := newSolUnit()
+	if err := unit.parseFile(filePath, false); err != nil {
+		return nil, err
+	}
+
+	items, err := unit.abiItems()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(items, "", "  ")
+}
+
+// solAbiArg is a parameter in the JSON ABI
+type solAbiArg struct {
+	Name         string      `json:"name"`
+	Type         string      `json:"type"`
+	InternalType string      `json:"internalType,omitempty"`
+	Indexed      bool        `json:"indexed,omitempty"`
+	Components   []solAbiArg `json:"components,omitempty"`
+}
+
+// solAbiItem is a function, event, or error in the JSON ABI
+type solAbiItem struct {
+	Type            string      `json:"type"`
+	Name            string      `json:"name,omitempty"`
+	Inputs          []solAbiArg `json:"inputs"`
+	Outputs         []solAbiArg `json:"outputs,omitempty"`
+	StateMutability string      `json:"stateMutability,omitempty"`
+	Anonymous       bool        `json:"anonymous,omitempty"`
+}
+
+// solType is a type as written in the source
+type solType struct {
+	name    string   // an elementary type or a (possibly qualified) user defined type
+	payable bool     // address payable
+	key     *solType // for mappings, the key type...
+	value   *solType // ...and the value type
+	dims    []string // array dimensions in source order (e.g., "[]" or "[3]")
+}
+
+type solParam struct {
+	typ     *solType
+	name    string
+	indexed bool
+}
+
+// solDecl is a declaration that appears in the ABI
+type solDecl struct {
+	kind       string // function, event, error, constructor, fallback, receive, or getter
+	scope      string // the declaring contract
+	name       string
+	inputs     []solParam
+	outputs    []solParam
+	mutability string
+	anonymous  bool
+	getter     *solType // for getters, the type of the state variable
+	line       int
+	file       string
+	imported   bool // declared in an imported file (and reported only if inherited)
+}
+
+type solStruct struct {
+	scope   string
+	members []solParam
+}
+
+type solUserType struct {
+	scope      string
+	underlying *solType
+}
+
+// solUnit collects the declarations of a file and the types declared in it and its imports
+type solUnit struct {
+	decls     []solDecl
+	structs   map[string]*solStruct   // keyed by name or Contract.Name
+	enums     map[string]bool         // keyed by name or Contract.Name
+	userTypes map[string]*solUserType // keyed by name or Contract.Name
+	constants map[string]string       // keyed by name or Contract.Name
+	contracts map[string][]string     // contract, interface, or library name to its bases
+	main      []string                // the contracts, interfaces, and libraries of the file itself
+	aliases   map[string]string       // import aliases and renames to what they stand for
+	parsed    map[string]bool
+}
+
+func newSolUnit() *solUnit {
+	return &solUnit{
+		structs:   make(map[string]*solStruct),
+		enums:     make(map[string]bool),
+		userTypes: make(map[string]*solUserType),
+		constants: make(map[string]string),
+		contracts: make(map[string][]string),
+		aliases:   make(map[string]string),
+		parsed:    make(map[string]bool),
+	}
+}
+
+func (u *solUnit) parseFile(filePath string, imported bool) error {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		filePath = abs
+	}
+	if u.parsed[filePath] {
+		return nil
+	}
+	u.parsed[filePath] = true
+
+	source, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	tokens, err := tokenizeSol(string(source))
+	if err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	p := &solParser{unit: u, tokens: tokens, file: filePath, imported: imported}
+	return p.parseSourceUnit()
+}
+
+//-------------------------------------------------------------------------
+// Tokenizer
+
+type solToken struct {
+	text   string
+	line   int
+	quoted bool // a string literal (text holds its contents)
+}
+
+func tokenizeSol(source string) ([]solToken, error) {
+	tokens := []solToken{}
+	line := 1
+	isIdent := func(c byte, first bool) bool {
+		return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+	}
+
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(source[i:], "//"):
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(source[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(source) && source[j] != c {
+				if source[j] == '\\' {
+					j++
+				}
+				if j < len(source) && source[j] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+				j++
+			}
+			if j >= len(source) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, solToken{text: source[i+1 : j], line: line, quoted: true})
+			i = j + 1
+		case isIdent(c, true) || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(source) && (isIdent(source[j], false) || (c >= '0' && c <= '9' && source[j] == '.')) {
+				j++
+			}
+			tokens = append(tokens, solToken{text: source[i:j], line: line})
+			i = j
+		case strings.HasPrefix(source[i:], "=>"):
+			tokens = append(tokens, solToken{text: "=>", line: line})
+			i += 2
+		default:
+			tokens = append(tokens, solToken{text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+//-------------------------------------------------------------------------
+// Parser
+
+type solParser struct {
+	unit     *solUnit
+	tokens   []solToken
+	pos      int
+	file     string
+	imported bool
+}
+
+func (p *solParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *solParser) peek() solToken {
+	if p.done() {
+		return solToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *solParser) next() solToken {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *solParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.done() && len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	} else if !p.done() {
+		line = p.tokens[p.pos].line
+	}
+	return fmt.Errorf("%s:%d: %s", p.file, line, fmt.Sprintf(format, args...))
+}
+
+func (p *solParser) expect(text string) error {
+	if p.done() {
+		return p.errorf("expected %s, found end of file", text)
+	}
+	if tok := p.next(); tok.text != text || tok.quoted {
+		p.pos--
+		return p.errorf("expected %s, found %s", text, tok.text)
+	}
+	return nil
+}
+
+func (p *solParser) ident() (string, error) {
+	tok := p.peek()
+	if p.done() || tok.quoted || !solIdentRe.MatchString(tok.text) {
+		return "", p.errorf("expected an identifier, found %s", tok.text)
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+// skipBalanced skips the bracketed group that starts at the current token
+func (p *solParser) skipBalanced() error {
+	depth := 0
+	for !p.done() {
+		tok := p.next()
+		if tok.quoted {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+	return p.errorf("unbalanced brackets")
+}
+
+// statement returns the tokens up to (and skips) the next semicolon outside of brackets
+func (p *solParser) statement() ([]solToken, error) {
+	start := p.pos
+	depth := 0
+	for !p.done() {
+		tok := p.next()
+		if tok.quoted {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case ";":
+			if depth == 0 {
+				return p.tokens[start : p.pos-1], nil
+			}
+		}
+	}
+	return nil, p.errorf("expected ;, found end of file")
+}
+
+func (p *solParser) parseSourceUnit() error {
+	for !p.done() {
+		var err error
+		switch tok := p.peek(); tok.text {
+		case "pragma", "using":
+			_, err = p.statement()
+		case "import":
+			err = p.parseImport()
+		case "abstract":
+			p.next()
+			if err = p.expect("contract"); err == nil {
+				p.pos--
+				err = p.parseContract()
+			}
+		case "contract", "interface", "library":
+			err = p.parseContract()
+		default:
+			err = p.parseMember("", false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseImport records aliases and parses the imported file (if it is found) for its types and
+// for the declarations of its contracts
+func (p *solParser) parseImport() error {
+	tokens, err := p.statement()
+	if err != nil {
+		return err
+	}
+
+	importPath := ""
+	for i, tok := range tokens {
+		switch {
+		case tok.quoted:
+			importPath = tok.text
+		case tok.text == "as" && i > 0 && i+1 < len(tokens):
+			if prev := tokens[i-1]; prev.quoted || prev.text == "*" {
+				// import "x.sol" as X; or import * as X from "x.sol";
+				p.unit.aliases[tokens[i+1].text] = ""
+			} else {
+				// import {A as B} from "x.sol";
+				p.unit.aliases[tokens[i+1].text] = prev.text
+			}
+		}
+	}
+
+	if importPath == "" {
+		return p.errorf("import without a path")
+	}
+	// Remapped imports (such as @openzeppelin/...) are not resolved. Types from them are reported
+	// as unknown if they are used.
+	fullPath := filepath.Join(filepath.Dir(p.file), importPath)
+	if _, err := os.Stat(fullPath); err != nil {
+		return nil
+	}
+	return p.unit.parseFile(fullPath, true)
+}
+
+func (p *solParser) parseContract() error {
+	kind := p.next().text
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+
+	bases := []string{}
+	if p.peek().text == "is" {
+		p.next()
+		for {
+			base, err := p.qualifiedName()
+			if err != nil {
+				return err
+			}
+			bases = append(bases, base)
+			if p.peek().text == "(" {
+				if err = p.skipBalanced(); err != nil {
+					return err
+				}
+			}
+			if p.peek().text != "," {
+				break
+			}
+			p.next()
+		}
+	}
+	p.unit.contracts[name] = bases
+	if !p.imported {
+		p.unit.main = append(p.unit.main, name)
+	}
+
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	for p.peek().text != "}" {
+		if p.done() {
+			return p.errorf("expected }, found end of file")
+		}
+		if err = p.parseMember(name, kind == "interface"); err != nil {
+			return err
+		}
+	}
+	p.next()
+	return nil
+}
+
+// parseMember parses a declaration in a contract (or at file level if scope is empty)
+func (p *solParser) parseMember(scope string, inInterface bool) error {
+	tok := p.peek()
+	switch tok.text {
+	case "function", "constructor", "fallback", "receive", "modifier":
+		return p.parseFunction(scope, inInterface)
+
+	case "event", "error":
+		p.next()
+		decl := solDecl{kind: tok.text, scope: scope, line: tok.line, file: p.file}
+		var err error
+		if decl.name, err = p.ident(); err != nil {
+			return err
+		}
+		if decl.inputs, err = p.parseParams(); err != nil {
+			return err
+		}
+		if p.peek().text == "anonymous" {
+			p.next()
+			decl.anonymous = true
+		}
+		if err = p.expect(";"); err != nil {
+			return err
+		}
+		p.addDecl(decl)
+		return nil
+
+	case "struct":
+		p.next()
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err = p.expect("{"); err != nil {
+			return err
+		}
+		str := &solStruct{scope: scope}
+		for p.peek().text != "}" {
+			typ, err := p.parseType()
+			if err != nil {
+				return err
+			}
+			member, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err = p.expect(";"); err != nil {
+				return err
+			}
+			str.members = append(str.members, solParam{typ: typ, name: member})
+		}
+		p.next()
+		p.unit.structs[scopedName(scope, name)] = str
+		return nil
+
+	case "enum":
+		p.next()
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		p.unit.enums[scopedName(scope, name)] = true
+		return p.skipBalanced()
+
+	case "type":
+		p.next()
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err = p.expect("is"); err != nil {
+			return err
+		}
+		underlying, err := p.parseType()
+		if err != nil {
+			return err
+		}
+		p.unit.userTypes[scopedName(scope, name)] = &solUserType{scope: scope, underlying: underlying}
+		return p.expect(";")
+
+	case "using":
+		_, err := p.statement()
+		return err
+	}
+
+	return p.parseStateVariable(scope, tok)
+}
+
+// parseFunction parses functions, constructors, fallback and receive functions, and modifiers,
+// skipping their bodies
+func (p *solParser) parseFunction(scope string, inInterface bool) error {
+	start := p.next()
+	decl := solDecl{kind: start.text, scope: scope, mutability: "nonpayable", line: start.line, file: p.file}
+	if decl.kind == "function" {
+		if p.peek().text == "(" {
+			// Before Solidity 0.6, the fallback function was unnamed
+			decl.kind = "fallback"
+		} else {
+			var err error
+			if decl.name, err = p.ident(); err != nil {
+				return err
+			}
+		}
+	} else if decl.kind == "modifier" {
+		if _, err := p.ident(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if p.peek().text == "(" {
+		if decl.inputs, err = p.parseParams(); err != nil {
+			return err
+		}
+	}
+
+	visibility := ""
+	for {
+		if p.done() {
+			return p.errorf("expected a function body, found end of file")
+		}
+		tok := p.peek()
+		switch tok.text {
+		case "{":
+			err = p.skipBalanced()
+		case ";":
+			p.next()
+		case "returns":
+			p.next()
+			decl.outputs, err = p.parseParams()
+		case "external", "public", "internal", "private":
+			p.next()
+			visibility = tok.text
+		case "view", "pure", "payable":
+			p.next()
+			decl.mutability = tok.text
+		case "constant":
+			p.next()
+			decl.mutability = "view"
+		default:
+			// virtual, override, or a modifier, any of which may have arguments
+			p.next()
+			if p.peek().text == "(" {
+				err = p.skipBalanced()
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if tok.text == "{" || tok.text == ";" {
+			break
+		}
+	}
+
+	switch {
+	case decl.kind == "modifier" || scope == "":
+		// Modifiers and free functions are not part of any ABI
+	case decl.kind == "function" && !inInterface && (visibility == "internal" || visibility == "private"):
+	case decl.kind == "receive":
+		decl.mutability = "payable"
+		p.addDecl(decl)
+	default:
+		p.addDecl(decl)
+	}
+	return nil
+}
+
+// parseStateVariable parses a state variable (or a file level constant). Public state variables
+// have getters. Constants whose values are numbers are recorded for use as array sizes.
+func (p *solParser) parseStateVariable(scope string, start solToken) error {
+	tokens, err := p.statement()
+	if err != nil {
+		return err
+	}
+
+	sub := &solParser{unit: p.unit, tokens: tokens, file: p.file}
+	typ, err := sub.parseType()
+	if err != nil {
+		return p.errorf("unexpected %s", start.text)
+	}
+
+	public, constant := false, false
+	for !sub.done() {
+		tok := sub.next()
+		switch tok.text {
+		case "public":
+			public = true
+		case "constant":
+			constant = true
+		case "override":
+			if sub.peek().text == "(" {
+				_ = sub.skipBalanced()
+			}
+		case "private", "internal", "immutable", "transient":
+		default:
+			name := tok.text
+			if constant && sub.peek().text == "=" && len(sub.tokens)-sub.pos == 2 {
+				p.unit.constants[scopedName(scope, name)] = sub.tokens[sub.pos+1].text
+			}
+			if public && scope != "" {
+				p.addDecl(solDecl{kind: "getter", scope: scope, name: name, getter: typ, mutability: "view", line: start.line, file: p.file})
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseParams parses a parenthesized list of parameters
+func (p *solParser) parseParams() ([]solParam, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	params := []solParam{}
+	if p.peek().text == ")" {
+		p.next()
+		return params, nil
+	}
+
+	for {
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		param := solParam{typ: typ}
+		for {
+			tok := p.peek()
+			if tok.text == "," || tok.text == ")" {
+				break
+			}
+			p.next()
+			switch tok.text {
+			case "memory", "calldata", "storage":
+			case "indexed":
+				param.indexed = true
+			default:
+				param.name = tok.text
+			}
+		}
+		params = append(params, param)
+
+		if tok := p.next(); tok.text == ")" {
+			return params, nil
+		} else if tok.text != "," {
+			p.pos--
+			return nil, p.errorf("expected , or ), found %s", tok.text)
+		}
+	}
+}
+
+// parseType parses an elementary type, a (possibly qualified) user defined type, a mapping, or a
+// function type, followed by any array dimensions
+func (p *solParser) parseType() (*solType, error) {
+	typ := &solType{}
+	switch p.peek().text {
+	case "mapping":
+		p.next()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var err error
+		if typ.key, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if p.peek().text != "=>" {
+			p.next() // a named key (Solidity 0.8.18)
+		}
+		if err = p.expect("=>"); err != nil {
+			return nil, err
+		}
+		if typ.value, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if p.peek().text != ")" {
+			p.next() // a named value
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		typ.name = "mapping"
+		return typ, nil
+
+	case "function":
+		p.next()
+		if err := p.skipBalanced(); err != nil {
+			return nil, err
+		}
+		for {
+			switch p.peek().text {
+			case "external", "internal", "view", "pure", "payable":
+				p.next()
+				continue
+			case "returns":
+				p.next()
+				if err := p.skipBalanced(); err != nil {
+					return nil, err
+				}
+			}
+			break
+		}
+		typ.name = "function"
+
+	default:
+		var err error
+		if typ.name, err = p.qualifiedName(); err != nil {
+			return nil, err
+		}
+		if typ.name == "address" && p.peek().text == "payable" {
+			p.next()
+			typ.payable = true
+		}
+	}
+
+	for p.peek().text == "[" {
+		p.next()
+		dim := ""
+		for p.peek().text != "]" {
+			if p.done() {
+				return nil, p.errorf("expected ], found end of file")
+			}
+			dim += p.next().text
+		}
+		p.next()
+		typ.dims = append(typ.dims, dim)
+	}
+	return typ, nil
+}
+
+func (p *solParser) qualifiedName() (string, error) {
+	name, err := p.ident()
+	if err != nil {
+		return "", err
+	}
+	for p.peek().text == "." {
+		p.next()
+		part, err := p.ident()
+		if err != nil {
+			return "", err
+		}
+		name += "." + part
+	}
+	return name, nil
+}
+
+func (p *solParser) addDecl(decl solDecl) {
+	decl.imported = p.imported
+	p.unit.decls = append(p.unit.decls, decl)
+}
+
+func scopedName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+//-------------------------------------------------------------------------
+// Conversion to the JSON ABI
+
+var (
+	solIdentRe      = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	solElementaryRe = regexp.MustCompile(`^(address|bool|string|byte|bytes([0-9]+)?|u?int([0-9]+)?|u?fixed([0-9]+x[0-9]+)?)$`)
+	solNumberRe     = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// abiItems converts the declarations of the file, followed by those its contracts inherit from
+// imported files, to JSON ABI items, dropping duplicates (such as a function declared in both an
+// interface and a contract that implements it). Constructors are not inherited.
+func (u *solUnit) abiItems() ([]solAbiItem, error) {
+	inherited := u.inherited()
+	decls := make([]solDecl, 0, len(u.decls))
+	for _, decl := range u.decls {
+		if !decl.imported {
+			decls = append(decls, decl)
+		}
+	}
+	for _, decl := range u.decls {
+		if decl.imported && inherited[decl.scope] && decl.kind != "constructor" {
+			decls = append(decls, decl)
+		}
+	}
+
+	items := make([]solAbiItem, 0, len(decls))
+	seen := make(map[string]bool)
+	for _, decl := range decls {
+		item, err := u.abiItem(&decl)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", decl.file, decl.line, err)
+		}
+
+		types := make([]string, 0, len(item.Inputs))
+		for _, input := range item.Inputs {
+			types = append(types, input.Type)
+		}
+		key := item.Type + " " + item.Name + "(" + strings.Join(types, ",") + ")"
+		if !seen[key] {
+			seen[key] = true
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// inherited returns the contracts the file's own contracts inherit from, directly or not
+func (u *solUnit) inherited() map[string]bool {
+	ret := make(map[string]bool)
+	queue := append([]string{}, u.main...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, base := range u.contracts[name] {
+			if key, kind := u.lookup("", base); kind == "contract" && !ret[key] {
+				ret[key] = true
+				queue = append(queue, key)
+			}
+		}
+	}
+	return ret
+}
+
+func (u *solUnit) abiItem(decl *solDecl) (item solAbiItem, err error) {
+	item = solAbiItem{Type: decl.kind, Name: decl.name, StateMutability: decl.mutability, Anonymous: decl.anonymous}
+	switch decl.kind {
+	case "getter":
+		item.Type = "function"
+		item.Inputs, item.Outputs, err = u.getterArgs(decl.scope, decl.getter)
+		return
+	case "function":
+		if item.Outputs, err = u.abiArgs(decl.scope, decl.outputs); err != nil {
+			return
+		}
+		if item.Outputs == nil {
+			item.Outputs = []solAbiArg{}
+		}
+	}
+	item.Inputs, err = u.abiArgs(decl.scope, decl.inputs)
+	return
+}
+
+func (u *solUnit) abiArgs(scope string, params []solParam) ([]solAbiArg, error) {
+	args := make([]solAbiArg, 0, len(params))
+	for _, param := range params {
+		arg, err := u.abiArg(scope, param.name, param.typ, 0)
+		if err != nil {
+			return nil, err
+		}
+		arg.Indexed = param.indexed
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// getterArgs returns the inputs and outputs of a public state variable's getter. Mapping keys and
+// array indices are inputs. Struct values are returned member by member, less any arrays and
+// mappings.
+func (u *solUnit) getterArgs(scope string, typ *solType) (inputs, outputs []solAbiArg, err error) {
+	inputs = []solAbiArg{}
+	for {
+		if typ.key != nil {
+			key, err := u.abiArg(scope, "", typ.key, 0)
+			if err != nil {
+				return nil, nil, err
+			}
+			inputs = append(inputs, key)
+			typ = typ.value
+			continue
+		}
+		if len(typ.dims) > 0 {
+			for range typ.dims {
+				inputs = append(inputs, solAbiArg{Type: "uint256", InternalType: "uint256"})
+			}
+			stripped := *typ
+			stripped.dims = nil
+			typ = &stripped
+			continue
+		}
+		break
+	}
+
+	if key, kind := u.lookup(scope, typ.name); kind == "struct" {
+		str := u.structs[key]
+		for _, member := range str.members {
+			if member.typ.key != nil || len(member.typ.dims) > 0 {
+				continue
+			}
+			arg, err := u.abiArg(str.scope, member.name, member.typ, 0)
+			if err != nil {
+				return nil, nil, err
+			}
+			outputs = append(outputs, arg)
+		}
+		return
+	}
+
+	arg, err := u.abiArg(scope, "", typ, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inputs, []solAbiArg{arg}, nil
+}
+
+// abiArg converts a type as written in the given scope to its ABI type
+func (u *solUnit) abiArg(scope, name string, typ *solType, depth int) (arg solAbiArg, err error) {
+	if depth > 32 {
+		return arg, fmt.Errorf("%s is recursive", typ.name)
+	}
+
+	dims := ""
+	for _, dim := range typ.dims {
+		if dim != "" && !solNumberRe.MatchString(dim) {
+			if key, kind := u.lookup(scope, dim); kind == "constant" {
+				dim = u.constants[key]
+			}
+			if !solNumberRe.MatchString(dim) {
+				return arg, fmt.Errorf("unsupported array size %s", dim)
+			}
+		}
+		dims += "[" + dim + "]"
+	}
+
+	arg.Name = name
+	switch {
+	case typ.key != nil:
+		return arg, fmt.Errorf("mappings are not allowed in the ABI")
+
+	case typ.name == "function":
+		arg.Type = "function" + dims
+		arg.InternalType = arg.Type
+
+	case solElementaryRe.MatchString(typ.name):
+		arg.Type = canonicalSolType(typ.name) + dims
+		arg.InternalType = arg.Type
+		if typ.payable {
+			arg.InternalType = "address payable" + dims
+		}
+
+	default:
+		key, kind := u.lookup(scope, typ.name)
+		switch kind {
+		case "struct":
+			str := u.structs[key]
+			arg.Type = "tuple" + dims
+			arg.InternalType = "struct " + key + dims
+			arg.Components = make([]solAbiArg, 0, len(str.members))
+			for _, member := range str.members {
+				component, err := u.abiArg(str.scope, member.name, member.typ, depth+1)
+				if err != nil {
+					return arg, err
+				}
+				arg.Components = append(arg.Components, component)
+			}
+		case "enum":
+			arg.Type = "uint8" + dims
+			arg.InternalType = "enum " + key + dims
+		case "userType":
+			userType := u.userTypes[key]
+			if arg, err = u.abiArg(userType.scope, name, userType.underlying, depth+1); err != nil {
+				return
+			}
+			arg.Type += dims
+			arg.InternalType = key + dims
+		case "contract":
+			arg.Type = "address" + dims
+			arg.InternalType = "contract " + key + dims
+		default:
+			return arg, fmt.Errorf("unknown type %s", typ.name)
+		}
+	}
+	return
+}
+
+// lookup finds what a name refers to from within the given scope: first in the scope itself, then
+// in the contracts it inherits from, then at file level. Returns the key of the declaration and its
+// kind, or an empty kind if the name is not found.
+func (u *solUnit) lookup(scope, name string) (key string, kind string) {
+	parts := strings.Split(name, ".")
+	if alias, ok := u.aliases[parts[0]]; ok {
+		if alias == "" {
+			parts = parts[1:]
+		} else {
+			parts[0] = alias
+		}
+		if len(parts) == 0 {
+			return "", ""
+		}
+	}
+	name = strings.Join(parts, ".")
+
+	candidates := []string{}
+	if len(parts) == 1 {
+		visited := make(map[string]bool)
+		queue := []string{scope}
+		for len(queue) > 0 {
+			s := queue[0]
+			queue = queue[1:]
+			if s == "" || visited[s] {
+				continue
+			}
+			visited[s] = true
+			candidates = append(candidates, scopedName(s, name))
+			queue = append(queue, u.contracts[s]...)
+		}
+	}
+	candidates = append(candidates, name)
+
+	for _, candidate := range candidates {
+		switch {
+		case u.structs[candidate] != nil:
+			return candidate, "struct"
+		case u.enums[candidate]:
+			return candidate, "enum"
+		case u.userTypes[candidate] != nil:
+			return candidate, "userType"
+		case u.constants[candidate] != "":
+			return candidate, "constant"
+		}
+		if _, ok := u.contracts[candidate]; ok {
+			return candidate, "contract"
+		}
+	}
+	return "", ""
+}
+
+// canonicalSolType returns the canonical form of an elementary type (e.g., uint is uint256)
+func canonicalSolType(name string) string {
+	switch name {
+	case "uint", "int":
+		return name + "256"
+	case "byte":
+		return "bytes1"
+	case "fixed", "ufixed":
+		return name + "128x18"
+	}
+	return name
+}
@@ -0,0 +1,158 @@
+package abi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+const testSolTypes = `
+pragma solidity ^0.8.20;
+
+type Price is uint128;
+
+struct Order {
+    address maker;
+    uint256[] amounts;
+    Side side;
+}
+
+enum Side { Buy, Sell }
+`
+
+const testSolExchange = `
+pragma solidity ^0.8.20;
+
+import "./Types.sol";
+import {Order as O} from "./Types.sol";
+import "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+
+/// @notice An exchange
+interface IExchange {
+    struct Fill { O order; Price price; bytes32 id; }
+    event Filled(address indexed maker, Fill fill, string note);
+    error TooLate(uint deadline, Side side);
+    function fill(Order calldata order, Price price) external payable returns (Fill memory);
+    function fills(bytes32) external view returns (Fill[] memory result);
+}
+
+abstract contract Exchange is IExchange, Base(1) {
+    uint256 public constant MAX = 3;
+    mapping(address owner => mapping(uint => Fill)) public book;
+    IExchange[MAX] public peers;
+    string public name = "ex{change}";
+    uint private hidden;
+
+    constructor(address payable owner) { hidden = 1; }
+
+    modifier onlyOwner { _; }
+
+    /* fill is declared in the interface as well */
+    function fill(Order calldata order, Price price) external payable override returns (Fill memory f) {
+        if (true) { f.id = "}"; }
+    }
+    function _internal(uint a) internal pure returns (uint) { return a; }
+    function legacy(byte b, uint[2] memory pair) public onlyOwner returns (bool) { }
+    receive() external payable {}
+}
+
+contract Base { constructor(uint x) {} }
+`
+
+func TestLoadAbiFromSolFile(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "Types.sol"), []byte(testSolTypes), 0644)
+	solPath := filepath.Join(dir, "Exchange.sol")
+	_ = os.WriteFile(solPath, []byte(testSolExchange), 0644)
+
+	loaded := make(AbiInterfaceMap)
+	if err := LoadAbiFromSolFile(solPath, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"Filled(address,((address,uint256[],uint8),uint128,bytes32),string)",
+		"TooLate(uint256,uint8)",
+		"fill((address,uint256[],uint8),uint128)",
+		"fills(bytes32)",
+		"MAX()",
+		"book(address,uint256)",
+		"peers(uint256)",
+		"name()",
+		"legacy(bytes1,uint256[2])",
+	}
+	signatures := make(map[string]*types.SimpleFunction)
+	for _, function := range loaded {
+		signatures[function.Signature] = function
+		if function.AbiSource != "Exchange.sol" {
+			t.Error("unexpected source", function.AbiSource)
+		}
+	}
+	for _, signature := range expected {
+		if signatures[signature] == nil {
+			t.Error("missing", signature)
+		}
+	}
+	if len(loaded) != len(expected) {
+		t.Error("expected", len(expected), "functions, got", len(loaded), signatures)
+	}
+
+	book := signatures["book(address,uint256)"]
+	if book == nil || len(book.Outputs) != 3 || book.Outputs[1].ParameterType != "uint128" {
+		t.Error("the getter of a mapping to a struct should return the struct's members", book)
+	}
+	if fill := signatures["fill((address,uint256[],uint8),uint128)"]; fill == nil || fill.StateMutability != "payable" {
+		t.Error("unexpected fill", fill)
+	}
+}
+
+func TestSolInheritsFromImports(t *testing.T) {
+	dir := t.TempDir()
+	bases := `
+interface IBar {
+    event Barred(uint x);
+    error NotBar();
+    function bar() external;
+}
+contract BarBase is IBar { constructor(uint x) {} function baz(uint y) public {} function bar() external {} }
+interface IUnrelated { function unrelated() external; }
+`
+	_ = os.WriteFile(filepath.Join(dir, "Bases.sol"), []byte(bases), 0644)
+	solPath := filepath.Join(dir, "Foo.sol")
+	_ = os.WriteFile(solPath, []byte("import * as B from \"./Bases.sol\";\ncontract Foo is B.BarBase { function foo() external {} }\n"), 0644)
+
+	loaded := make(AbiInterfaceMap)
+	if err := LoadAbiFromSolFile(solPath, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	signatures := make(map[string]bool)
+	for _, function := range loaded {
+		signatures[function.Signature] = true
+	}
+	for _, signature := range []string{"foo()", "baz(uint256)", "bar()", "Barred(uint256)", "NotBar()"} {
+		if !signatures[signature] {
+			t.Error("missing", signature)
+		}
+	}
+	if signatures["unrelated()"] || len(loaded) != 5 {
+		t.Error("expected only the inherited declarations of the imported file", signatures)
+	}
+}
+
+func TestSolToAbiErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown type":     "interface I { function f(IERC20 token) external; }",
+		"unterminated":     "interface I { function f() external;",
+		"mapping argument": "contract C { function f(mapping(uint => uint) storage m) public {} }",
+	}
+	for name, source := range tests {
+		solPath := filepath.Join(t.TempDir(), "Test.sol")
+		_ = os.WriteFile(solPath, []byte(source), 0644)
+		if _, err := SolToAbi(solPath); err == nil {
+			t.Error("expected an error for", name)
+		}
+	}
+}
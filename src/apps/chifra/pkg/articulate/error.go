@@ -0,0 +1,131 @@
+package articulate
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	goEthAbi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	errorSelector = "0x08c379a0" // Error(string)
+	panicSelector = "0x4e487b71" // Panic(uint256)
+)
+
+// panicReasons are the Solidity compiler's panic codes
+// (https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require)
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// ArticulateTraceError articulates the revert data of a failed trace. Returns nil if the trace
+// didn't fail or the revert data is not understood.
+func ArticulateTraceError(trace *types.SimpleTrace, abiMap abi.AbiInterfaceMap) (*types.SimpleFunction, error) {
+	if len(trace.Error) == 0 || trace.Result == nil {
+		return nil, nil
+	}
+	return ArticulateRevert(trace.Result.Output, abiMap)
+}
+
+// ArticulateRevert articulates the data returned by a reverted call. It understands the
+// Error(string) and Panic(uint256) errors produced by Solidity and any custom error found in
+// abiMap. Returns nil if the data is not understood.
+func ArticulateRevert(output string, abiMap abi.AbiInterfaceMap) (*types.SimpleFunction, error) {
+	if len(output) < 10 {
+		return nil, nil
+	}
+	selector := strings.ToLower(output[:10])
+	data := output[10:]
+
+	switch selector {
+	case errorSelector:
+		message, err := unpackSingle("string", data)
+		if err != nil {
+			return nil, err
+		}
+		return &types.SimpleFunction{
+			Name:         "Error",
+			Signature:    "Error(string)",
+			Encoding:     errorSelector,
+			FunctionType: "error",
+			Inputs: []types.SimpleParameter{
+				{Name: "message", ParameterType: "string", Value: message},
+			},
+		}, nil
+
+	case panicSelector:
+		value, err := unpackSingle("uint256", data)
+		if err != nil {
+			return nil, err
+		}
+		code, ok := value.(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("unexpected panic code %v", value)
+		}
+		reason := panicReasons[code.Uint64()]
+		if !code.IsUint64() || reason == "" {
+			reason = "unknown panic code"
+		}
+		return &types.SimpleFunction{
+			Name:         "Panic",
+			Signature:    "Panic(uint256)",
+			Encoding:     panicSelector,
+			FunctionType: "error",
+			Inputs: []types.SimpleParameter{
+				{Name: "code", ParameterType: "uint256", Value: fmt.Sprintf("0x%x", code)},
+				{Name: "reason", ParameterType: "string", Value: reason},
+			},
+		}, nil
+	}
+
+	found := abiMap[selector]
+	if found == nil || !found.IsError() {
+		return nil, nil
+	}
+	abiError, err := found.GetAbiError()
+	if err != nil {
+		return nil, err
+	}
+
+	// The abiMap is shared, so we articulate into a copy
+	articulated := *found
+	articulated.Inputs = make([]types.SimpleParameter, len(found.Inputs))
+	copy(articulated.Inputs, found.Inputs)
+	if err = ArticulateArguments(abiError.Inputs, data, nil, articulated.Inputs); err != nil {
+		return nil, err
+	}
+	return &articulated, nil
+}
+
+// unpackSingle decodes data holding a single value of the given type
+func unpackSingle(typeName, data string) (any, error) {
+	dataBytes, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	abiType, err := goEthAbi.NewType(typeName, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	values, err := goEthAbi.Arguments{{Type: abiType}}.Unpack(dataBytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("expected one value, got %d", len(values))
+	}
+	return values[0], nil
+}
@@ -0,0 +1,56 @@
+package articulate
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestArticulateRevert(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, _ := abi.Arguments{{Type: stringType}}.Pack("Ownable: caller is not the owner")
+	result, err := ArticulateRevert(errorSelector+hex.EncodeToString(packed), nil)
+	if err != nil || result == nil || result.Name != "Error" || result.Inputs[0].Value != "Ownable: caller is not the owner" {
+		t.Fatal("unexpected Error(string) result", result, err)
+	}
+
+	uintType, _ := abi.NewType("uint256", "", nil)
+	packed, _ = abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	result, err = ArticulateRevert(panicSelector+hex.EncodeToString(packed), nil)
+	if err != nil || result == nil || result.Name != "Panic" || result.Inputs[1].Value != "arithmetic overflow or underflow" {
+		t.Fatal("unexpected Panic(uint256) result", result, err)
+	}
+}
+
+func TestArticulateCustomError(t *testing.T) {
+	const abiJson = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`
+	loaded, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethError := loaded.Errors["InsufficientBalance"]
+	customError := types.FunctionFromAbiError(&ethError, "test")
+	abiMap := map[string]*types.SimpleFunction{
+		customError.Encoding: customError,
+	}
+
+	packed, _ := ethError.Inputs.Pack(big.NewInt(10), big.NewInt(20))
+	result, err := ArticulateRevert(customError.Encoding+hex.EncodeToString(packed), abiMap)
+	if err != nil || result == nil || result.Name != "InsufficientBalance" {
+		t.Fatal("unexpected custom error result", result, err)
+	}
+	if result.Inputs[0].Value != "10" || result.Inputs[1].Value != "20" {
+		t.Fatal("unexpected custom error values", result.Inputs)
+	}
+	if customError.Inputs[0].Value != nil {
+		t.Fatal("articulation should not change the shared ABI")
+	}
+
+	if result, _ := ArticulateRevert("0xdeadbeef", abiMap); result != nil {
+		t.Fatal("expected unknown selectors to produce nothing", result)
+	}
+}
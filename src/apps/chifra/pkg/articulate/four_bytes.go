@@ -0,0 +1,71 @@
+package articulate
+
+import (
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// FindFunction articulates calldata whose selector is not found in any ABI using the four-byte
+// database. Candidates that fail to decode the input are dropped. If more than one candidate
+// remains, the first is returned with all of their signatures listed in its Ambiguous field.
+// Returns nil if no candidate decodes the data. (Signatures say nothing of a function's outputs,
+// so outputs are not articulated.)
+func FindFunction(chain, input string) (*types.SimpleFunction, error) {
+	if len(input) < 10 {
+		return nil, nil
+	}
+
+	candidates, err := abi.FourBytesCandidates(chain, input[:10], 0)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	matches := make([]*types.SimpleFunction, 0, len(candidates))
+	for _, candidate := range candidates {
+		if ArticulateFunction(candidate, input[10:], "") == nil {
+			matches = append(matches, candidate)
+		}
+	}
+	return pickCandidate(matches), nil
+}
+
+// FindEvent articulates a log whose topic is not found in any ABI using the four-byte database.
+// The log's topics tell how many of the event's parameters are indexed. Ambiguity is handled as
+// it is by FindFunction.
+func FindEvent(chain string, log *types.SimpleLog) (*types.SimpleFunction, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil
+	}
+
+	selector := log.Topics[0].Hex()
+	candidates, err := abi.FourBytesCandidates(chain, selector, len(log.Topics)-1)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	matches := make([]*types.SimpleFunction, 0, len(candidates))
+	for _, candidate := range candidates {
+		articulated, err := ArticulateLog(log, abi.AbiInterfaceMap{selector: candidate})
+		if err == nil && articulated != nil {
+			matches = append(matches, articulated)
+		}
+	}
+	return pickCandidate(matches), nil
+}
+
+// pickCandidate returns the first of the matching candidates, marking it as ambiguous if there
+// are others
+func pickCandidate(matches []*types.SimpleFunction) *types.SimpleFunction {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	ret := matches[0]
+	if len(matches) > 1 {
+		ret.Ambiguous = make([]string, 0, len(matches))
+		for _, match := range matches {
+			ret.Ambiguous = append(ret.Ambiguous, match.Signature)
+		}
+	}
+	return ret
+}
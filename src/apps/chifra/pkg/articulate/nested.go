@@ -0,0 +1,196 @@
+package articulate
+
+import (
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// maxNestingDepth limits how deeply wrapped calls are unwrapped
+const maxNestingDepth = 4
+
+// AbiLoader loads the ABI of an address at a block into an AbiInterfaceMap (see abi.LoadedAbis)
+type AbiLoader interface {
+	Load(chain string, address base.Address, bn uint64, destination abi.AbiInterfaceMap) error
+}
+
+// ArticulateNested unwraps the calls made by wrapper functions: multicall, the Multicall
+// contracts' aggregate family, a Safe's execTransaction, an EntryPoint's handleOps, and the
+// execute and executeBatch functions of smart accounts. The function must already be articulated.
+// Each inner call's ABI is loaded (at block bn) into abiMap by loaded, and the inner call is
+// articulated and unwrapped in turn.
+//
+// The functions in abiMap are shared, so if the function is a wrapper, a copy carrying the
+// nested calls is returned. Otherwise, the function itself is returned. Errors are those of the
+// first call that could not be articulated; those calls are reported with only their input.
+func ArticulateNested(chain string, bn uint64, to base.Address, function *types.SimpleFunction, abiMap abi.AbiInterfaceMap, loaded AbiLoader) (*types.SimpleFunction, error) {
+	return articulateNested(chain, bn, to, function, abiMap, loaded, 0)
+}
+
+func articulateNested(chain string, bn uint64, to base.Address, function *types.SimpleFunction, abiMap abi.AbiInterfaceMap, loaded AbiLoader, depth int) (*types.SimpleFunction, error) {
+	if function == nil || depth >= maxNestingDepth {
+		return function, nil
+	}
+
+	calls := nestedCalls(to, function)
+	if len(calls) == 0 {
+		return function, nil
+	}
+
+	var firstErr error
+	for i := range calls {
+		inner, err := articulateCall(chain, bn, &calls[i], abiMap, loaded, depth)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		calls[i].Function = inner
+	}
+
+	ret := function.Copy()
+	ret.Nested = calls
+	return ret, firstErr
+}
+
+// articulateCall returns the articulated (and unwrapped) inner call or nil if its ABI is unknown
+func articulateCall(chain string, bn uint64, call *types.SimpleNestedCall, abiMap abi.AbiInterfaceMap, loaded AbiLoader, depth int) (*types.SimpleFunction, error) {
+	if len(call.Input) < 10 {
+		return nil, nil
+	}
+
+	if err := loaded.Load(chain, call.To, bn, abiMap); err != nil {
+		return nil, err
+	}
+
+	found := abiMap[strings.ToLower(call.Input[:10])]
+	if found == nil || !found.IsMethod() {
+		return nil, nil
+	}
+
+	inner := found.Copy()
+	if err := ArticulateFunction(inner, call.Input[10:], ""); err != nil {
+		return nil, err
+	}
+	return articulateNested(chain, bn, call.To, inner, abiMap, loaded, depth+1)
+}
+
+// nestedCalls extracts the calls made by a wrapper function from its articulated inputs. to is
+// the address of the wrapper, which is the target of multicall's calls.
+func nestedCalls(to base.Address, function *types.SimpleFunction) []types.SimpleNestedCall {
+	switch function.Name {
+	case "multicall":
+		// multicall(bytes[]) and multicall(uint256 deadline, bytes[]) call the contract itself
+		if data := findInput(function.Inputs, "bytes[]"); data != nil {
+			return callsFromSlices(nil, to, data.Value)
+		}
+
+	case "aggregate", "tryAggregate", "blockAndAggregate", "tryBlockAndAggregate", "aggregate3", "aggregate3Value":
+		if calls := findInput(function.Inputs, "tuple[]"); calls != nil {
+			return callsFromTuples(calls.Value, "target", "callData")
+		}
+
+	case "handleOps":
+		if ops := findInput(function.Inputs, "tuple[]"); ops != nil {
+			return callsFromTuples(ops.Value, "sender", "callData")
+		}
+
+	case "execTransaction", "execute":
+		// Safe's execTransaction(address to, uint256 value, bytes data, ...) and a smart
+		// account's execute(address dest, uint256 value, bytes func)
+		target, data := findInput(function.Inputs, "address"), findInput(function.Inputs, "bytes")
+		if target != nil && data != nil {
+			return callsFromSlices([]any{target.Value}, base.Address{}, data.Value)
+		}
+
+	case "executeBatch":
+		// executeBatch(address[] dest, bytes[] func)
+		targets, data := findInput(function.Inputs, "address[]"), findInput(function.Inputs, "bytes[]")
+		if targets != nil && data != nil {
+			return callsFromSlices(targets.Value, base.Address{}, data.Value)
+		}
+	}
+	return nil
+}
+
+// findInput returns the first input of the given type or nil if there isn't one. Slices of
+// tuples, whose type is spelled out by go-ethereum (for example, "(address,bytes)[]"), are
+// found with the type "tuple[]".
+func findInput(inputs []types.SimpleParameter, parameterType string) *types.SimpleParameter {
+	for i := range inputs {
+		t := inputs[i].ParameterType
+		if parameterType == "tuple[]" && strings.HasPrefix(t, "(") && strings.HasSuffix(t, ")[]") {
+			t = "tuple[]"
+		}
+		if t == parameterType {
+			return &inputs[i]
+		}
+	}
+	return nil
+}
+
+// callsFromSlices pairs a slice of targets with a slice of calldata. If targets is nil, all calls
+// are made to defaultTo. A single, non-slice value for data is treated as a slice of one.
+func callsFromSlices(targets any, defaultTo base.Address, data ...any) []types.SimpleNestedCall {
+	if len(data) == 1 {
+		if items, ok := data[0].([]any); ok {
+			data = items
+		}
+	}
+
+	var targetList []any
+	if targets != nil {
+		var ok bool
+		if targetList, ok = targets.([]any); !ok {
+			targetList = []any{targets}
+		}
+		if len(targetList) != len(data) {
+			return nil
+		}
+	}
+
+	calls := make([]types.SimpleNestedCall, 0, len(data))
+	for i, item := range data {
+		input, ok := item.(string)
+		if !ok {
+			return nil
+		}
+		to := defaultTo
+		if targetList != nil {
+			addr, ok := targetList[i].(string)
+			if !ok {
+				return nil
+			}
+			to = base.HexToAddress(addr)
+		}
+		calls = append(calls, types.SimpleNestedCall{To: to, Input: input})
+	}
+	return calls
+}
+
+// callsFromTuples extracts calls from a slice of tuples, reading the target and the calldata from
+// the named fields
+func callsFromTuples(value any, toField, dataField string) []types.SimpleNestedCall {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	calls := make([]types.SimpleNestedCall, 0, len(items))
+	for _, item := range items {
+		tuple, ok := item.(map[string]any)
+		if !ok {
+			return nil
+		}
+		to, ok := tuple[toField].(string)
+		if !ok {
+			return nil
+		}
+		input, ok := tuple[dataField].(string)
+		if !ok {
+			return nil
+		}
+		calls = append(calls, types.SimpleNestedCall{To: base.HexToAddress(to), Input: input})
+	}
+	return calls
+}
@@ -0,0 +1,85 @@
+package articulate
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestArticulateNested(t *testing.T) {
+	const abiJson = `[
+		{"type":"function","name":"aggregate","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+		{"type":"function","name":"multicall","inputs":[{"name":"data","type":"bytes[]"}],"outputs":[]},
+		{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]}
+	]`
+	loaded, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	abiMap := map[string]*types.SimpleFunction{}
+	for _, method := range loaded.Methods {
+		method := method
+		function := types.FunctionFromAbiMethod(&method, "test")
+		abiMap[function.Encoding] = function
+	}
+
+	token := base.HexToAddress("0x00000000000000000000000000000000000000aa")
+	wrapper := base.HexToAddress("0x00000000000000000000000000000000000000bb")
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	transfer, _ := loaded.Pack("transfer", recipient, big.NewInt(42))
+	multicall, _ := loaded.Pack("multicall", [][]byte{transfer, transfer})
+	type call struct {
+		Target   common.Address
+		CallData []byte
+	}
+	aggregate, _ := loaded.Pack("aggregate", []call{
+		{Target: common.BytesToAddress(token.Bytes()), CallData: transfer},
+		{Target: common.BytesToAddress(wrapper.Bytes()), CallData: multicall},
+	})
+
+	input := "0x" + hex.EncodeToString(aggregate)
+	outer := abiMap[input[:10]].Copy()
+	if err := ArticulateFunction(outer, input[10:], ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ABIs are already in the map, so there's nothing to load
+	result, err := ArticulateNested("mainnet", 0, wrapper, outer, abiMap, preloaded{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Nested) != 2 {
+		t.Fatal("expected two nested calls, got", len(result.Nested))
+	}
+	first := result.Nested[0]
+	if first.To != token || first.Function == nil || first.Function.Name != "transfer" || first.Function.Inputs[1].Value != "42" {
+		t.Fatal("unexpected first call", first, first.Function)
+	}
+	second := result.Nested[1]
+	if second.To != wrapper || second.Function == nil || second.Function.Name != "multicall" || len(second.Function.Nested) != 2 {
+		t.Fatal("unexpected second call", second, second.Function)
+	}
+	if inner := second.Function.Nested[1]; inner.To != wrapper || inner.Function == nil || inner.Function.Name != "transfer" {
+		t.Fatal("multicall should call the wrapper itself", inner)
+	}
+
+	for _, function := range abiMap {
+		if len(function.Nested) > 0 || (len(function.Inputs) > 0 && function.Inputs[0].Value != nil) {
+			t.Fatal("articulation should not change the shared ABI", function.Name)
+		}
+	}
+}
+
+type preloaded struct{}
+
+func (preloaded) Load(chain string, address base.Address, bn uint64, destination map[string]*types.SimpleFunction) error {
+	return nil
+}
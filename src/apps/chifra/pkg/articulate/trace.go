@@ -13,6 +13,10 @@ func ArticulateTrace(trace *types.SimpleTrace, abiMap abi.AbiInterfaceMap) (arti
 	}
 	encoding := input[:10]
 	articulated = abiMap[encoding]
+	if articulated != nil && !articulated.IsMethod() {
+		// Custom errors share the selector-keyed map, but a trace's input is always a function call
+		articulated = nil
+	}
 
 	if trace.Result == nil || articulated == nil {
 		return
@@ -36,6 +40,11 @@ func ArticulateTrace(trace *types.SimpleTrace, abiMap abi.AbiInterfaceMap) (arti
 		}
 	}
 
+	// A failed trace's output is revert data (see ArticulateTraceError), not the function's outputs
+	if len(trace.Error) > 0 || len(trace.Result.Output) < 2 {
+		return
+	}
+
 	abiMethod, err = articulated.GetAbiMethod()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,146 @@
+package articulate
+
+import (
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/abi"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// ArticulateTransaction articulates the transaction along with its logs, its traces (if it
+// carries them), and, if it failed, its revert reason. The ABIs are loaded into abiMap by loaded
+// as they are needed. Errors don't stop the articulation, so all of them are returned.
+func ArticulateTransaction(chain string, tx *types.SimpleTransaction, abiMap abi.AbiInterfaceMap, loaded AbiLoader) (errs []error) {
+	var err error
+	if err = loaded.Load(chain, tx.To, uint64(tx.BlockNumber), abiMap); err != nil {
+		// continue processing even with an error
+		errs = append(errs, err)
+		err = nil
+	}
+
+	for index, log := range logsOf(tx) {
+		var err error
+		if err = loaded.Load(chain, log.Address, uint64(log.BlockNumber), abiMap); err != nil {
+			// continue processing even with an error
+			errs = append(errs, err)
+			err = nil
+		}
+		if err == nil {
+			tx.Receipt.Logs[index].ArticulatedLog, err = ArticulateLog(&log, abiMap)
+			if err != nil {
+				// continue processing even with an error
+				errs = append(errs, err)
+			}
+			if tx.Receipt.Logs[index].ArticulatedLog == nil {
+				if tx.Receipt.Logs[index].ArticulatedLog, err = FindEvent(chain, &log); err != nil {
+					// continue processing even with an error
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	for index, trace := range tx.Traces {
+		var err error
+		if err = loaded.Load(chain, trace.Action.To, uint64(trace.BlockNumber), abiMap); err != nil {
+			// continue processing even with an error
+			errs = append(errs, err)
+			err = nil
+		}
+		if err == nil {
+			tx.Traces[index].ArticulatedTrace, err = ArticulateTrace(&trace, abiMap)
+			if err != nil {
+				// continue processing even with an error
+				errs = append(errs, err)
+			}
+			if tx.Traces[index].ArticulatedTrace == nil {
+				if tx.Traces[index].ArticulatedTrace, err = FindFunction(chain, trace.Action.Input); err != nil {
+					// continue processing even with an error
+					errs = append(errs, err)
+				}
+			}
+			tx.Traces[index].ArticulatedTrace, err = ArticulateNested(chain, uint64(trace.BlockNumber), trace.Action.To, tx.Traces[index].ArticulatedTrace, abiMap, loaded)
+			if err != nil {
+				// continue processing even with an error
+				errs = append(errs, err)
+			}
+			tx.Traces[index].ArticulatedError, err = ArticulateTraceError(&trace, abiMap)
+			if err != nil {
+				// continue processing even with an error
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if tx.Receipt != nil && tx.IsError {
+		// The revert reason is only available from the transaction's top-level trace
+		if tx.Receipt.ArticulatedError, err = articulateTxError(chain, tx, abiMap); err != nil {
+			// continue processing even with an error
+			errs = append(errs, err)
+		}
+	}
+
+	var found *types.SimpleFunction
+	var selector string
+	if len(tx.Input) >= 10 {
+		selector = tx.Input[:10]
+		inputData := tx.Input[10:]
+		// Custom errors share the selector-keyed map, but calldata is always a function call
+		if found = abiMap[selector]; found != nil && !found.IsMethod() {
+			found = nil
+		}
+		if found != nil {
+			// The functions in abiMap are shared, so we articulate a copy
+			tx.ArticulatedTx = found.Copy()
+			var outputData string
+			if len(tx.Traces) > 0 && len(tx.Traces[0].Error) == 0 && tx.Traces[0].Result != nil && len(tx.Traces[0].Result.Output) > 2 {
+				outputData = tx.Traces[0].Result.Output[2:]
+			}
+			if err = ArticulateFunction(tx.ArticulatedTx, inputData, outputData); err != nil {
+				// continue processing even with an error
+				errs = append(errs, err)
+			}
+			if tx.ArticulatedTx, err = ArticulateNested(chain, uint64(tx.BlockNumber), tx.To, tx.ArticulatedTx, abiMap, loaded); err != nil {
+				// continue processing even with an error
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if found == nil && len(tx.Input) >= 10 {
+		if tx.ArticulatedTx, err = FindFunction(chain, tx.Input); err != nil {
+			// continue processing even with an error
+			errs = append(errs, err)
+		}
+	}
+
+	if tx.ArticulatedTx == nil && len(tx.Input) > 0 {
+		if message, ok := ArticulateString(tx.Input); ok {
+			tx.Message = message
+			// } else if len(selector) > 0 {
+			// 	// don't report this error
+			// 	errorChan <- fmt.Errorf("method/event not found: %s", selector)
+		}
+	}
+	return errs
+}
+
+func logsOf(tx *types.SimpleTransaction) []types.SimpleLog {
+	if tx.Receipt == nil {
+		return nil
+	}
+	return tx.Receipt.Logs
+}
+
+// articulateTxError articulates the revert reason of a failed transaction from its top-level
+// trace, fetching the traces if they weren't requested
+func articulateTxError(chain string, tx *types.SimpleTransaction, abiMap abi.AbiInterfaceMap) (*types.SimpleFunction, error) {
+	if len(tx.Traces) > 0 {
+		return tx.Traces[0].ArticulatedError, nil
+	}
+
+	traces, err := rpcClient.GetTracesByTransactionId(chain, uint64(tx.BlockNumber), uint64(tx.TransactionIndex))
+	if err != nil || len(traces) == 0 {
+		return nil, err
+	}
+	return ArticulateTraceError(&traces[0], abiMap)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
 	filePkg "github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/metrics"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 )
@@ -56,11 +57,21 @@ func save(chain string, filePath string, content io.Reader) (err error) {
 	return
 }
 
+var (
+	cacheHits   = metrics.NewCounter("cache_hits", "Number of items successfully opened from the cache")
+	cacheMisses = metrics.NewCounter("cache_misses", "Number of items requested from but not found in the cache")
+)
+
 // load opens binary cache file for reading
 func load(chain string, filePath string) (file io.ReadCloser, err error) {
 	cacheDir := getCacheAndChainPath(chain)
 	fullPath := path.Join(cacheDir, filePath)
 	file, err = os.Open(fullPath)
+	if err != nil {
+		cacheMisses.Inc()
+	} else {
+		cacheHits.Inc()
+	}
 	return
 }
 
@@ -210,7 +221,14 @@ func GetAbi(chain string, address base.Address) (simpleAbis []types.SimpleFuncti
 		events = append(events, *types.FunctionFromAbiEvent(&event, fileName))
 	}
 
+	customErrors := make([]types.SimpleFunction, 0, len(ethAbi.Errors))
+	for _, ethError := range ethAbi.Errors {
+		ethError := ethError
+		customErrors = append(customErrors, *types.FunctionFromAbiError(&ethError, fileName))
+	}
+
 	simpleAbis = append(functions, events...)
+	simpleAbis = append(simpleAbis, customErrors...)
 	return
 }
 
@@ -60,6 +60,38 @@ func GetSymbol(chain string) string {
 	return ch.Symbol
 }
 
+// GetEnsRegistry returns the address of the ENS registry configured for a chain, if any
+func GetEnsRegistry(chain string) string {
+	ch := GetRootConfig().Chains[chain]
+	return strings.ToLower(ch.EnsRegistry)
+}
+
+// GetAbiProviders returns the ABI providers for a chain in the order they should be tried. If none
+// are configured, mainnet uses Etherscan followed by Sourcify and other chains use Sourcify.
+func GetAbiProviders(chain string) []AbiProviderGroup {
+	providers := GetRootConfig().Chains[chain].AbiProviders
+	if len(providers) > 0 {
+		return providers
+	}
+
+	if chain == "mainnet" {
+		providers = append(providers, AbiProviderGroup{
+			Type: "etherscan",
+			Url:  "https://api.etherscan.io/api",
+			Key:  "etherscan",
+		})
+	}
+	return append(providers, AbiProviderGroup{
+		Type: "sourcify",
+		Url:  "https://sourcify.dev/server",
+	})
+}
+
+// GetApiKey returns the API key with the given name from the [keys] section
+func GetApiKey(name string) string {
+	return GetRootConfig().Keys[name].ApiKey
+}
+
 func cleanUrl(url string) string {
 	url = cleanPrefix(url)
 	if !strings.HasSuffix(url, "/") {
@@ -26,14 +26,27 @@ type versionGroup struct {
 }
 
 type chainGroup struct {
-	Chain          string `toml:"chain"`
-	ChainId        string `toml:"chainId"`
-	LocalExplorer  string `toml:"localExplorer"`
-	RemoteExplorer string `toml:"remoteExplorer"`
-	RpcProvider    string `toml:"rpcProvider"`
-	ApiProvider    string `toml:"apiProvider"`
-	IpfsGateway    string `toml:"ipfsGateway"`
-	Symbol         string `toml:"symbol"`
+	Chain          string             `toml:"chain"`
+	ChainId        string             `toml:"chainId"`
+	LocalExplorer  string             `toml:"localExplorer"`
+	RemoteExplorer string             `toml:"remoteExplorer"`
+	RpcProvider    string             `toml:"rpcProvider"`
+	ApiProvider    string             `toml:"apiProvider"`
+	IpfsGateway    string             `toml:"ipfsGateway"`
+	Symbol         string             `toml:"symbol"`
+	EnsRegistry    string             `toml:"ensRegistry"`
+	AbiProviders   []AbiProviderGroup `toml:"abiProviders"`
+}
+
+// AbiProviderGroup describes one of the places from which ABIs are downloaded. Type is one of
+// etherscan, sourcify, sourcifyLocal, or url. Url is the provider's base URL (or, for the url
+// type, a template), Key names an entry in [keys] holding the API key, and Path is the root of a
+// local Sourcify repository.
+type AbiProviderGroup struct {
+	Type string `toml:"type"`
+	Url  string `toml:"url"`
+	Key  string `toml:"key"`
+	Path string `toml:"path"`
 }
 
 type keyGroup struct {
@@ -47,6 +60,7 @@ type settingsGroup struct {
 	IndexPath      string `toml:"indexPath"`
 	DefaultChain   string `toml:"defaultChain"`
 	DefaultGateway string `toml:"defaultGateway"`
+	EnsCacheTtl    uint64 `toml:"ensCacheTtl"`
 }
 
 type ConfigFile struct {
@@ -75,6 +89,7 @@ func init() {
 	trueBlocksViper.SetDefault("Settings.IndexPath", GetPathToRootConfig()+"unchained/")
 	trueBlocksViper.SetDefault("Settings.DefaultChain", "mainnet")
 	trueBlocksViper.SetDefault("Settings.DefaultGateway", "https://ipfs.unchainedindex.io/ipfs")
+	trueBlocksViper.SetDefault("Settings.EnsCacheTtl", 24*60*60)
 }
 
 // GetRootConfig reads and the configuration located in trueBlocks.toml file. Note
@@ -161,6 +176,12 @@ func GetDefaultChain() string {
 	return GetRootConfig().Settings.DefaultChain
 }
 
+// GetEnsCacheTtl returns the number of seconds for which ENS resolutions are cached. Zero
+// disables the cache.
+func GetEnsCacheTtl() uint64 {
+	return GetRootConfig().Settings.EnsCacheTtl
+}
+
 func PathFromXDG(envVar string) (string, error) {
 	// If present, we require both an existing path and a fully qualified path
 	xdg := os.Getenv(envVar)
@@ -28,6 +28,9 @@ type ScrapeSettings struct {
 	Channel_count  uint64 `json:"-"`                      // Number of concurrent block processing channels
 	Allow_missing  bool   `json:"allowMissing,omitempty"` // Do not report errors for blockchain that contain blocks with zero addresses
 	// EXISTING_CODE
+	Adaptive      bool   `json:"adaptive,omitempty"` // Adjust the number of channels and blocks per pass to the node's responsiveness
+	Channel_min   uint64 `json:"-"`                  // When adaptive, the fewest concurrent block processing channels to use
+	Block_cnt_min uint64 `json:"-"`                  // When adaptive, the fewest number of blocks to process per pass
 	// EXISTING_CODE
 }
 
@@ -39,6 +42,9 @@ var defaultSettings = ScrapeSettings{
 	Channel_count:  20,
 	Allow_missing:  false,
 	// EXISTING_CODE
+	Adaptive:      false,
+	Channel_min:   2,
+	Block_cnt_min: 100,
 	// EXISTING_CODE
 }
 
@@ -50,6 +56,9 @@ var Unset = ScrapeSettings{
 	Channel_count:  utils.NOPOS,
 	Allow_missing:  false,
 	// EXISTING_CODE
+	Adaptive:      false,
+	Channel_min:   utils.NOPOS,
+	Block_cnt_min: utils.NOPOS,
 	// EXISTING_CODE
 }
 
@@ -71,6 +80,14 @@ func (s *ScrapeSettings) isDefault(chain, fldName string) bool {
 	}
 
 	// EXISTING_CODE
+	switch fldName {
+	case "Adaptive":
+		return s.Adaptive == def.Adaptive
+	case "Channel_min":
+		return s.Channel_min == def.Channel_min
+	case "Block_cnt_min":
+		return s.Block_cnt_min == def.Block_cnt_min
+	}
 	// EXISTING_CODE
 
 	return false
@@ -84,6 +101,9 @@ func (s *ScrapeSettings) TestLog(chain string, test bool) {
 	logger.TestLog(!s.isDefault(chain, "Channel_count"), "Channel_count: ", s.Channel_count)
 	logger.TestLog(!s.isDefault(chain, "Allow_missing"), "Allow_missing: ", s.Allow_missing)
 	// EXISTING_CODE
+	logger.TestLog(!s.isDefault(chain, "Adaptive"), "Adaptive: ", s.Adaptive)
+	logger.TestLog(!s.isDefault(chain, "Channel_min"), "Channel_min: ", s.Channel_min)
+	logger.TestLog(!s.isDefault(chain, "Block_cnt_min"), "Block_cnt_min: ", s.Block_cnt_min)
 	// EXISTING_CODE
 }
 
@@ -176,6 +196,15 @@ func (base *ScrapeSettings) overlay(chain string, overlay ScrapeSettings) {
 	}
 
 	// EXISTING_CODE
+	if !overlay.isDefault(chain, "Adaptive") && overlay.Adaptive {
+		base.Adaptive = overlay.Adaptive
+	}
+	if !overlay.isDefault(chain, "Channel_min") && overlay.Channel_min != 0 && overlay.Channel_min != utils.NOPOS {
+		base.Channel_min = overlay.Channel_min
+	}
+	if !overlay.isDefault(chain, "Block_cnt_min") && overlay.Block_cnt_min != 0 && overlay.Block_cnt_min != utils.NOPOS {
+		base.Block_cnt_min = overlay.Block_cnt_min
+	}
 	// EXISTING_CODE
 }
 
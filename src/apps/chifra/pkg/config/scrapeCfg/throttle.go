@@ -0,0 +1,65 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package scrapeCfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+)
+
+// ThrottleState records the throughput settings the scraper chose for its most recent pass
+// along with the measurements that led to that choice. The scraper writes it at the end of
+// each pass so other tools (for example, chifra status) may report on it.
+type ThrottleState struct {
+	Adaptive   bool    `json:"adaptive"`
+	Channels   uint64  `json:"channels"`
+	BlockCnt   uint64  `json:"blockCnt"`
+	LatencyMs  float64 `json:"latencyMs"`
+	ErrorRate  float64 `json:"errorRate"`
+	NCalls     uint64  `json:"nCalls"`
+	NErrors    uint64  `json:"nErrors"`
+	LastUpdate int64   `json:"lastUpdate"`
+}
+
+func (s *ThrottleState) String() string {
+	mode := "fixed"
+	if s.Adaptive {
+		mode = "adaptive"
+	}
+	return fmt.Sprintf("%d channels, %d blocks per pass (%s, %.1fms latency, %.2f%% errors)", s.Channels, s.BlockCnt, mode, s.LatencyMs, s.ErrorRate*100)
+}
+
+func getPathToThrottle(chain string) string {
+	return filepath.Join(config.GetPathToCache(chain), "tmp", "scraper_throttle.json")
+}
+
+// WriteThrottleState stores the scraper's current throughput settings for the given chain
+func WriteThrottleState(chain string, state *ThrottleState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getPathToThrottle(chain), b, 0644)
+}
+
+// ReadThrottleState returns the throughput settings most recently stored by the scraper for
+// the given chain or nil if the scraper has never run.
+func ReadThrottleState(chain string) (*ThrottleState, error) {
+	path := getPathToThrottle(chain)
+	if !file.FileExists(path) {
+		return nil, nil
+	}
+
+	state := &ThrottleState{}
+	if err := json.Unmarshal([]byte(file.AsciiFileToString(path)), state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -28,6 +28,18 @@ package identifiers
 // [date]-[date]
 // [date]-[date]:[stepOrPeriod]
 //
+// Any point may be followed by one or more offsets. An offset is a number of blocks
+// (+50) or a duration (+6h, -1d) where the units are s, m, h, d, and w (seconds,
+// minutes, hours, days, and weeks). Because '-' also separates the points of a range,
+// subtracting a number of blocks is only allowed from the anchors latest, finalized,
+// and safe (latest-1000). Durations may be subtracted from any point. Whitespace
+// around an offset is ignored.
+//
+// [point]+[blocks]
+// [point]+[duration]
+// [point]-[duration]
+// [anchor]-[blocks]
+//
 // 10
 // 10:100
 // 10:weekly
@@ -50,8 +62,15 @@ package identifiers
 // 2021-10-03T10:30:59:100
 // 2021-10-03T10:30:59-1000:100
 
+// latest-1000
+// london+50
+// 2023-01-01T00:00 + 6h
+// latest-1d-latest:12 (every 12th block in the last day)
+// finalized-100-finalized
+
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
@@ -60,10 +79,13 @@ import (
 // Define "tokens" for our lexer
 var rangeLexer = lexer.MustSimple([]lexer.Rule{
 	{Name: `Date`, Pattern: `\d{4}-\d{2}-\d{2}(T[\d]{2}(:[\d]{2})?(:[\d]{2})?(UTC)?)?`, Action: nil},
+	{Name: `Anchor`, Pattern: `(latest|finalized|safe)\s*-\s*[0-9]+[smhdw]?`, Action: nil},
 	{Name: `Special`, Pattern: `[a-z_]+[0-9]*`, Action: nil},
 	{Name: `Hash`, Pattern: `0x[a-f0-9]{64}`, Action: nil},
 	{Name: `Hex`, Pattern: `0x[a-f0-9]+`, Action: nil},
+	{Name: `Offset`, Pattern: `\+\s*[0-9]+[smhdw]?|-\s*[0-9]+[smhdw]`, Action: nil},
 	{Name: `Unsigned`, Pattern: `^[0-9]+`, Action: nil},
+	{Name: `whitespace`, Pattern: `\s+`, Action: nil},
 	{Name: `PointSeparator`, Pattern: `-`, Action: nil},
 	{Name: `ModifierSeparator`, Pattern: `:`, Action: nil},
 })
@@ -72,10 +94,11 @@ var rangeLexer = lexer.MustSimple([]lexer.Rule{
 // a block number, a date or special name (e.g. "london" is translated to
 // block 12965000)
 type Point struct {
-	Number  uint   `parser:"@Hex|@Unsigned" json:"number,omitempty"`
-	Hash    string `parser:"| @Hash" json:"hash,omitempty"`
-	Date    string `parser:"| @Date" json:"date,omitempty"`
-	Special string `parser:"| @Special" json:"special,omitempty"`
+	Number  uint     `parser:"(@Hex|@Unsigned" json:"number,omitempty"`
+	Hash    string   `parser:"| @Hash" json:"hash,omitempty"`
+	Date    string   `parser:"| @Date" json:"date,omitempty"`
+	Special string   `parser:"| @(Anchor|Special))" json:"special,omitempty"`
+	Offsets []string `parser:"@Offset*" json:"offsets,omitempty"`
 }
 
 func (p Point) String() string {
@@ -105,6 +128,7 @@ type Range struct {
 // Build parser
 var parser = participle.MustBuild(&Range{},
 	participle.Lexer(rangeLexer),
+	participle.Elide("whitespace"),
 )
 
 // Takes a string and tries to parse it into Range struct, which always have
@@ -113,6 +137,24 @@ var parser = participle.MustBuild(&Range{},
 func Parse(source string) (*Range, error) {
 	blockRange := &Range{}
 	err := parser.ParseString("", source, blockRange)
+	if err == nil {
+		for _, point := range blockRange.Points {
+			point.normalize()
+		}
+	}
 
 	return blockRange, err
 }
+
+// normalize splits an anchor with an offset (latest-1000) into the anchor and its offset and
+// removes whitespace from the offsets
+func (p *Point) normalize() {
+	if i := strings.IndexAny(p.Special, " \t-"); i > 0 {
+		offset := strings.TrimSpace(p.Special[i:])
+		p.Offsets = append([]string{offset}, p.Offsets...)
+		p.Special = p.Special[:i]
+	}
+	for i, offset := range p.Offsets {
+		p.Offsets[i] = strings.Join(strings.Fields(offset), "")
+	}
+}
@@ -464,3 +464,106 @@ func TestParseInvalidPeriod(t *testing.T) {
 		t.Error("Passes for invalid period")
 	}
 }
+
+func TestParseAnchorOffset(t *testing.T) {
+	out, err := Parse("latest-1000")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(out.Points) != 1 {
+		t.Error("Expected a single point, got", len(out.Points))
+	}
+
+	if out.Points[0].Special != "latest" {
+		t.Error("Mismatched anchor:", out.Points[0].Special)
+	}
+
+	if len(out.Points[0].Offsets) != 1 || out.Points[0].Offsets[0] != "-1000" {
+		t.Error("Mismatched offsets:", out.Points[0].Offsets)
+	}
+}
+
+func TestParseSpecialOffset(t *testing.T) {
+	out, err := Parse("london+50")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if out.Points[0].Special != "london" {
+		t.Error("Mismatched special:", out.Points[0].Special)
+	}
+
+	if len(out.Points[0].Offsets) != 1 || out.Points[0].Offsets[0] != "+50" {
+		t.Error("Mismatched offsets:", out.Points[0].Offsets)
+	}
+}
+
+func TestParseDateDuration(t *testing.T) {
+	out, err := Parse("2023-01-01T00:00 + 6h")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if out.Points[0].Date != "2023-01-01T00:00" {
+		t.Error("Mismatched date:", out.Points[0].Date)
+	}
+
+	if len(out.Points[0].Offsets) != 1 || out.Points[0].Offsets[0] != "+6h" {
+		t.Error("Mismatched offsets:", out.Points[0].Offsets)
+	}
+}
+
+func TestParseOffsetRange(t *testing.T) {
+	out, err := Parse("latest-1d-latest:12")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(out.Points) != 2 {
+		t.Error("Expected two points, got", len(out.Points))
+	}
+
+	if out.Points[0].Special != "latest" || len(out.Points[0].Offsets) != 1 || out.Points[0].Offsets[0] != "-1d" {
+		t.Error("Mismatched start:", out.Points[0])
+	}
+
+	if out.Points[1].Special != "latest" || len(out.Points[1].Offsets) != 0 {
+		t.Error("Mismatched end:", out.Points[1])
+	}
+
+	if out.Modifier.Step != 12 {
+		t.Error("Mismatched step:", out.Modifier.Step)
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		offset  string
+		amount  int64
+		seconds int64
+		ok      bool
+	}{
+		{"+50", 50, 0, true},
+		{"-1000", -1000, 0, true},
+		{"+6h", 6, 3600, true},
+		{"-1d", -1, 86400, true},
+		{"+2w", 2, 604800, true},
+		{"50", 0, 0, false},
+		{"+x", 0, 0, false},
+	}
+	for _, test := range tests {
+		amount, seconds, err := ParseOffset(test.offset)
+		if (err == nil) != test.ok {
+			t.Error("Unexpected result for", test.offset, err)
+			continue
+		}
+		if amount != test.amount || seconds != test.seconds {
+			t.Error("Mismatched offset for", test.offset, amount, seconds)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package identifiers
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
@@ -145,19 +146,102 @@ func (p *Point) resolvePoint(chain string) uint64 {
 	} else if p.Special != "" {
 		bn, _ = tslib.FromNameToBn(chain, p.Special)
 	} else if p.Number >= utils.EarliestEvmTs {
-		var err error
-		bn, err = tslib.FromTsToBn(chain, base.Timestamp(p.Number))
-		if err == tslib.ErrInTheFuture {
-			provider := config.GetRpcProvider(chain)
-			latest := rpcClient.BlockNumber(provider)
-			tsFuture := rpc.GetBlockTimestamp(chain, latest)
-			secs := uint64(tsFuture - base.Timestamp(p.Number))
-			blks := (secs / 13)
-			bn = latest + blks
-		}
+		bn = tsToBn(chain, base.Timestamp(p.Number))
 	} else {
 		bn = uint64(p.Number)
 	}
+
+	if len(p.Offsets) > 0 {
+		bn = p.applyOffsets(chain, bn)
+	}
+	return bn
+}
+
+// applyOffsets applies the point's offsets in order. Block offsets are added to the block number.
+// Durations are added to the timestamp (of the date or timestamp itself if the point is one and no
+// block offset came first, otherwise of the block) and the block at that time is found.
+func (p *Point) applyOffsets(chain string, bn uint64) uint64 {
+	var ts base.Timestamp
+	haveTs := false
+	if p.Date != "" {
+		if dt, err := tslib.FromDateToTs(p.Date); err == nil {
+			ts, haveTs = dt, true
+		}
+	} else if p.Special == "" && p.Hash == "" && p.Number >= utils.EarliestEvmTs {
+		ts, haveTs = base.Timestamp(p.Number), true
+	}
+
+	for _, offset := range p.Offsets {
+		amount, seconds, err := ParseOffset(offset)
+		if err != nil {
+			continue
+		}
+
+		if seconds == 0 {
+			if amount < 0 && uint64(-amount) > bn {
+				bn = 0
+			} else {
+				bn = uint64(int64(bn) + amount)
+			}
+			haveTs = false
+			continue
+		}
+
+		if !haveTs {
+			ts = rpc.GetBlockTimestamp(chain, bn)
+		}
+		ts += base.Timestamp(amount * seconds)
+		haveTs = true
+		bn = tsToBn(chain, ts)
+	}
+	return bn
+}
+
+// ParseOffset parses an offset such as +50, -1000, or -6h. It returns the signed amount and the
+// number of seconds per unit of the amount (zero if the amount is a number of blocks).
+func ParseOffset(offset string) (amount int64, seconds int64, err error) {
+	if len(offset) < 2 || (offset[0] != '+' && offset[0] != '-') {
+		return 0, 0, fmt.Errorf("invalid offset %s", offset)
+	}
+
+	digits := offset[1:]
+	switch digits[len(digits)-1] {
+	case 's':
+		seconds = 1
+	case 'm':
+		seconds = 60
+	case 'h':
+		seconds = 60 * 60
+	case 'd':
+		seconds = 24 * 60 * 60
+	case 'w':
+		seconds = 7 * 24 * 60 * 60
+	}
+	if seconds != 0 {
+		digits = digits[:len(digits)-1]
+	}
+
+	if amount, err = strconv.ParseInt(digits, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid offset %s", offset)
+	}
+	if offset[0] == '-' {
+		amount = -amount
+	}
+	return amount, seconds, nil
+}
+
+// tsToBn returns the block at or before the timestamp. Blocks in the future are estimated with
+// 13 second blocks.
+func tsToBn(chain string, ts base.Timestamp) uint64 {
+	bn, err := tslib.FromTsToBn(chain, ts)
+	if err == tslib.ErrInTheFuture {
+		provider := config.GetRpcProvider(chain)
+		latest := rpcClient.BlockNumber(provider)
+		tsFuture := rpc.GetBlockTimestamp(chain, latest)
+		secs := uint64(ts - tsFuture)
+		blks := (secs / 13)
+		bn = latest + blks
+	}
 	return bn
 }
 
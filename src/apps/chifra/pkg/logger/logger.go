@@ -18,7 +18,8 @@ type severity int
 
 const (
 	progress severity = iota
-	infoC             // colored table
+	debug
+	infoC // colored table
 	info
 	test
 	warning
@@ -27,6 +28,7 @@ const (
 
 var severityToLabel = map[severity]string{
 	progress: "PROG",
+	debug:    "DBUG",
 	infoC:    "INFO",
 	info:     "INFO",
 	test:     "TEST",
@@ -96,6 +98,24 @@ func toLog(sev severity, a ...interface{}) {
 	}
 }
 
+var (
+	debugModeSet = false
+	debugMode    = false
+)
+
+// Debug prints `v` only if TB_DEBUG is set to true in the environment
+func Debug(v ...any) {
+	if !debugModeSet {
+		debugModeSet = true
+		debugMode = os.Getenv("TB_DEBUG") == "true"
+	}
+
+	if !debugMode {
+		return
+	}
+	toLog(debug, v...)
+}
+
 func InfoTable(v ...any) {
 	toLog(infoC, v...)
 }
@@ -0,0 +1,4 @@
+// Package metrics provides counters, gauges, and summaries that the scraper, the monitor freshener,
+// the RPC layer, and the cache update as they work. The daemon exposes them in OpenMetrics text
+// format on its /metrics route.
+package metrics
@@ -0,0 +1,167 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the OpenMetrics type of a metric
+type Kind string
+
+const (
+	KindCounter Kind = "counter"
+	KindGauge   Kind = "gauge"
+	KindSummary Kind = "summary"
+)
+
+// Metric is implemented by every metric that may be registered
+type Metric interface {
+	Name() string
+	Help() string
+	Kind() Kind
+	samples() []sample
+}
+
+type sample struct {
+	suffix string
+	labels string
+	value  float64
+}
+
+type desc struct {
+	name string
+	help string
+}
+
+func (d *desc) Name() string { return d.name }
+func (d *desc) Help() string { return d.help }
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	desc
+	value uint64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+// Add adds n to the counter
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.value, n)
+}
+
+// Value returns the counter's current value
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func (c *Counter) Kind() Kind { return KindCounter }
+
+func (c *Counter) samples() []sample {
+	return []sample{{suffix: "_total", value: float64(c.Value())}}
+}
+
+// CounterVec is a family of counters distinguished by the value of a single label
+type CounterVec struct {
+	desc
+	label    string
+	mutex    sync.Mutex
+	counters map[string]*uint64
+}
+
+// Inc adds one to the counter for the given label value
+func (v *CounterVec) Inc(value string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.counters[value] == nil {
+		v.counters[value] = new(uint64)
+	}
+	*v.counters[value]++
+}
+
+func (v *CounterVec) Kind() Kind { return KindCounter }
+
+func (v *CounterVec) samples() []sample {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	keys := make([]string, 0, len(v.counters))
+	for k := range v.counters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ret := make([]sample, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, sample{suffix: "_total", labels: v.label + "=\"" + escapeLabel(k) + "\"", value: float64(*v.counters[k])})
+	}
+	return ret
+}
+
+// Gauge is a value that may go up or down
+type Gauge struct {
+	desc
+	bits uint64
+}
+
+// Set sets the gauge to the given value
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+// Value returns the gauge's current value
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+func (g *Gauge) Kind() Kind { return KindGauge }
+
+func (g *Gauge) samples() []sample {
+	return []sample{{value: g.Value()}}
+}
+
+// Summary tracks the count and the total of a series of observations (usually durations in seconds)
+type Summary struct {
+	desc
+	mutex sync.Mutex
+	count uint64
+	sum   float64
+}
+
+// Observe adds a single observation to the summary
+func (s *Summary) Observe(value float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.count++
+	s.sum += value
+}
+
+// ObserveSince adds the number of seconds elapsed since start to the summary
+func (s *Summary) ObserveSince(start time.Time) {
+	s.Observe(time.Since(start).Seconds())
+}
+
+// Count returns the number of observations
+func (s *Summary) Count() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
+
+func (s *Summary) Kind() Kind { return KindSummary }
+
+func (s *Summary) samples() []sample {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return []sample{
+		{suffix: "_count", value: float64(s.count)},
+		{suffix: "_sum", value: s.sum},
+	}
+}
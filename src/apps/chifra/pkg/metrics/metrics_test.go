@@ -0,0 +1,50 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	counter := NewCounter("test_widgets", "Number of widgets")
+	vec := NewCounterVec("test_calls", "Number of calls by method", "method")
+	gauge := NewGauge("test_height", "Current height")
+	summary := NewSummary("test_seconds", "Time spent")
+
+	counter.Add(3)
+	counter.Inc()
+	vec.Inc("eth_getLogs")
+	vec.Inc("trace_block")
+	vec.Inc("eth_getLogs")
+	gauge.Set(12.5)
+	summary.Observe(0.5)
+	summary.Observe(1.5)
+
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	expected := []string{
+		"# TYPE trueblocks_test_widgets counter\n# HELP trueblocks_test_widgets Number of widgets\ntrueblocks_test_widgets_total 4\n",
+		"trueblocks_test_calls_total{method=\"eth_getLogs\"} 2\ntrueblocks_test_calls_total{method=\"trace_block\"} 1\n",
+		"# TYPE trueblocks_test_height gauge\n",
+		"trueblocks_test_height 12.5\n",
+		"trueblocks_test_seconds_count 2\ntrueblocks_test_seconds_sum 2\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(got, e) {
+			t.Error("expected output to contain", e, "\ngot:", got)
+		}
+	}
+
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Error("expected output to end with # EOF")
+	}
+}
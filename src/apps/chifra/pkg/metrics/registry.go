@@ -0,0 +1,98 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// namespace is prepended to the name of every metric
+const namespace = "trueblocks_"
+
+var (
+	registryMutex sync.Mutex
+	registry      = map[string]Metric{}
+)
+
+func register[T Metric](m T) T {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if _, ok := registry[m.Name()]; ok {
+		panic("metrics: duplicate metric " + m.Name())
+	}
+	registry[m.Name()] = m
+	return m
+}
+
+// NewCounter creates and registers a counter. The name should not include the `_total` suffix.
+func NewCounter(name, help string) *Counter {
+	return register(&Counter{desc: desc{name: namespace + name, help: help}})
+}
+
+// NewCounterVec creates and registers a family of counters keyed by a single label
+func NewCounterVec(name, help, label string) *CounterVec {
+	return register(&CounterVec{desc: desc{name: namespace + name, help: help}, label: label, counters: map[string]*uint64{}})
+}
+
+// NewGauge creates and registers a gauge
+func NewGauge(name, help string) *Gauge {
+	return register(&Gauge{desc: desc{name: namespace + name, help: help}})
+}
+
+// NewSummary creates and registers a summary
+func NewSummary(name, help string) *Summary {
+	return register(&Summary{desc: desc{name: namespace + name, help: help}})
+}
+
+// WriteOpenMetrics writes every registered metric to w in OpenMetrics text format
+func WriteOpenMetrics(w io.Writer) error {
+	registryMutex.Lock()
+	metrics := make([]Metric, 0, len(registry))
+	for _, m := range registry {
+		metrics = append(metrics, m)
+	}
+	registryMutex.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name() < metrics[j].Name()
+	})
+
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", m.Name(), m.Kind(), m.Name(), escapeHelp(m.Help())); err != nil {
+			return err
+		}
+		for _, s := range m.samples() {
+			labels := ""
+			if len(s.labels) > 0 {
+				labels = "{" + s.labels + "}"
+			}
+			if _, err := fmt.Fprintf(w, "%s%s%s %s\n", m.Name(), s.suffix, labels, formatValue(s.value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, "# EOF\n")
+	return err
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeHelp(help string) string {
+	help = strings.ReplaceAll(help, "\\", "\\\\")
+	return strings.ReplaceAll(help, "\n", "\\n")
+}
+
+func escapeLabel(value string) string {
+	value = escapeHelp(value)
+	return strings.ReplaceAll(value, "\"", "\\\"")
+}
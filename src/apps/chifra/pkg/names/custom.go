@@ -15,6 +15,9 @@ import (
 var loadedCustomNames map[base.Address]types.SimpleName = map[base.Address]types.SimpleName{}
 var loadedCustomNamesMutex sync.Mutex
 
+// customNamesRead is set once the custom names database has been read (it may be empty)
+var customNamesRead bool
+
 // We don't want to save test names even in test database
 var testAddresses map[string]bool = map[string]bool{
 	"0x0000000000000000000000000000000000000001": true,
@@ -46,6 +49,7 @@ func loadCustomMap(chain string, terms []string, parts Parts, destination *map[b
 	if err != nil {
 		return err
 	}
+	customNamesRead = true
 	if parts&Testing != 0 {
 		loadTestNames(terms, parts, &loadedCustomNames, destination)
 	}
@@ -114,7 +118,17 @@ func CreateCustomName(chain string, name *types.SimpleName) (err error) {
 		return
 	}
 	defer db.Close()
-	return setCustomNameAndSave(db, name)
+
+	previous := ReadCustomName(name.Address)
+	if err = setCustomNameAndSave(db, name); err != nil {
+		return
+	}
+
+	operation := JournalCreate
+	if previous != nil {
+		operation = JournalUpdate
+	}
+	return journalChange(chain, operation, previous, name)
 }
 
 func ReadCustomName(address base.Address) (name *types.SimpleName) {
@@ -131,7 +145,12 @@ func UpdateCustomName(chain string, name *types.SimpleName) (result *types.Simpl
 		return
 	}
 	defer db.Close()
-	return setIfExists(db, name)
+
+	previous := ReadCustomName(name.Address)
+	if result, err = setIfExists(db, name); err != nil {
+		return
+	}
+	return result, journalChange(chain, JournalUpdate, previous, result)
 }
 
 func setIfExists(output *os.File, name *types.SimpleName) (result *types.SimpleName, err error) {
@@ -149,7 +168,16 @@ func ChangeCustomNameDeletedFlag(chain string, address base.Address, deleted boo
 	}
 	defer db.Close()
 
-	return changeDeleted(db, address, deleted)
+	previous := ReadCustomName(address)
+	if name, err = changeDeleted(db, address, deleted); err != nil {
+		return
+	}
+
+	operation := JournalUndelete
+	if deleted {
+		operation = JournalDelete
+	}
+	return name, journalChange(chain, operation, previous, name)
 }
 
 func changeDeleted(output *os.File, address base.Address, deleted bool) (name *types.SimpleName, err error) {
@@ -171,7 +199,10 @@ func RemoveCustomName(chain string, address base.Address) (name *types.SimpleNam
 	}
 	defer db.Close()
 
-	return removeIfExists(db, address)
+	if name, err = removeIfExists(db, address); err != nil {
+		return
+	}
+	return name, journalChange(chain, JournalRemove, name, nil)
 }
 
 func removeIfExists(output *os.File, address base.Address) (name *types.SimpleName, err error) {
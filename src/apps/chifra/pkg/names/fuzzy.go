@@ -0,0 +1,208 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// Base scores of the ways a term may match a field. An exact address outranks everything else.
+const (
+	scoreAddress      = 1000
+	scoreAddressStart = 100
+	scoreField        = 100
+	scoreToken        = 80
+	scoreFieldPrefix  = 60
+	scoreTokenPrefix  = 50
+	scoreContains     = 35
+	scoreTypo         = 30
+	scorePrefixTypo   = 15
+	scoreCoverage     = 10
+)
+
+// fuzzyField is a field of a name searched by a fuzzy search. The weight (a percentage) ranks
+// matches on symbols above matches on names above matches on the other fields.
+type fuzzyField struct {
+	value  string
+	weight int
+}
+
+// fuzzyScore returns the relevance of the name to the terms or zero if any of the terms does not
+// match the name. Terms match words in the name's fields exactly, as prefixes (so partially typed
+// words match), as substrings, or within a small edit distance (so typos match). Matching is case
+// insensitive unless parts includes MatchCase.
+func fuzzyScore(name *types.SimpleName, terms []string, parts Parts) int {
+	fold := func(s string) string {
+		if parts&MatchCase != 0 {
+			return s
+		}
+		return strings.ToLower(s)
+	}
+
+	fields := []fuzzyField{
+		{fold(name.Symbol), 120},
+		{fold(name.Name), 100},
+		{fold(name.Tags), 50},
+	}
+	if parts&Expanded != 0 {
+		fields = append(fields, fuzzyField{fold(name.Source), 40}, fuzzyField{fold(name.Petname), 40})
+	}
+
+	total := 0
+	for _, term := range terms {
+		score := 0
+		if strings.HasPrefix(strings.ToLower(term), "0x") {
+			score = addressScore(name, strings.ToLower(term))
+		} else {
+			term = fold(term)
+			for _, field := range fields {
+				if s := termScore(term, field.value) * field.weight / 100; s > score {
+					score = s
+				}
+			}
+		}
+		if score == 0 {
+			return 0
+		}
+		total += score
+	}
+
+	if len(terms) > 1 {
+		// Terms that together are (or start) a field score as if they were a single term
+		phrase := fold(strings.Join(terms, " "))
+		bonus := 0
+		for _, field := range fields {
+			s := 0
+			if field.value == phrase {
+				s = scoreField
+			} else if strings.HasPrefix(field.value, phrase) {
+				s = scoreFieldPrefix
+			}
+			bonus = utils.Max(bonus, s*field.weight/100)
+		}
+		total += bonus
+	}
+	return total
+}
+
+// addressScore scores a term that looks like an address. Partial addresses must have at least
+// two hex digits to match.
+func addressScore(name *types.SimpleName, term string) int {
+	addr := name.Address.Hex()
+	switch {
+	case term == addr:
+		return scoreAddress
+	case len(term) >= 4 && strings.HasPrefix(addr, term):
+		return scoreAddressStart * len(term) / len(addr)
+	default:
+		return 0
+	}
+}
+
+// termScore returns the best score of the ways the term matches the value
+func termScore(term, value string) int {
+	if len(term) == 0 || len(value) == 0 {
+		return 0
+	}
+	if value == term {
+		return scoreField
+	}
+
+	best := 0
+	improve := func(score int) {
+		if score > best {
+			best = score
+		}
+	}
+
+	termLen := len([]rune(term))
+	maxTypos := allowedTypos(term)
+	for _, token := range tokenize(value) {
+		switch {
+		case token == term:
+			improve(scoreToken)
+		case strings.HasPrefix(token, term):
+			// Longer prefixes of a token score higher than shorter ones
+			improve(scoreTokenPrefix + (scoreToken-scoreTokenPrefix)*len(term)/(len(token)+1))
+		case maxTypos > 0:
+			if d := editDistance(term, token, maxTypos); d <= maxTypos {
+				improve(scoreTypo - 10*(d-1))
+			} else if runes := []rune(token); len(runes) > termLen {
+				if d := editDistance(term, string(runes[:termLen]), maxTypos); d <= maxTypos {
+					improve(scorePrefixTypo - 5*(d-1))
+				}
+			}
+		}
+	}
+
+	if best < scoreFieldPrefix && strings.HasPrefix(value, term) {
+		improve(scoreFieldPrefix)
+	} else if best < scoreContains && strings.Contains(value, term) {
+		improve(scoreContains)
+	}
+
+	if best > 0 {
+		// Terms that cover more of the field are better matches (Uniswap over Uniswap V2)
+		best += scoreCoverage * utils.Min(termLen, len([]rune(value))) / len([]rune(value))
+	}
+	return best
+}
+
+// allowedTypos returns the number of typos (the edit distance) tolerated in a term. Short terms
+// must match exactly, otherwise every term would match something.
+func allowedTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// tokenize splits a value into its words
+func tokenize(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// editDistance returns the edit distance between a and b (counting insertions, deletions,
+// substitutions, and transpositions of adjacent characters) or max+1 if it is greater than max
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	// Three rows of the distance matrix: two rows back (for transpositions), the last, and this one
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = utils.Min(utils.Min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = utils.Min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = utils.Min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return utils.Min(prev[len(rb)], max+1)
+}
@@ -0,0 +1,71 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		max      int
+		expected int
+	}{
+		{"uniswap", "uniswap", 2, 0},
+		{"uniswap", "uniswop", 2, 1},
+		{"uniwsap", "uniswap", 2, 1}, // a transposition is a single edit
+		{"unswap", "uniswap", 2, 1},
+		{"tether", "teather", 2, 1},
+		{"compound", "uniswap", 2, 3},
+		{"a", "abcdef", 2, 3},
+		{"über", "uber", 1, 1},
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b, test.max); got != test.expected {
+			t.Errorf("editDistance(%q, %q, %d) = %d, expected %d", test.a, test.b, test.max, got, test.expected)
+		}
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	uniswap := types.SimpleName{Address: base.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdaddc4201f984"), Name: "Uniswap", Symbol: "UNI", Tags: "50-Tokens:ERC20"}
+	uniswapV2 := types.SimpleName{Address: base.HexToAddress("0x004375dff511095cc5a197a54140a24efef3a416"), Name: "Uniswap V2", Symbol: "UNI-V2", Tags: "50-Tokens:ERC20"}
+	unicorn := types.SimpleName{Address: base.HexToAddress("0x89205a3a3b2a69de6dbf7f01ed13b2108b2c43e7"), Name: "Unicorn Token", Symbol: "UNICORN", Tags: "50-Tokens:ERC20"}
+	tether := types.SimpleName{Address: base.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7"), Name: "Tether USD", Symbol: "USDT", Tags: "50-Tokens:ERC20"}
+	tetherGnosis := types.SimpleName{Address: base.HexToAddress("0x4ecaba5870353805a9f068101a40e0f32ed605c6"), Name: "Tether USD on Gnosis", Symbol: "USD", Tags: "90-Individuals:Other"}
+
+	score := func(name types.SimpleName, terms ...string) int {
+		return fuzzyScore(&name, terms, Regular)
+	}
+	ranks := func(terms []string, better, worse types.SimpleName) {
+		if b, w := score(better, terms...), score(worse, terms...); b <= w {
+			t.Errorf("%v: expected %s (%d) to rank above %s (%d)", terms, better.Name, b, worse.Name, w)
+		}
+	}
+
+	ranks([]string{"uniswap"}, uniswap, uniswapV2)
+	ranks([]string{"uniwsap"}, uniswap, uniswapV2)
+	ranks([]string{"uni"}, uniswap, unicorn)
+	ranks([]string{"unic"}, unicorn, uniswap)
+	ranks([]string{"tether", "usd"}, tether, tetherGnosis)
+	ranks([]string{"usdt"}, tether, tetherGnosis)
+	ranks([]string{"0xdac17f958d2ee523a2206206994597c13d831ec7"}, tether, tetherGnosis)
+
+	if score(uniswap, "UNISWAP") == 0 {
+		t.Error("expected matching to be case insensitive")
+	}
+	if s := fuzzyScore(&uniswap, []string{"UNISWAP"}, Regular|MatchCase); s != 0 {
+		t.Error("expected --match_case to be honored, got", s)
+	}
+	if score(uniswap, "uniswap", "compound") != 0 {
+		t.Error("expected every term to have to match")
+	}
+	if score(uniswap, "xyz") != 0 || score(uniswap, "0xdac1") != 0 {
+		t.Error("expected unrelated terms not to match")
+	}
+	if score(tetherGnosis, "gnosis") == 0 || score(tetherGnosis, "individuals") == 0 {
+		t.Error("expected the name and tags to be searched")
+	}
+}
@@ -0,0 +1,309 @@
+package names
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// ConflictStrategy is how an imported name is resolved against an existing name for the same address
+type ConflictStrategy string
+
+const (
+	// ConflictKeep keeps the existing name and ignores the imported name
+	ConflictKeep ConflictStrategy = "keep"
+	// ConflictOverwrite replaces the existing name with the imported name
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	// ConflictMerge updates the existing name with the imported name's non-empty fields (including
+	// its tags, so a name keeps a single tag)
+	ConflictMerge ConflictStrategy = "merge"
+)
+
+// The actions an import (or a rollback) may take for a name
+const (
+	ImportAdd       = "add"
+	ImportUpdate    = "update"
+	ImportKeep      = "keep"
+	ImportUnchanged = "unchanged"
+	RollbackRemove  = "remove"
+)
+
+// importFields are the name fields that may be the target of a mapping
+var importFields = map[string]bool{
+	"tags":       true,
+	"address":    true,
+	"name":       true,
+	"symbol":     true,
+	"source":     true,
+	"decimals":   true,
+	"petname":    true,
+	"deleted":    true,
+	"iscustom":   true,
+	"isprefund":  true,
+	"iscontract": true,
+	"iserc20":    true,
+	"iserc721":   true,
+}
+
+// ParseMapping parses a field mapping of the form "column=field,column=field" (for example,
+// "label=name,category=tags"). Columns and fields are not case sensitive.
+func ParseMapping(str string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if len(strings.TrimSpace(str)) == 0 {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(str, ",") {
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, fmt.Errorf("invalid mapping %q, expected column=field", pair)
+		}
+		column := strings.ToLower(strings.TrimSpace(parts[0]))
+		field := strings.ToLower(strings.TrimSpace(parts[1]))
+		if !importFields[field] {
+			return nil, fmt.Errorf("invalid mapping %q, %s is not a name field", pair, field)
+		}
+		mapping[column] = field
+	}
+	return mapping, nil
+}
+
+// ReadImportFile reads the names in a file from another source. Files ending in .json hold an
+// array of objects (or an object whose data field is such an array, as chifra names --fmt json
+// produces). Files ending in .csv are comma separated. Other files are tab separated. The first
+// row of a separated file names its columns.
+func ReadImportFile(path string, mapping map[string]string) ([]types.SimpleName, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	var ret []types.SimpleName
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		ret, err = readImportJson(fp, mapping)
+	case ".csv":
+		ret, err = readImportSeparated(fp, NameReaderComma, mapping)
+	default:
+		ret, err = readImportSeparated(fp, NameReaderTab, mapping)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ret, nil
+}
+
+func readImportSeparated(source io.Reader, mode NameReaderMode, mapping map[string]string) ([]types.SimpleName, error) {
+	reader, err := NewMappedNameReader(source, mode, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []types.SimpleName{}
+	for row := 2; ; row++ {
+		name, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if name.Address.IsZero() {
+			return nil, fmt.Errorf("row %d has no valid address", row)
+		}
+		ret = append(ret, name)
+	}
+	return ret, nil
+}
+
+func readImportJson(source io.Reader, mapping map[string]string) ([]types.SimpleName, error) {
+	contents, err := io.ReadAll(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]any
+	if err = json.Unmarshal(contents, &records); err != nil {
+		wrapped := struct {
+			Data []map[string]any `json:"data"`
+		}{}
+		if err = json.Unmarshal(contents, &wrapped); err != nil {
+			return nil, err
+		}
+		records = wrapped.Data
+	}
+
+	ret := make([]types.SimpleName, 0, len(records))
+	for i, record := range records {
+		fields := map[string]string{}
+		for key, value := range record {
+			if value != nil {
+				fields[fieldName(key, mapping)] = fmt.Sprint(value)
+			}
+		}
+		name := nameFromFields(func(field string) string {
+			return fields[field]
+		})
+		if name.Address.IsZero() {
+			return nil, fmt.Errorf("record %d has no valid address", i+1)
+		}
+		ret = append(ret, name)
+	}
+	return ret, nil
+}
+
+// NameChange is the result of importing a name. Previous is the name the address had before the
+// import (if any) and Changed lists the fields that differ between the two.
+type NameChange struct {
+	Action   string
+	Name     types.SimpleName
+	Previous *types.SimpleName
+	Changed  []FieldChange
+}
+
+// FieldChange is a field whose value an import changes
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// ResolveImport determines what importing the names does to a database holding the existing
+// names. Names imported later in the list are resolved against names imported earlier. Imported
+// names are custom names and are given a petname if they have none.
+func ResolveImport(existing map[base.Address]types.SimpleName, imported []types.SimpleName, strategy ConflictStrategy) []NameChange {
+	current := make(map[base.Address]types.SimpleName, len(existing))
+	for addr, name := range existing {
+		current[addr] = name
+	}
+
+	changes := make([]NameChange, 0, len(imported))
+	for _, name := range imported {
+		name.IsCustom = true
+		if len(name.Petname) == 0 {
+			name.Petname = AddrToPetname(name.Address.Hex(), "-")
+		}
+
+		previous, ok := current[name.Address]
+		if !ok {
+			current[name.Address] = name
+			changes = append(changes, NameChange{Action: ImportAdd, Name: name})
+			continue
+		}
+
+		switch strategy {
+		case ConflictOverwrite:
+			// nothing to do, the imported name replaces the previous one
+		case ConflictMerge:
+			name = mergeNames(previous, name)
+		default:
+			changes = append(changes, NameChange{Action: ImportKeep, Name: previous, Previous: &previous})
+			continue
+		}
+
+		changed := changedFields(&previous, &name)
+		if len(changed) == 0 {
+			changes = append(changes, NameChange{Action: ImportUnchanged, Name: previous, Previous: &previous})
+			continue
+		}
+		current[name.Address] = name
+		changes = append(changes, NameChange{Action: ImportUpdate, Name: name, Previous: &previous, Changed: changed})
+	}
+	return changes
+}
+
+// mergeNames returns the previous name updated with the non-empty fields of the imported name
+func mergeNames(previous, imported types.SimpleName) types.SimpleName {
+	merged := previous
+	merged.IsCustom = true
+	if len(imported.Name) > 0 {
+		merged.Name = imported.Name
+	}
+	if len(imported.Symbol) > 0 {
+		merged.Symbol = imported.Symbol
+	}
+	if len(imported.Source) > 0 {
+		merged.Source = imported.Source
+	}
+	if imported.Decimals > 0 {
+		merged.Decimals = imported.Decimals
+	}
+	merged.IsContract = merged.IsContract || imported.IsContract
+	merged.IsErc20 = merged.IsErc20 || imported.IsErc20
+	merged.IsErc721 = merged.IsErc721 || imported.IsErc721
+	if len(imported.Tags) > 0 {
+		merged.Tags = imported.Tags
+	}
+	return merged
+}
+
+// changedFields returns the fields that differ between the two names
+func changedFields(a, b *types.SimpleName) []FieldChange {
+	changed := []FieldChange{}
+	fields := []struct {
+		name     string
+		from, to any
+	}{
+		{"tags", a.Tags, b.Tags},
+		{"name", a.Name, b.Name},
+		{"symbol", a.Symbol, b.Symbol},
+		{"source", a.Source, b.Source},
+		{"decimals", a.Decimals, b.Decimals},
+		{"petname", a.Petname, b.Petname},
+		{"deleted", a.Deleted, b.Deleted},
+		{"isCustom", a.IsCustom, b.IsCustom},
+		{"isContract", a.IsContract, b.IsContract},
+		{"isErc20", a.IsErc20, b.IsErc20},
+		{"isErc721", a.IsErc721, b.IsErc721},
+	}
+	for _, field := range fields {
+		if field.from != field.to {
+			changed = append(changed, FieldChange{
+				Field: field.name,
+				From:  fmt.Sprint(field.from),
+				To:    fmt.Sprint(field.to),
+			})
+		}
+	}
+	return changed
+}
+
+// ImportCustomNames adds (or replaces) the names in the custom names database, writing it once,
+// and journals the changes. The custom names must have been loaded (see LoadNamesMap).
+func ImportCustomNames(chain string, names []types.SimpleName) (err error) {
+	if len(names) == 0 {
+		return errors.New("no names to import")
+	}
+
+	db, err := OpenDatabaseFile(chain, DatabaseCustom, os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	loadedCustomNamesMutex.Lock()
+	defer loadedCustomNamesMutex.Unlock()
+
+	entries := make([]JournalEntry, 0, len(names))
+	for _, name := range names {
+		name := name
+		var previous *types.SimpleName
+		if found, ok := loadedCustomNames[name.Address]; ok {
+			previous = &found
+		}
+		loadedCustomNames[name.Address] = name
+		entries = append(entries, newJournalEntry(JournalImport, previous, &name))
+	}
+	if err = writeCustomNames(db); err != nil {
+		return
+	}
+	return appendJournal(chain, entries)
+}
@@ -0,0 +1,116 @@
+package names
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := ParseMapping("Label=name, Category = TAGS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapping) != 2 || mapping["label"] != "name" || mapping["category"] != "tags" {
+		t.Error("unexpected mapping", mapping)
+	}
+
+	for _, invalid := range []string{"label", "label=nickname", "=name", "a=b=c"} {
+		if _, err := ParseMapping(invalid); err == nil {
+			t.Errorf("expected an error for mapping %q", invalid)
+		}
+	}
+}
+
+func TestReadImportFile(t *testing.T) {
+	dir := t.TempDir()
+	mapping := map[string]string{"label": "name", "category": "tags"}
+
+	csvPath := filepath.Join(dir, "labels.csv")
+	csvContents := "Label,Address,Category,Ignored\n" +
+		"My Wallet,0x1234567890123456789012345678901234567890,10-Mine,x\n" +
+		"\"Quoted, Name\",0x000000000000000000000000000000000000DEAD,99-Burn,y\n"
+	if err := os.WriteFile(csvPath, []byte(csvContents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadImportFile(csvPath, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "My Wallet" || got[0].Tags != "10-Mine" || got[1].Name != "Quoted, Name" {
+		t.Error("unexpected names from csv", got)
+	}
+	if got[1].Address != base.HexToAddress("0x000000000000000000000000000000000000dead") {
+		t.Error("expected the address to be lower cased, got", got[1].Address)
+	}
+
+	jsonPath := filepath.Join(dir, "labels.json")
+	jsonContents := `{ "data": [ { "address": "0x1234567890123456789012345678901234567890", "label": "My Wallet", "decimals": 18, "isErc20": true } ] }`
+	if err = os.WriteFile(jsonPath, []byte(jsonContents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = ReadImportFile(jsonPath, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "My Wallet" || got[0].Decimals != 18 || !got[0].IsErc20 {
+		t.Error("unexpected names from json", got)
+	}
+
+	badPath := filepath.Join(dir, "bad.tsv")
+	if err = os.WriteFile(badPath, []byte("name\taddress\nNobody\tnot-an-address\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ReadImportFile(badPath, nil); err == nil {
+		t.Error("expected an error for a row without a valid address")
+	}
+}
+
+func TestResolveImport(t *testing.T) {
+	addr := base.HexToAddress("0x000000000000000000000000000000000000dead")
+	other := base.HexToAddress("0x1234567890123456789012345678901234567890")
+	existing := map[base.Address]types.SimpleName{
+		addr: {Address: addr, Name: "Burn", Tags: "55-Defi", Source: "EtherScan.io", Petname: "abnormally-able-bee"},
+	}
+	imported := []types.SimpleName{
+		{Address: addr, Name: "Burn Address", Tags: "99-Burn"},
+		{Address: other, Name: "Mine"},
+	}
+
+	changes := ResolveImport(existing, imported, ConflictKeep)
+	if changes[0].Action != ImportKeep || changes[0].Name.Name != "Burn" {
+		t.Error("keep should keep the existing name", changes[0])
+	}
+	if changes[1].Action != ImportAdd || !changes[1].Name.IsCustom || len(changes[1].Name.Petname) == 0 {
+		t.Error("new names should be added as custom names with a petname", changes[1])
+	}
+
+	changes = ResolveImport(existing, imported, ConflictOverwrite)
+	if changes[0].Action != ImportUpdate || changes[0].Name.Tags != "99-Burn" || changes[0].Name.Source != "" {
+		t.Error("overwrite should replace the existing name", changes[0])
+	}
+
+	changes = ResolveImport(existing, imported, ConflictMerge)
+	merged := changes[0].Name
+	if changes[0].Action != ImportUpdate || merged.Name != "Burn Address" || merged.Tags != "99-Burn" || merged.Source != "EtherScan.io" {
+		t.Error("merge should update non-empty fields", merged)
+	}
+	fields := map[string]bool{}
+	for _, change := range changes[0].Changed {
+		fields[change.Field] = true
+	}
+	if len(fields) != 3 || !fields["name"] || !fields["tags"] || !fields["isCustom"] {
+		t.Error("unexpected changed fields", changes[0].Changed)
+	}
+
+	// Importing the same name twice leaves the second import unchanged
+	changes = ResolveImport(existing, append(imported, imported[0]), ConflictMerge)
+	if changes[2].Action != ImportUnchanged {
+		t.Error("expected the repeated import to be unchanged", changes[2])
+	}
+}
@@ -0,0 +1,222 @@
+package names
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// The operations recorded in the journal
+const (
+	JournalCreate   = "create"
+	JournalUpdate   = "update"
+	JournalDelete   = "delete"
+	JournalUndelete = "undelete"
+	JournalRemove   = "remove"
+	JournalImport   = "import"
+	JournalRollback = "rollback"
+)
+
+// JournalEntry records a change to the custom names database. Old is the name before the change
+// (nil if the address had no custom name) and New is the name after the change (nil if the name
+// was removed). Source is the user and machine that made the change.
+type JournalEntry struct {
+	Timestamp base.Timestamp    `json:"timestamp"`
+	Operation string            `json:"operation"`
+	Source    string            `json:"source"`
+	Address   base.Address      `json:"address"`
+	Old       *types.SimpleName `json:"old,omitempty"`
+	New       *types.SimpleName `json:"new,omitempty"`
+}
+
+// Changes returns the fields the entry changed
+func (e *JournalEntry) Changes() []FieldChange {
+	old, new := types.SimpleName{}, types.SimpleName{}
+	if e.Old != nil {
+		old = *e.Old
+	}
+	if e.New != nil {
+		new = *e.New
+	}
+	return changedFields(&old, &new)
+}
+
+func newJournalEntry(operation string, old, new *types.SimpleName) JournalEntry {
+	entry := JournalEntry{
+		Timestamp: base.Timestamp(time.Now().Unix()),
+		Operation: operation,
+		Source:    journalSource(),
+		Old:       old,
+		New:       new,
+	}
+	if new != nil {
+		entry.Address = new.Address
+	} else if old != nil {
+		entry.Address = old.Address
+	}
+	return entry
+}
+
+// journalSource identifies who is making a change
+func journalSource() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		name += "@" + host
+	}
+	return name
+}
+
+// journalChange records a single change made to a custom name
+func journalChange(chain, operation string, old, new *types.SimpleName) error {
+	return appendJournal(chain, []JournalEntry{newJournalEntry(operation, old, new)})
+}
+
+// appendJournal appends the entries to the journal, one JSON object per line
+func appendJournal(chain string, entries []JournalEntry) (err error) {
+	db, err := OpenDatabaseFile(chain, DatabaseJournal, os.O_WRONLY|os.O_APPEND|os.O_CREATE)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	if err = file.Lock(db); err != nil {
+		return
+	}
+	defer file.Unlock(db)
+
+	return writeJournal(db, entries)
+}
+
+func writeJournal(output io.Writer, entries []JournalEntry) error {
+	writer := bufio.NewWriter(output)
+	for _, entry := range entries {
+		if os.Getenv("TEST_MODE") == "true" && testAddresses[entry.Address.Hex()] {
+			// Do not journal test addresses (we don't save them either)
+			continue
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if _, err = writer.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// ReadJournal returns the entries in the journal of the custom names database, oldest first. If
+// there is no journal, it returns no entries.
+func ReadJournal(chain string) ([]JournalEntry, error) {
+	db, err := OpenDatabaseFile(chain, DatabaseJournal, os.O_RDONLY)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []JournalEntry{}, nil
+		}
+		return nil, err
+	}
+	defer db.Close()
+
+	return readJournal(db)
+}
+
+func readJournal(source io.Reader) ([]JournalEntry, error) {
+	entries := []JournalEntry{}
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", DatabaseJournal, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ResolveRollback determines the changes needed to return the custom names (current) to their state
+// at the given time. Only the addresses in the filter are rolled back unless the filter is empty.
+// The changes are listed in the order in which the addresses were first changed after the time.
+func ResolveRollback(current map[base.Address]types.SimpleName, entries []JournalEntry, ts base.Timestamp, filter map[base.Address]bool) []NameChange {
+	// The state of an address at the time is the old value of the first change made after it
+	order := []base.Address{}
+	target := map[base.Address]*types.SimpleName{}
+	for _, entry := range entries {
+		if entry.Timestamp <= ts || (len(filter) > 0 && !filter[entry.Address]) {
+			continue
+		}
+		if _, seen := target[entry.Address]; !seen {
+			order = append(order, entry.Address)
+			target[entry.Address] = entry.Old
+		}
+	}
+
+	changes := make([]NameChange, 0, len(order))
+	for _, addr := range order {
+		want := target[addr]
+		have, exists := current[addr]
+		switch {
+		case want == nil && !exists:
+			continue
+		case want == nil:
+			changes = append(changes, NameChange{Action: RollbackRemove, Name: have, Previous: &have, Changed: changedFields(&have, &types.SimpleName{})})
+		case !exists:
+			changes = append(changes, NameChange{Action: ImportAdd, Name: *want, Changed: changedFields(&types.SimpleName{}, want)})
+		default:
+			changed := changedFields(&have, want)
+			if len(changed) == 0 {
+				changes = append(changes, NameChange{Action: ImportUnchanged, Name: have, Previous: &have})
+			} else {
+				changes = append(changes, NameChange{Action: ImportUpdate, Name: *want, Previous: &have, Changed: changed})
+			}
+		}
+	}
+	return changes
+}
+
+// RollbackCustomNames makes the changes (see ResolveRollback) to the custom names database and
+// journals them, so a rollback may itself be rolled back. The custom names must have been loaded
+// (see LoadNamesMap).
+func RollbackCustomNames(chain string, changes []NameChange) (err error) {
+	db, err := OpenDatabaseFile(chain, DatabaseCustom, os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	loadedCustomNamesMutex.Lock()
+	defer loadedCustomNamesMutex.Unlock()
+
+	entries := []JournalEntry{}
+	for _, change := range changes {
+		name := change.Name
+		switch change.Action {
+		case RollbackRemove:
+			delete(loadedCustomNames, name.Address)
+			entries = append(entries, newJournalEntry(JournalRollback, change.Previous, nil))
+		case ImportAdd, ImportUpdate:
+			loadedCustomNames[name.Address] = name
+			entries = append(entries, newJournalEntry(JournalRollback, change.Previous, &name))
+		}
+	}
+
+	if err = writeCustomNames(db); err != nil {
+		return
+	}
+	return appendJournal(chain, entries)
+}
@@ -0,0 +1,97 @@
+package names
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestJournal(t *testing.T) {
+	t.Setenv("TEST_MODE", "true")
+
+	addr := base.HexToAddress("0x1f9090aae28b8a3dceadf281b0f12828e676c326")
+	first := types.SimpleName{Address: addr, Name: "first"}
+	second := types.SimpleName{Address: addr, Name: "second"}
+	// Test addresses are not journaled
+	testName := types.SimpleName{Address: base.HexToAddress("0x0000000000000000000000000000000000000001")}
+
+	var buffer bytes.Buffer
+	if err := writeJournal(&buffer, []JournalEntry{
+		newJournalEntry(JournalCreate, nil, &first),
+		newJournalEntry(JournalUpdate, &first, &second),
+		newJournalEntry(JournalRemove, &testName, nil),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readJournal(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Operation != JournalCreate || entries[1].Operation != JournalUpdate {
+		t.Fatal("unexpected journal", entries)
+	}
+	if entries[1].Address != addr || entries[1].Old.Name != "first" || entries[1].New.Name != "second" || len(entries[1].Source) == 0 {
+		t.Error("unexpected entry", entries[1])
+	}
+	if changes := entries[1].Changes(); len(changes) != 1 || changes[0].Field != "name" {
+		t.Error("unexpected changes", changes)
+	}
+	if changes := entries[0].Changes(); len(changes) != 1 || changes[0].From != "" || changes[0].To != "first" {
+		t.Error("expected a creation to change the name from empty", changes)
+	}
+
+	if _, err = readJournal(strings.NewReader("{not json}\n")); err == nil {
+		t.Error("expected an error for a corrupt journal")
+	}
+}
+
+func TestResolveRollback(t *testing.T) {
+	a := base.HexToAddress("0x000000000000000000000000000000000000000a")
+	b := base.HexToAddress("0x000000000000000000000000000000000000000b")
+	c := base.HexToAddress("0x000000000000000000000000000000000000000c")
+	aOld := types.SimpleName{Address: a, Name: "a old"}
+	aMid := types.SimpleName{Address: a, Name: "a mid"}
+	aNew := types.SimpleName{Address: a, Name: "a new"}
+	bName := types.SimpleName{Address: b, Name: "b"}
+	cName := types.SimpleName{Address: c, Name: "c"}
+
+	entries := []JournalEntry{
+		{Timestamp: 100, Operation: JournalCreate, Address: a, New: &aOld},
+		{Timestamp: 100, Operation: JournalCreate, Address: c, New: &cName},
+		{Timestamp: 200, Operation: JournalUpdate, Address: a, Old: &aOld, New: &aMid},
+		{Timestamp: 300, Operation: JournalCreate, Address: b, New: &bName},
+		{Timestamp: 300, Operation: JournalRemove, Address: c, Old: &cName},
+		{Timestamp: 400, Operation: JournalUpdate, Address: a, Old: &aMid, New: &aNew},
+	}
+	current := map[base.Address]types.SimpleName{
+		a: aNew,
+		b: bName,
+	}
+
+	changes := ResolveRollback(current, entries, 150, nil)
+	if len(changes) != 3 {
+		t.Fatal("expected three changes, got", changes)
+	}
+	if changes[0].Action != ImportUpdate || changes[0].Name.Name != "a old" {
+		t.Error("expected a to return to its first name", changes[0])
+	}
+	if changes[1].Action != RollbackRemove || changes[1].Name.Address != b {
+		t.Error("expected b to be removed", changes[1])
+	}
+	if changes[2].Action != ImportAdd || changes[2].Name.Name != "c" {
+		t.Error("expected c to be restored", changes[2])
+	}
+
+	changes = ResolveRollback(current, entries, 300, map[base.Address]bool{a: true})
+	if len(changes) != 1 || changes[0].Name.Name != "a mid" {
+		t.Error("expected only a to be rolled back to its middle name", changes)
+	}
+
+	if changes = ResolveRollback(current, entries, 400, nil); len(changes) != 0 {
+		t.Error("expected nothing to roll back", changes)
+	}
+}
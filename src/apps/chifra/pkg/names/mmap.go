@@ -0,0 +1,25 @@
+package names
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the file into memory read-only. The mapping is never unmapped as names returned
+// from it may still be in use.
+func mapFile(path string) ([]byte, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	info, err := fp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, nil
+	}
+	return syscall.Mmap(int(fp.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
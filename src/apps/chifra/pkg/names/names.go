@@ -29,6 +29,7 @@ const (
 	Testing   Parts = 0x8
 	MatchCase Parts = 0x10
 	Expanded  Parts = 0x20
+	Fuzzy     Parts = 0x40
 )
 
 type SortBy int
@@ -42,6 +43,7 @@ const (
 	// SortByDecimals
 	SortByTags
 	// SortByPetname
+	SortByScore
 )
 
 // LoadNamesArray loads the names from the cache and returns an array of names
@@ -66,6 +68,15 @@ func LoadNamesArray(chain string, parts Parts, sortBy SortBy, terms []string) ([
 			return names[i].Name < names[j].Name
 		case SortByTags:
 			return names[i].Tags < names[j].Tags
+		case SortByScore:
+			// Most relevant first, then alphabetically
+			if names[i].Score != names[j].Score {
+				return names[i].Score > names[j].Score
+			}
+			if names[i].Name != names[j].Name {
+				return names[i].Name < names[j].Name
+			}
+			return names[i].Address.Hex() < names[j].Address.Hex()
 		case SortByAddress:
 			fallthrough
 		default:
@@ -82,19 +93,75 @@ func LoadNamesArray(chain string, parts Parts, sortBy SortBy, terms []string) ([
 	return names, nil
 }
 
+// LoadTags returns the distinct tags of the names of the requested parts that match the terms in
+// order. Without terms, the tags of the prefund and regular names come from the names database's
+// tag index rather than from loading and sorting all of the names.
+func LoadTags(chain string, parts Parts, terms []string) ([]string, error) {
+	db, err := OpenNamesDb(chain)
+	if len(terms) != 0 || err != nil {
+		namesArray, err := LoadNamesArray(chain, parts, SortByTags, terms)
+		if err != nil {
+			return nil, err
+		}
+		tags := []string{}
+		for _, name := range namesArray {
+			if len(tags) == 0 || tags[len(tags)-1] != name.Tags {
+				tags = append(tags, name.Tags)
+			}
+		}
+		return tags, nil
+	}
+
+	// Custom names with Individual tag or tags under 30 are private during testing (see LoadNamesArray)
+	isTesting := parts&Testing != 0
+	tagsMap := map[string]bool{}
+	for _, tags := range db.Tags(parts) {
+		if !isTesting || !strings.Contains(tags, "Individual") {
+			tagsMap[tags] = true
+		}
+	}
+	if parts&Custom != 0 {
+		customMap := map[base.Address]types.SimpleName{}
+		if err = loadCustomMap(chain, nil, parts, &customMap); err != nil {
+			return nil, err
+		}
+		for _, name := range customMap {
+			if !isTesting || !(strings.Contains(name.Tags, "Individual") || name.Tags < "3") {
+				tagsMap[name.Tags] = true
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(tagsMap))
+	for tag := range tagsMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
 // LoadNamesMap loads the names from the cache and returns a map of names
 func LoadNamesMap(chain string, parts Parts, terms []string) (map[base.Address]types.SimpleName, error) {
 	namesMap := map[base.Address]types.SimpleName{}
 
-	// Load the prefund names first...
-	if parts&Prefund != 0 {
-		prefundPath := filepath.Join(config.GetPathToChainConfig(chain), "allocs.csv")
-		loadPrefundMap(chain, prefundPath, terms, parts, &namesMap)
-	}
+	// The names database holds both the prefunds and the regular names. If it's not available, we
+	// read the sources directly.
+	if parts&(Prefund|Regular) != 0 {
+		if db, err := OpenNamesDb(chain); err == nil {
+			loadDbMap(db, terms, parts, &namesMap)
+
+		} else {
+			// Load the prefund names first...
+			if parts&Prefund != 0 {
+				prefundPath := filepath.Join(config.GetPathToChainConfig(chain), "allocs.csv")
+				loadPrefundMap(chain, prefundPath, terms, parts, &namesMap)
+			}
 
-	if parts&Regular != 0 {
-		namesPath := filepath.Join(config.GetPathToChainConfig(chain), "names.tab")
-		loadRegularMap(chain, namesPath, terms, parts, &namesMap)
+			if parts&Regular != 0 {
+				namesPath := filepath.Join(config.GetPathToChainConfig(chain), "names.tab")
+				loadRegularMap(chain, namesPath, terms, parts, &namesMap)
+			}
+		}
 	}
 
 	// Load the custom names (note that these may overwrite the prefund and regular names)
@@ -129,21 +196,31 @@ func (gr *NameReader) Read() (types.SimpleName, error) {
 		return types.SimpleName{}, err
 	}
 
+	return nameFromFields(func(field string) string {
+		if index, ok := gr.header[field]; ok && index < len(record) {
+			return record[index]
+		}
+		return ""
+	}), nil
+}
+
+// nameFromFields builds a name from the values of its fields (missing fields are empty)
+func nameFromFields(get func(field string) string) types.SimpleName {
 	return types.SimpleName{
-		Tags:       record[gr.header["tags"]],
-		Address:    base.HexToAddress(strings.ToLower(record[gr.header["address"]])),
-		Name:       record[gr.header["name"]],
-		Decimals:   globals.ToUint64(record[gr.header["decimals"]]),
-		Symbol:     record[gr.header["symbol"]],
-		Source:     record[gr.header["source"]],
-		Petname:    record[gr.header["petname"]],
-		IsCustom:   record[gr.header["iscustom"]] == "true",
-		IsPrefund:  record[gr.header["isprefund"]] == "true",
-		IsContract: record[gr.header["iscontract"]] == "true",
-		IsErc20:    record[gr.header["iserc20"]] == "true",
-		IsErc721:   record[gr.header["iserc721"]] == "true",
-		Deleted:    record[gr.header["deleted"]] == "true",
-	}, nil
+		Tags:       get("tags"),
+		Address:    base.HexToAddress(strings.ToLower(get("address"))),
+		Name:       get("name"),
+		Decimals:   globals.ToUint64(get("decimals")),
+		Symbol:     get("symbol"),
+		Source:     get("source"),
+		Petname:    get("petname"),
+		IsCustom:   get("iscustom") == "true",
+		IsPrefund:  get("isprefund") == "true",
+		IsContract: get("iscontract") == "true",
+		IsErc20:    get("iserc20") == "true",
+		IsErc721:   get("iserc721") == "true",
+		Deleted:    get("deleted") == "true",
+	}
 }
 
 type NameReaderMode int
@@ -154,6 +231,16 @@ const (
 )
 
 func NewNameReader(source io.Reader, mode NameReaderMode) (NameReader, error) {
+	return newNameReader(source, mode, nil, requiredColumns)
+}
+
+// NewMappedNameReader returns a reader for name files from other sources. The mapping renames
+// the file's columns to name fields (see ParseMapping). Only the address column is required.
+func NewMappedNameReader(source io.Reader, mode NameReaderMode, mapping map[string]string) (NameReader, error) {
+	return newNameReader(source, mode, mapping, []string{"address"})
+}
+
+func newNameReader(source io.Reader, mode NameReaderMode, mapping map[string]string, required []string) (NameReader, error) {
 	reader := csv.NewReader(source)
 	reader.Comma = '\t'
 	if mode == NameReaderComma {
@@ -166,13 +253,13 @@ func NewNameReader(source io.Reader, mode NameReaderMode) (NameReader, error) {
 	}
 	header := map[string]int{}
 	for index, columnName := range headerRow {
-		header[columnName] = index
+		header[fieldName(columnName, mapping)] = index
 	}
 
-	for _, required := range requiredColumns {
-		_, ok := header[required]
+	for _, column := range required {
+		_, ok := header[column]
 		if !ok {
-			err = fmt.Errorf(`required column "%s" missing`, required) //, path)
+			err = fmt.Errorf(`required column "%s" missing`, column) //, path)
 			return NameReader{}, err
 		}
 	}
@@ -186,25 +273,35 @@ func NewNameReader(source io.Reader, mode NameReaderMode) (NameReader, error) {
 	return r, nil
 }
 
+// fieldName returns the name field of a column. Column names are not case sensitive.
+func fieldName(column string, mapping map[string]string) string {
+	column = strings.ToLower(strings.TrimSpace(column))
+	if field, ok := mapping[column]; ok {
+		return field
+	}
+	return column
+}
+
 type DatabaseFile string
 
 const (
 	DatabaseRegular DatabaseFile = "names.tab"
 	DatabaseCustom  DatabaseFile = "names_custom.tab"
 	DatabasePrefund DatabaseFile = "allocs.csv"
+	DatabaseJournal DatabaseFile = "names_custom.journal"
 )
 
 func OpenDatabaseFile(chain string, kind DatabaseFile, openFlag int) (*os.File, error) {
 	filePath := filepath.Join(config.GetPathToChainConfig(chain), string(kind))
 	var permissions fs.FileMode = 0666
 
-	if kind == DatabaseCustom && os.Getenv("TEST_MODE") == "true" {
+	if (kind == DatabaseCustom || kind == DatabaseJournal) && os.Getenv("TEST_MODE") == "true" {
 		// Create temp database, just for tests. On Mac, the permissions must be set to 0777
 		if err := os.MkdirAll(path.Join(os.TempDir(), "trueblocks"), 0777); err != nil {
 			return nil, err
 		}
 
-		filePath = path.Join(os.TempDir(), "trueblocks", "names_custom.tab")
+		filePath = path.Join(os.TempDir(), "trueblocks", string(kind))
 		openFlag |= os.O_CREATE
 		// On Mac, the permissions must be set to 0777
 		permissions = 0777
@@ -216,93 +313,3 @@ func OpenDatabaseFile(chain string, kind DatabaseFile, openFlag int) (*os.File,
 		permissions,
 	)
 }
-
-/*
-// NameOnDiscHeader is the header of the names database when stored in the binary backing file
-type NameOnDiscHeader struct {
-	Magic   uint64    // 8 bytes
-	Version uint64    // + 8 bytes = 16 bytes
-	Count   uint64    // + 8 bytes = 24 bytes
-	Padding [428]byte // 452 - 24 = 428 bytes
-}
-
-// NameOnDisc is a record in the names database when stored in the binary backing file
-type NameOnDisc struct {
-	Tags     [30 + 1]byte  `json:"-"` // 31 bytes
-	Address  [42 + 1]byte  `json:"-"` // + 43 bytes = 74 bytes
-	Name     [120 + 1]byte `json:"-"` // + 121 bytes = 195 bytes
-	Symbol   [30 + 1]byte  `json:"-"` // + 31 bytes = 226 bytes
-	Source   [180 + 1]byte `json:"-"` // + 181 bytes = 407 bytes
-	Petname  [40 + 1]byte  `json:"-"` // + 41 bytes = 448 bytes
-	Decimals uint16        `json:"-"` // + 2 bytes = 450 bytes
-	Flags    uint16        `json:"-"` // + 2 bytes = 452 bytes
-}
-
-// Bitflags for the Flags field
-const (
-	IsCustom   uint16 = 0x1
-	IsPrefund  uint16 = 0x2
-	IsContract uint16 = 0x4
-	IsErc20    uint16 = 0x8
-	IsErc721   uint16 = 0x10
-	IsDeleted  uint16 = 0x20
-)
-
-// asString converts the byte array (not zero-terminated) to a string
-func asString(which string, b []byte) string {
-	ret := ""
-	for _, rVal := range string(b) {
-		if rVal == 0 {
-			return ret
-		}
-		ret += string(rVal)
-	}
-	return ret
-}
-
-	// binPath := config.GetPathToCache(chain) + "names/names.bin"
-	// namesPath := filepath.Join(config.GetPathToChainConfig(chain), "names.tab")
-	// customPath := filepath.Join(config.GetPathToChainConfig(chain), "names_custom.tab")
-	// Load the names from the binary file (note that these may overwrite the prefund names)
-	if parts&Regular != 0 {
-		// enabled := false // os.Getenv("FAST") == "true" // TODO: this isn't right
-		// if enabled && file.FileExists(binPath) {
-		// 	file, _ := os.OpenFile(binPath, os.O_RDONLY, 0)
-		// 	defer file.Close()
-
-		// 	header := NameOnDiscHeader{}
-		// 	err := binary.Read(file, binary.LittleEndian, &header)
-		// 	if err != nil {
-		// 		return nil, err
-		// 	}
-
-		// 	for i := uint64(0); i < header.Count; i++ {
-		// 		v := NameOnDisc{}
-		// 		binary.Read(file, binary.LittleEndian, &v)
-		// 		n := Name{
-		// 			Tags:       asString("tags", v.Tags[:]),
-		// 			Address:    asString("address", v.Address[:]),
-		// 			Name:       asString("name", v.Name[:]),
-		// 			Symbol:     asString("symbol", v.Symbol[:]),
-		// 			Decimals:   fmt.Sprintf("%d", v.Decimals),
-		// 			Source:     asString("source", v.Source[:]),
-		// 			Petname:    asString("petname", v.Petname[:]),
-		// 			IsCustom:   v.Flags&IsCustom != 0,
-		// 			IsPrefund:  v.Flags&IsPrefund != 0,
-		// 			IsContract: v.Flags&IsContract != 0,
-		// 			IsErc20:    v.Flags&IsErc20 != 0,
-		// 			IsErc721:   v.Flags&IsErc721 != 0,
-		// 			Deleted:    v.Flags&IsDeleted != 0,
-		// 		}
-		// 		if !n.IsCustom {
-		// 			if doSearch(n, terms, parts) {
-		// 				ret[base.HexToAddress(n.Address)] = n
-		// 			}
-		// 		}
-		// 	}
-		// } else {
-		namesPath := filepath.Join(config.GetPathToChainConfig(chain), "names.tab")
-		loadRegularMap(chain, namesPath, terms, parts, &ret)
-		// }
-	}
-*/
@@ -0,0 +1,541 @@
+package names
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// The names database is a binary, memory-mappable copy of the regular names (names.tab) and the
+// prefunds (allocs.csv). It is rebuilt whenever either source changes. Custom names are not part
+// of the database as they change often and are few. The file is laid out as:
+//
+//	namesDbHeader
+//	namesDbRecord[Count]  sorted by address (a prefund precedes a regular name at the same address)
+//	uint32[Count]         record indexes sorted by lower-cased name
+//	uint32[Count]         record indexes sorted by tags
+//	[]byte                the string table
+const namesDbVersion = 1
+
+// namesDbHeader is the header of the names database
+type namesDbHeader struct {
+	Magic       uint32
+	Version     uint32
+	Count       uint32
+	StringsSize uint32
+	Sources     [2]sourceStamp // names.tab and allocs.csv when the database was built
+}
+
+// sourceStamp identifies a version of a source file. A missing file has a size of -1.
+type sourceStamp struct {
+	Size    int64
+	ModTime int64
+}
+
+// namesDbRecord is a name in the names database. Strings are stored in the string table.
+type namesDbRecord struct {
+	Address  [20]byte
+	Flags    uint16
+	Unused   uint16
+	Decimals uint64
+	Strings  [5]stringRef // tags, name, symbol, source, petname
+}
+
+type stringRef struct {
+	Offset uint32
+	Len    uint32
+}
+
+// Bitflags for the Flags field
+const (
+	flagPrefund uint16 = 1 << iota
+	flagContract
+	flagErc20
+	flagErc721
+	flagDeleted
+	flagAllocs // the name came from allocs.csv rather than names.tab
+)
+
+var (
+	namesDbHeaderSize = binary.Size(namesDbHeader{})
+	namesDbRecordSize = binary.Size(namesDbRecord{})
+)
+
+// NamesDb is an opened names database
+type NamesDb struct {
+	header  namesDbHeader
+	data    []byte
+	records []byte
+	byName  []byte
+	byTags  []byte
+	strs    []byte
+}
+
+var (
+	namesDbs      = map[string]*NamesDb{}
+	namesDbsMutex sync.Mutex
+)
+
+func getPathToNamesDb(chain string) string {
+	return filepath.Join(config.GetPathToCache(chain), "names", "names.bin")
+}
+
+func getNamesDbSources(chain string) [2]string {
+	return [2]string{
+		filepath.Join(config.GetPathToChainConfig(chain), string(DatabaseRegular)),
+		filepath.Join(config.GetPathToChainConfig(chain), string(DatabasePrefund)),
+	}
+}
+
+// OpenNamesDb returns the chain's names database, building it first if it does not exist or if
+// its sources have changed. The database is opened once per process (so the daemon shares one
+// instance) and reopened only when the sources change.
+func OpenNamesDb(chain string) (*NamesDb, error) {
+	namesDbsMutex.Lock()
+	defer namesDbsMutex.Unlock()
+
+	sources := getNamesDbSources(chain)
+	stamps := stampSources(sources)
+	if db := namesDbs[chain]; db != nil && db.header.Sources == stamps {
+		return db, nil
+	}
+
+	binPath := getPathToNamesDb(chain)
+	db, err := openNamesDbFile(binPath)
+	if err != nil || db.header.Sources != stamps {
+		if err = buildNamesDb(chain, binPath, sources, stamps); err != nil {
+			return nil, err
+		}
+		if db, err = openNamesDbFile(binPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// A previously opened instance may still be in use by another goroutine, so we leave it mapped
+	namesDbs[chain] = db
+	return db, nil
+}
+
+func stampSources(sources [2]string) (stamps [2]sourceStamp) {
+	for i, source := range sources {
+		stamps[i] = sourceStamp{Size: -1}
+		if info, err := os.Stat(source); err == nil {
+			stamps[i] = sourceStamp{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+		}
+	}
+	return
+}
+
+// buildNamesDb reads the sources and writes the names database
+func buildNamesDb(chain, binPath string, sources [2]string, stamps [2]sourceStamp) error {
+	all := []types.SimpleName{}
+
+	if stamps[1].Size >= 0 {
+		prefunds, _ := LoadPrefunds(chain, sources[1])
+		for i, prefund := range prefunds {
+			all = append(all, prefundName(i, prefund))
+		}
+	}
+	nPrefunds := len(all)
+
+	if stamps[0].Size >= 0 {
+		regular, err := readNamesFile(sources[0])
+		if err != nil {
+			return err
+		}
+		all = append(all, regular...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and move it into place so readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(binPath), "names.bin.*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = writeNamesDb(tmp, all, nPrefunds, stamps); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), binPath)
+}
+
+// readNamesFile reads every name in a tab separated names file
+func readNamesFile(path string) ([]types.SimpleName, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	reader, err := NewNameReader(fp, NameReaderTab)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	ret := []types.SimpleName{}
+	for {
+		n, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ret = append(ret, n)
+	}
+	return ret, nil
+}
+
+// writeNamesDb writes the names as a names database. The first nPrefunds names are from allocs.csv.
+func writeNamesDb(w io.Writer, all []types.SimpleName, nPrefunds int, stamps [2]sourceStamp) error {
+	fromAllocs := make(map[*types.SimpleName]bool, nPrefunds)
+	sorted := make([]*types.SimpleName, len(all))
+	for i := range all {
+		sorted[i] = &all[i]
+		fromAllocs[&all[i]] = i < nPrefunds
+	}
+	// Stable, so prefunds stay ahead of regular names at the same address
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Address.Bytes(), sorted[j].Address.Bytes()) < 0
+	})
+
+	strs := bytes.Buffer{}
+	offsets := map[string]stringRef{}
+	addString := func(s string) stringRef {
+		if ref, ok := offsets[s]; ok {
+			return ref
+		}
+		ref := stringRef{Offset: uint32(strs.Len()), Len: uint32(len(s))}
+		strs.WriteString(s)
+		offsets[s] = ref
+		return ref
+	}
+
+	records := make([]namesDbRecord, len(sorted))
+	for i, name := range sorted {
+		record := namesDbRecord{Decimals: name.Decimals}
+		copy(record.Address[:], name.Address.Bytes())
+		for _, f := range []struct {
+			set  bool
+			flag uint16
+		}{
+			{name.IsPrefund, flagPrefund},
+			{name.IsContract, flagContract},
+			{name.IsErc20, flagErc20},
+			{name.IsErc721, flagErc721},
+			{name.Deleted, flagDeleted},
+			{fromAllocs[name], flagAllocs},
+		} {
+			if f.set {
+				record.Flags |= f.flag
+			}
+		}
+		for j, s := range []string{name.Tags, name.Name, name.Symbol, name.Source, name.Petname} {
+			record.Strings[j] = addString(s)
+		}
+		records[i] = record
+	}
+
+	byName := sortedIndex(len(sorted), func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	byTags := sortedIndex(len(sorted), func(i, j int) bool {
+		return sorted[i].Tags < sorted[j].Tags
+	})
+
+	header := namesDbHeader{
+		Magic:       file.MagicNumber,
+		Version:     namesDbVersion,
+		Count:       uint32(len(all)),
+		StringsSize: uint32(strs.Len()),
+		Sources:     stamps,
+	}
+	for _, v := range []any{&header, records, byName, byTags, strs.Bytes()} {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedIndex(n int, less func(i, j int) bool) []uint32 {
+	index := make([]uint32, n)
+	for i := range index {
+		index[i] = uint32(i)
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		return less(int(index[i]), int(index[j]))
+	})
+	return index
+}
+
+var errBadNamesDb = errors.New("invalid names database")
+
+// openNamesDbFile maps the names database into memory and checks its layout
+func openNamesDbFile(binPath string) (*NamesDb, error) {
+	data, err := mapFile(binPath)
+	if err != nil {
+		return nil, err
+	}
+	return newNamesDb(data)
+}
+
+func newNamesDb(data []byte) (*NamesDb, error) {
+	db := &NamesDb{data: data}
+	if len(data) < namesDbHeaderSize {
+		return nil, errBadNamesDb
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &db.header); err != nil {
+		return nil, err
+	}
+	if db.header.Magic != file.MagicNumber || db.header.Version != namesDbVersion {
+		return nil, errBadNamesDb
+	}
+
+	cnt := int(db.header.Count)
+	recordsEnd := namesDbHeaderSize + cnt*namesDbRecordSize
+	byNameEnd := recordsEnd + cnt*4
+	byTagsEnd := byNameEnd + cnt*4
+	if len(data) != byTagsEnd+int(db.header.StringsSize) {
+		return nil, errBadNamesDb
+	}
+
+	db.records = data[namesDbHeaderSize:recordsEnd]
+	db.byName = data[recordsEnd:byNameEnd]
+	db.byTags = data[byNameEnd:byTagsEnd]
+	db.strs = data[byTagsEnd:]
+	return db, nil
+}
+
+// Len returns the number of names in the database
+func (db *NamesDb) Len() int {
+	return int(db.header.Count)
+}
+
+// At returns the i'th name in address order
+func (db *NamesDb) At(i int) types.SimpleName {
+	rec := db.records[i*namesDbRecordSize : (i+1)*namesDbRecordSize]
+	flags := binary.LittleEndian.Uint16(rec[20:])
+	return types.SimpleName{
+		Address:    base.BytesToAddress(rec[:20]),
+		Decimals:   binary.LittleEndian.Uint64(rec[24:]),
+		Tags:       db.stringAt(i, 0),
+		Name:       db.stringAt(i, 1),
+		Symbol:     db.stringAt(i, 2),
+		Source:     db.stringAt(i, 3),
+		Petname:    db.stringAt(i, 4),
+		IsPrefund:  flags&flagPrefund != 0,
+		IsContract: flags&flagContract != 0,
+		IsErc20:    flags&flagErc20 != 0,
+		IsErc721:   flags&flagErc721 != 0,
+		Deleted:    flags&flagDeleted != 0,
+	}
+}
+
+// loadDbMap adds the names in the database that are of the requested parts and match the terms.
+// If the terms allow it (see indexedRecords), only the names found through the indexes are searched.
+func loadDbMap(db *NamesDb, terms []string, parts Parts, ret *map[base.Address]types.SimpleName) {
+	if recs, ok := db.indexedRecords(terms, parts); ok {
+		for _, i := range recs {
+			db.addIfMatch(i, terms, parts, ret)
+		}
+		return
+	}
+
+	for i := 0; i < db.Len(); i++ {
+		db.addIfMatch(i, terms, parts, ret)
+	}
+}
+
+// indexedRecords returns (in address order) the records that can match all of the terms if one
+// of the terms is an address or an anchored name prefix (such as ^uniswap). Such a term matches
+// the records at the address or those whose name starts with the prefix (we ignore names, symbols,
+// or tags that spell out an address). Fuzzy and expanded searches look at more fields, so they
+// search every record.
+func (db *NamesDb) indexedRecords(terms []string, parts Parts) ([]int, bool) {
+	if parts&(Fuzzy|Expanded) != 0 {
+		return nil, false
+	}
+	for _, term := range terms {
+		if isAddressTerm(term) {
+			first, last := db.addressRange(base.HexToAddress(term))
+			recs := make([]int, 0, last-first)
+			for i := first; i < last; i++ {
+				recs = append(recs, i)
+			}
+			return recs, true
+		}
+	}
+	for _, term := range terms {
+		prefix := strings.TrimPrefix(term, "^")
+		if prefix != term && len(prefix) > 0 && regexp.QuoteMeta(prefix) == prefix {
+			recs := db.findRange(db.byName, db.lowerNameAt, strings.ToLower(prefix))
+			sort.Ints(recs)
+			return recs, true
+		}
+	}
+	return nil, false
+}
+
+func isAddressTerm(term string) bool {
+	if len(term) != 42 || !strings.HasPrefix(term, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(term[2:])
+	return err == nil
+}
+
+func (db *NamesDb) addIfMatch(i int, terms []string, parts Parts, ret *map[base.Address]types.SimpleName) {
+	if !db.isOfParts(i, parts) {
+		return
+	}
+	n := db.At(i)
+	if doSearch(&n, terms, parts) {
+		(*ret)[n.Address] = n
+	}
+}
+
+// isOfParts returns true if the record is a prefund and prefunds were requested or it's a regular
+// name and regular names were requested
+func (db *NamesDb) isOfParts(i int, parts Parts) bool {
+	flags := binary.LittleEndian.Uint16(db.records[i*namesDbRecordSize+20:])
+	if flags&flagAllocs != 0 {
+		return parts&Prefund != 0
+	}
+	return parts&Regular != 0
+}
+
+func (db *NamesDb) addressAt(i int) []byte {
+	return db.records[i*namesDbRecordSize : i*namesDbRecordSize+20]
+}
+
+func (db *NamesDb) stringAt(i, which int) string {
+	pos := i*namesDbRecordSize + 32 + which*8
+	offset := binary.LittleEndian.Uint32(db.records[pos:])
+	length := binary.LittleEndian.Uint32(db.records[pos+4:])
+	return string(db.strs[offset : offset+length])
+}
+
+func indexAt(index []byte, i int) int {
+	return int(binary.LittleEndian.Uint32(index[i*4:]))
+}
+
+// FindByAddress returns the name at the address. If an address is both a prefund and a regular
+// name, the regular name is returned.
+func (db *NamesDb) FindByAddress(addr base.Address) (types.SimpleName, bool) {
+	first, last := db.addressRange(addr)
+	if first == last {
+		return types.SimpleName{}, false
+	}
+	return db.At(last - 1), true
+}
+
+// FindByNamePrefix returns the names whose name starts with the prefix (ignoring case) in name order
+func (db *NamesDb) FindByNamePrefix(prefix string) []types.SimpleName {
+	return db.namesAt(db.findRange(db.byName, db.lowerNameAt, strings.ToLower(prefix)))
+}
+
+// FindByTag returns the names whose tags start with the given tag (for example, 30-Contracts) in
+// tag order
+func (db *NamesDb) FindByTag(tag string) []types.SimpleName {
+	return db.namesAt(db.findRange(db.byTags, db.tagsAt, tag))
+}
+
+// Tags returns the distinct tags of the names of the requested parts in order
+func (db *NamesDb) Tags(parts Parts) []string {
+	ret := []string{}
+	for i := 0; i < db.Len(); i++ {
+		rec := indexAt(db.byTags, i)
+		if !db.isOfParts(rec, parts) {
+			continue
+		}
+		if tags := db.tagsAt(rec); len(ret) == 0 || ret[len(ret)-1] != tags {
+			ret = append(ret, tags)
+		}
+	}
+	return ret
+}
+
+// addressRange returns the records at the address as [first, last)
+func (db *NamesDb) addressRange(addr base.Address) (int, int) {
+	want := addr.Bytes()
+	first := sort.Search(db.Len(), func(i int) bool {
+		return bytes.Compare(db.addressAt(i), want) >= 0
+	})
+	last := first
+	for last < db.Len() && bytes.Equal(db.addressAt(last), want) {
+		last++
+	}
+	return first, last
+}
+
+// findRange returns the records in the index whose key starts with the prefix in index order
+func (db *NamesDb) findRange(index []byte, key func(int) string, prefix string) []int {
+	first := sort.Search(db.Len(), func(i int) bool {
+		return key(indexAt(index, i)) >= prefix
+	})
+	ret := []int{}
+	for i := first; i < db.Len(); i++ {
+		rec := indexAt(index, i)
+		if !strings.HasPrefix(key(rec), prefix) {
+			break
+		}
+		ret = append(ret, rec)
+	}
+	return ret
+}
+
+func (db *NamesDb) namesAt(recs []int) []types.SimpleName {
+	ret := make([]types.SimpleName, 0, len(recs))
+	for _, rec := range recs {
+		ret = append(ret, db.At(rec))
+	}
+	return ret
+}
+
+func (db *NamesDb) lowerNameAt(i int) string {
+	return strings.ToLower(db.stringAt(i, 1))
+}
+
+func (db *NamesDb) tagsAt(i int) string {
+	return db.stringAt(i, 0)
+}
+
+// FindName returns the name of the address from the custom names and the names database (custom
+// names take precedence). It is meant for lookups of single addresses, such as when articulating
+// or exporting, where loading all of the names would be wasteful.
+func FindName(chain string, addr base.Address) (types.SimpleName, bool) {
+	if !customNamesRead {
+		_ = loadCustomMap(chain, nil, Custom, &map[base.Address]types.SimpleName{})
+	}
+	if name := ReadCustomName(addr); name != nil {
+		return *name, true
+	}
+	db, err := OpenNamesDb(chain)
+	if err != nil {
+		return types.SimpleName{}, false
+	}
+	return db.FindByAddress(addr)
+}
@@ -0,0 +1,126 @@
+package names
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func loadTestNamesDb(t *testing.T) *NamesDb {
+	r, err := NewNameReader(strings.NewReader(inputValid), NameReaderTab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := []types.SimpleName{prefundName(0, Allocation{Address: base.HexToAddress("0x000000000000000000000000000000000000dead")})}
+	for {
+		name, err := r.Read()
+		if err != nil {
+			break
+		}
+		all = append(all, name)
+	}
+
+	buf := bytes.Buffer{}
+	if err := writeNamesDb(&buf, all, 1, [2]sourceStamp{}); err != nil {
+		t.Fatal(err)
+	}
+	db, err := newNamesDb(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestNamesDbLookups(t *testing.T) {
+	db := loadTestNamesDb(t)
+	if db.Len() != 5 {
+		t.Fatal("expected five names, got", db.Len())
+	}
+
+	name, ok := db.FindByAddress(base.HexToAddress("0x0000000000001b84b1cb32787b0d64758d019317"))
+	if !ok || name.Name != "HomeWork 🏠🛠️" || name.Decimals != 18 || !name.IsErc721 || name.IsPrefund {
+		t.Error("unexpected name", name)
+	}
+
+	// The regular name wins over the prefund at the same address
+	if name, ok = db.FindByAddress(base.HexToAddress("0x000000000000000000000000000000000000dead")); !ok || name.Name != "ENS: Burn Address" {
+		t.Error("expected the regular name, got", name)
+	}
+
+	if _, ok = db.FindByAddress(base.HexToAddress("0x1")); ok {
+		t.Error("found a name that does not exist")
+	}
+
+	if found := db.FindByNamePrefix("chi "); len(found) != 1 || found[0].Symbol != "CHI" {
+		t.Error("unexpected names for prefix", found)
+	}
+
+	if found := db.FindByTag("50-Tokens"); len(found) != 2 || found[0].Tags != "50-Tokens:ERC20" {
+		t.Error("unexpected names for tag", found)
+	}
+}
+
+func TestNamesDbParts(t *testing.T) {
+	db := loadTestNamesDb(t)
+
+	prefunds := map[base.Address]types.SimpleName{}
+	loadDbMap(db, nil, Prefund, &prefunds)
+	if len(prefunds) != 1 {
+		t.Error("expected only the prefund, got", prefunds)
+	}
+
+	regular := map[base.Address]types.SimpleName{}
+	loadDbMap(db, []string{"dex"}, Regular, &regular)
+	if len(regular) != 1 {
+		t.Error("expected only dex.blue, got", regular)
+	}
+}
+
+func TestNamesDbIndexedSearch(t *testing.T) {
+	db := loadTestNamesDb(t)
+
+	both := map[base.Address]types.SimpleName{}
+	loadDbMap(db, []string{"0x000000000000000000000000000000000000dead"}, Regular|Prefund, &both)
+	if recs, ok := db.indexedRecords([]string{"0x000000000000000000000000000000000000dead"}, Regular); !ok || len(recs) != 2 {
+		t.Error("expected the address's two records", recs)
+	}
+	if name := both[base.HexToAddress("0xdead")]; len(both) != 1 || name.Name != "ENS: Burn Address" {
+		t.Error("expected the regular name to win over the prefund", both)
+	}
+
+	prefix := map[base.Address]types.SimpleName{}
+	loadDbMap(db, []string{"^CHI", "gas"}, Regular, &prefix)
+	if recs, ok := db.indexedRecords([]string{"^CHI"}, Regular); !ok || len(recs) != 1 {
+		t.Error("expected one record for the prefix", recs)
+	}
+	if len(prefix) != 1 {
+		t.Error("expected only Chi Gastoken, got", prefix)
+	}
+
+	for _, terms := range [][]string{{"dex"}, {"^dex.blue"}, {}} {
+		if _, ok := db.indexedRecords(terms, Regular); ok {
+			t.Error("did not expect the indexes to be used for", terms)
+		}
+	}
+	if _, ok := db.indexedRecords([]string{"^chi"}, Regular|Fuzzy); ok {
+		t.Error("did not expect the indexes to be used for a fuzzy search")
+	}
+
+	tags := db.Tags(Regular)
+	if strings.Join(tags, ",") != "30-Contracts,50-Tokens:ERC20,50-Tokens:ERC721,55-Defi" {
+		t.Error("unexpected tags", tags)
+	}
+}
+
+func TestNamesDbRejectsInvalid(t *testing.T) {
+	if _, err := newNamesDb([]byte("not a names database at all, really not one")); err == nil {
+		t.Error("expected an error for a bad file")
+	}
+	if _, err := openNamesDbFile(filepath.Join(t.TempDir(), "missing.bin")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -15,20 +15,25 @@ import (
 func loadPrefundMap(chain string, thePath string, terms []string, parts Parts, ret *map[base.Address]types.SimpleName) {
 	prefunds, _ := LoadPrefunds(chain, thePath)
 	for i, prefund := range prefunds {
-		n := types.SimpleName{
-			Tags:      "80-Prefund",
-			Address:   prefund.Address,
-			Name:      "Prefund_" + fmt.Sprintf("%04d", i),
-			Source:    "Genesis",
-			Petname:   AddrToPetname(prefund.Address.Hex(), "-"),
-			IsPrefund: true,
-		}
+		n := prefundName(i, prefund)
 		if doSearch(&n, terms, parts) {
 			(*ret)[n.Address] = n
 		}
 	}
 }
 
+// prefundName returns the name of the i'th prefund
+func prefundName(i int, prefund Allocation) types.SimpleName {
+	return types.SimpleName{
+		Tags:      "80-Prefund",
+		Address:   prefund.Address,
+		Name:      "Prefund_" + fmt.Sprintf("%04d", i),
+		Source:    "Genesis",
+		Petname:   AddrToPetname(prefund.Address.Hex(), "-"),
+		IsPrefund: true,
+	}
+}
+
 // Allocation is a single allocation in the genesis file
 type Allocation struct {
 	Address base.Address `json:"address" csv:"address"`
@@ -2,15 +2,46 @@ package names
 
 import (
 	"regexp"
+	"sync"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 )
 
+// maxCompiledTerms bounds compiledTerms, which would otherwise grow with every search a long
+// running process (such as the API server) is asked to do
+const maxCompiledTerms = 256
+
+// compiledTerms caches the compiled search terms so each is compiled once rather than once per name
+var (
+	compiledTerms      = map[string]*regexp.Regexp{}
+	compiledTermsMutex sync.Mutex
+)
+
+func compileTerm(expr string) *regexp.Regexp {
+	compiledTermsMutex.Lock()
+	defer compiledTermsMutex.Unlock()
+
+	if re, ok := compiledTerms[expr]; ok {
+		return re
+	}
+	if len(compiledTerms) >= maxCompiledTerms {
+		compiledTerms = map[string]*regexp.Regexp{}
+	}
+	re := regexp.MustCompile(expr)
+	compiledTerms[expr] = re
+	return re
+}
+
 func doSearch(name *types.SimpleName, terms []string, parts Parts) bool {
 	if len(terms) == 0 {
 		return true
 	}
 
+	if parts&Fuzzy != 0 {
+		name.Score = fuzzyScore(name, terms, parts)
+		return name.Score > 0
+	}
+
 	cnt := 0
 	searchStr := name.Name + "\t" + name.Symbol + "\t" + name.Address.Hex() + "\t" + name.Tags
 	if parts&Expanded != 0 {
@@ -22,7 +53,7 @@ func doSearch(name *types.SimpleName, terms []string, parts Parts) bool {
 		verb = "(?)"
 	}
 	for _, term := range terms {
-		if regexp.MustCompile(verb + term).MatchString(searchStr) {
+		if compileTerm(verb + term).MatchString(searchStr) {
 			cnt++
 		}
 	}
@@ -2,6 +2,7 @@ package pinning
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,6 +20,9 @@ type FetchResult struct {
 
 // FetchFromGateway downloads a chunk from an IPFS gateway using HTTP
 func FetchFromGateway(ctx context.Context, gateway, hash string) (*FetchResult, error) {
+	if len(hash) == 0 {
+		return nil, errors.New("cannot fetch an empty hash")
+	}
 	url, _ := url.Parse(gateway)
 	url.Path = filepath.Join(url.Path, hash)
 	request, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
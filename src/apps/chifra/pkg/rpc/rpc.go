@@ -6,8 +6,10 @@ import (
 	"io"
 	"net/http"
 	"sync/atomic"
+	"time"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/metrics"
 )
 
 // Params are used during calls to the RPC.
@@ -21,8 +23,23 @@ type Payload struct {
 
 var rpcCounter uint32
 
+var (
+	rpcRequests = metrics.NewCounterVec("rpc_requests", "Number of requests sent to the RPC by method", "method")
+	rpcErrors   = metrics.NewCounterVec("rpc_errors", "Number of requests to the RPC that failed by method", "method")
+	rpcSeconds  = metrics.NewSummary("rpc_request_seconds", "Time spent waiting for the RPC to respond")
+)
+
 // FromRpc Returns all traces for a given block.
-func FromRpc(rpcProvider string, payload *Payload, ret interface{}) error {
+func FromRpc(rpcProvider string, payload *Payload, ret interface{}) (err error) {
+	start := time.Now()
+	rpcRequests.Inc(payload.Method)
+	defer func() {
+		rpcSeconds.ObserveSince(start)
+		if err != nil {
+			rpcErrors.Inc(payload.Method)
+		}
+	}()
+
 	type rpcPayload struct {
 		Jsonrpc string `json:"jsonrpc"`
 		Method  string `json:"method"`
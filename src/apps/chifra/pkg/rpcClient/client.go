@@ -16,6 +16,8 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpc"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
@@ -265,13 +267,53 @@ func DecodeHex(hex string) []byte {
 	return hexutil.MustDecode(hex)
 }
 
+// GetCodeAt returns the code deployed at the address at the given block (or, if bn is
+// utils.NOPOS, the latest block)
 func GetCodeAt(chain, addr string, bn uint64) ([]byte, error) {
 	// return IsValidAddress(addr)
 	provider := config.GetRpcProvider(chain)
 	ec := GetClient(provider)
 	address := common.HexToAddress(addr)
-	// TODO: we don't use block number, but we should - we need to convert it
-	return ec.CodeAt(context.Background(), address, nil) // nil is latest block
+	return ec.CodeAt(context.Background(), address, blockArg(bn))
+}
+
+// GetBalanceAt returns the balance in wei of the address at the given block (or, if bn is
+// utils.NOPOS, the latest block)
+func GetBalanceAt(chain, addr string, bn uint64) (*big.Int, error) {
+	provider := config.GetRpcProvider(chain)
+	ec := GetClient(provider)
+	address := common.HexToAddress(addr)
+	return ec.BalanceAt(context.Background(), address, blockArg(bn))
+}
+
+// GetStorageAt returns the value stored in the given slot of the address's storage at the given
+// block (or, if bn is utils.NOPOS, the latest block)
+func GetStorageAt(chain, addr string, slot common.Hash, bn uint64) ([]byte, error) {
+	provider := config.GetRpcProvider(chain)
+	ec := GetClient(provider)
+	address := common.HexToAddress(addr)
+	return ec.StorageAt(context.Background(), address, slot, blockArg(bn))
+}
+
+// CallContractAt calls the address with the given data at the given block (or, if bn is
+// utils.NOPOS, the latest block) returning the call's output
+func CallContractAt(chain, addr string, data []byte, bn uint64) ([]byte, error) {
+	provider := config.GetRpcProvider(chain)
+	ec := GetClient(provider)
+	address := common.HexToAddress(addr)
+	msg := ethereum.CallMsg{
+		To:   &address,
+		Data: data,
+	}
+	return ec.CallContract(context.Background(), msg, blockArg(bn))
+}
+
+// blockArg converts a block number to the argument expected by the client (nil means latest)
+func blockArg(bn uint64) *big.Int {
+	if bn == utils.NOPOS {
+		return nil
+	}
+	return new(big.Int).SetUint64(bn)
 }
 
 // Id_2_TxHash takes a valid identifier (txHash/blockHash, blockHash.txId, blockNumber.txId)
@@ -0,0 +1,165 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package ens
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+)
+
+// The two directions in which we cache resolutions
+const (
+	forwardCache = iota // name to address
+	reverseCache        // address to name
+)
+
+// cacheEntry is a cached resolution. An empty Value records that the name (or address) did not
+// resolve, so we don't ask the node again until the entry expires.
+type cacheEntry struct {
+	Value   string `json:"value"`
+	Expires int64  `json:"expires"`
+}
+
+// ensCache holds the ENS resolutions of a chain. It is kept in memory (so the daemon answers
+// repeated requests without hitting the node) and on disc (so separate invocations share it).
+// New resolutions only mark the cache dirty; Flush writes it once the command (or, in the daemon,
+// the request) is finished.
+type ensCache struct {
+	mutex   sync.Mutex
+	path    string
+	ttl     int64
+	dirty   bool
+	Forward map[string]cacheEntry `json:"forward"`
+	Reverse map[string]cacheEntry `json:"reverse"`
+}
+
+var cacheMutex sync.Mutex
+var perChainCache = map[string]*ensCache{}
+
+// getCache returns the ENS cache for the chain, reading it from disc the first time
+func getCache(chain string) *ensCache {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	if cache := perChainCache[chain]; cache != nil {
+		return cache
+	}
+
+	cache := &ensCache{
+		path:    filepath.Join(config.GetPathToCache(chain), "ens", "ens.json"),
+		ttl:     int64(config.GetEnsCacheTtl()),
+		Forward: map[string]cacheEntry{},
+		Reverse: map[string]cacheEntry{},
+	}
+	if contents, err := os.ReadFile(cache.path); err == nil {
+		if err = json.Unmarshal(contents, cache); err != nil {
+			logger.Warn("Ignoring invalid ENS cache", cache.path, err)
+		}
+		if cache.Forward == nil {
+			cache.Forward = map[string]cacheEntry{}
+		}
+		if cache.Reverse == nil {
+			cache.Reverse = map[string]cacheEntry{}
+		}
+	}
+	perChainCache[chain] = cache
+	return cache
+}
+
+func (c *ensCache) entries(which int) map[string]cacheEntry {
+	if which == forwardCache {
+		return c.Forward
+	}
+	return c.Reverse
+}
+
+// get returns the cached value of the key if there is one and it has not expired
+func (c *ensCache) get(which int, key string) (string, bool) {
+	if c.ttl == 0 {
+		return "", false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries(which)[key]
+	if !ok || entry.Expires <= time.Now().Unix() {
+		return "", false
+	}
+	return entry.Value, true
+}
+
+// set caches the value of the key. The cache is written to disc by Flush.
+func (c *ensCache) set(which int, key, value string) {
+	if c.ttl == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries(which)[key] = cacheEntry{Value: value, Expires: time.Now().Unix() + c.ttl}
+	c.dirty = true
+}
+
+// Flush writes the ENS caches that have changed since they were read (or last written) to disc
+func Flush() {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	for _, cache := range perChainCache {
+		cache.flush()
+	}
+}
+
+// flush drops the expired entries and, if the cache has changed, writes it to disc
+func (c *ensCache) flush() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.dirty {
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, entries := range []map[string]cacheEntry{c.Forward, c.Reverse} {
+		for k, entry := range entries {
+			if entry.Expires <= now {
+				delete(entries, k)
+			}
+		}
+	}
+
+	if err := c.write(); err != nil {
+		logger.Warn("Could not write the ENS cache", c.path, err)
+		return
+	}
+	c.dirty = false
+}
+
+// write writes the cache to a temporary file and moves it into place so readers never see a
+// partial file
+func (c *ensCache) write() error {
+	contents, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := c.path + ".tmp"
+	if err = os.WriteFile(tmpPath, contents, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, c.path)
+}
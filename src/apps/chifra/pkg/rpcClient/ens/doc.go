@@ -1,2 +1,3 @@
-// Package ens interacts with the ENS smart contract on mainnet to resolve ENS names
+// Package ens resolves ENS names to addresses and addresses to their primary ENS names using the
+// chain's ENS registry (or mainnet's). Resolutions are cached in memory and on disc.
 package ens
@@ -5,13 +5,21 @@
 package ens
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/ethereum/go-ethereum/common"
 	ensGo "github.com/wealdtech/go-ens/v3"
 )
 
+// defaultRegistry is the address of the ENS registry on mainnet (and on its testnets)
+const defaultRegistry = "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"
+
 func lowerIfHex(addr string) string {
 	if !strings.HasPrefix(addr, "0x") {
 		return addr
@@ -19,44 +27,195 @@ func lowerIfHex(addr string) string {
 	return strings.ToLower(addr)
 }
 
-// ConvertEns converts an array of strings, if they contains .eth, into addresses. Note, we take
-// chain parameter, but ignore it choosing to look at mainnet ENS only
+// ConvertEns converts an array of strings, if they are ENS names, into addresses. Names are
+// resolved with the chain's ENS registry or, if the chain has none, on mainnet.
 func ConvertEns(chain string, addrsIn []string) ([]string, bool) {
-	provider := config.GetRpcProvider("mainnet")
-	ec := rpcClient.GetClient(provider)
-	defer ec.Close()
-
 	found := false
 	var out []string
-	for i := 0; i < len(addrsIn); i++ {
-		term := addrsIn[i]
-		if strings.Contains(term, ".eth") {
-			val, err := ensGo.Resolve(ec, term)
-			if err == nil && len(val) > 0 {
-				term = val.Hex()
-				found = true
-			}
-		}
-		out = append(out, lowerIfHex(term))
+	for _, term := range addrsIn {
+		term, ok := ConvertOneEns(chain, term)
+		found = found || ok
+		out = append(out, term)
 	}
 	return out, found
 }
 
-// ConvertOneEns converts a single string, if it contains .eth, into an address. Note, we take
-// chain parameter, but ignore it choosing to look at mainnet ENS only
+// ConvertOneEns converts a single string, if it is an ENS name, into an address
 func ConvertOneEns(chain string, in string) (string, bool) {
-	if !strings.Contains(in, ".eth") {
+	if !IsEnsName(in) {
 		return lowerIfHex(in), false
 	}
 
-	provider := config.GetRpcProvider("mainnet")
-	ec := rpcClient.GetClient(provider)
-	defer ec.Close()
-
-	val, err := ensGo.Resolve(ec, in)
-	if err == nil && len(val) > 0 {
-		return lowerIfHex(val.Hex()), true
+	if addr, ok := Resolve(chain, in); ok {
+		return addr.Hex(), true
 	}
 
 	return lowerIfHex(in), false
 }
+
+// ensTlds are the top-level domains we treat as ENS names: .eth, .box, and the DNS domains most
+// often imported into ENS. Other dotted terms (such as usd.c or names.tab) are left alone.
+var ensTlds = map[string]bool{
+	"eth":  true,
+	"box":  true,
+	"xyz":  true,
+	"art":  true,
+	"club": true,
+	"kred": true,
+	"luxe": true,
+	"com":  true,
+	"org":  true,
+	"io":   true,
+	"app":  true,
+}
+
+// IsEnsName returns true if the string may be an ENS name, that is, a dotted name ending in one
+// of the ensTlds that does not name a file that exists
+func IsEnsName(term string) bool {
+	if strings.HasPrefix(term, "0x") || !strings.Contains(term, ".") || strings.ContainsAny(term, " \t/\\:") {
+		return false
+	}
+
+	labels := strings.Split(term, ".")
+	for _, label := range labels {
+		if len(label) == 0 {
+			return false
+		}
+	}
+	if !ensTlds[strings.ToLower(labels[len(labels)-1])] {
+		return false
+	}
+
+	if _, err := ensGo.NormaliseDomain(term); err != nil {
+		return false
+	}
+	return !file.FileExists(term)
+}
+
+// Resolve returns the address to which the ENS name resolves
+func Resolve(chain string, name string) (base.Address, bool) {
+	name, err := ensGo.NormaliseDomain(name)
+	if err != nil {
+		return base.Address{}, false
+	}
+
+	ensChain := getEnsChain(chain)
+	if value, ok := getCache(ensChain).get(forwardCache, name); ok {
+		return base.HexToAddress(value), len(value) > 0
+	}
+
+	addr, err := resolveName(ensChain, name)
+	if err != nil {
+		// We don't cache errors from the node, they may be transient
+		return base.Address{}, false
+	}
+
+	value := ""
+	if !addr.IsZero() {
+		value = addr.Hex()
+	}
+	getCache(ensChain).set(forwardCache, name, value)
+	return addr, len(value) > 0
+}
+
+// ReverseResolve returns the primary ENS name of an address. The name must resolve back to the
+// address to be returned.
+func ReverseResolve(chain string, addr base.Address) (string, bool) {
+	ensChain := getEnsChain(chain)
+	if value, ok := getCache(ensChain).get(reverseCache, addr.Hex()); ok {
+		return value, len(value) > 0
+	}
+
+	name, err := reverseResolveAddress(ensChain, addr)
+	if err != nil {
+		return "", false
+	}
+
+	if len(name) > 0 {
+		if forward, ok := Resolve(chain, name); !ok || forward != addr {
+			name = ""
+		}
+	}
+	getCache(ensChain).set(reverseCache, addr.Hex(), name)
+	return name, len(name) > 0
+}
+
+// getEnsChain returns the chain on which names for the given chain are resolved
+func getEnsChain(chain string) string {
+	if len(config.GetEnsRegistry(chain)) > 0 {
+		return chain
+	}
+	return "mainnet"
+}
+
+var registryMutex sync.Mutex
+var perChainRegistry = map[string]*ensGo.Registry{}
+
+// getRegistry returns the ENS registry of the chain (which must be an ENS chain, see getEnsChain)
+func getRegistry(chain string) (*ensGo.Registry, error) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if registry := perChainRegistry[chain]; registry != nil {
+		return registry, nil
+	}
+
+	address := config.GetEnsRegistry(chain)
+	if len(address) == 0 {
+		address = defaultRegistry
+	}
+
+	// The client is shared by all callers, so we do not close it
+	ec := rpcClient.GetClient(config.GetRpcProvider(chain))
+	registry, err := ensGo.NewRegistryAt(ec, common.HexToAddress(address))
+	if err != nil {
+		return nil, err
+	}
+	perChainRegistry[chain] = registry
+	return registry, nil
+}
+
+// resolveName returns the address of the name or a zero address if the name does not resolve
+func resolveName(chain string, name string) (base.Address, error) {
+	registry, err := getRegistry(chain)
+	if err != nil {
+		return base.Address{}, err
+	}
+
+	resolverAddr, err := registry.ResolverAddress(name)
+	if err != nil || resolverAddr == ensGo.UnknownAddress {
+		return base.Address{}, err
+	}
+
+	ec := rpcClient.GetClient(config.GetRpcProvider(chain))
+	resolver, err := ensGo.NewResolverAt(ec, name, resolverAddr)
+	if err != nil {
+		return base.Address{}, err
+	}
+
+	addr, err := resolver.Address()
+	if err != nil {
+		return base.Address{}, err
+	}
+	return base.Address{Address: addr}, nil
+}
+
+// reverseResolveAddress returns the name set in the address's reverse record, if any
+func reverseResolveAddress(chain string, addr base.Address) (string, error) {
+	registry, err := getRegistry(chain)
+	if err != nil {
+		return "", err
+	}
+
+	resolverAddr, err := registry.ResolverAddress(fmt.Sprintf("%x.addr.reverse", addr.Bytes()))
+	if err != nil || resolverAddr == ensGo.UnknownAddress {
+		return "", err
+	}
+
+	ec := rpcClient.GetClient(config.GetRpcProvider(chain))
+	resolver, err := ensGo.NewReverseResolverAt(ec, resolverAddr)
+	if err != nil {
+		return "", err
+	}
+	return resolver.Name(addr.Address)
+}
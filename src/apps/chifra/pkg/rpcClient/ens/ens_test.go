@@ -0,0 +1,80 @@
+package ens
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsEnsName(t *testing.T) {
+	tests := map[string]bool{
+		"vitalik.eth":     true,
+		"sub.vitalik.eth": true,
+		"nick.xyz":        true,
+		"trueblocks.box":  true,
+		"0x1234.eth":      false,
+		"vitalik":         false,
+		"vitalik.":        false,
+		".eth":            false,
+		"a..eth":          false,
+		"12-1000.5":       false,
+		"./names.tab":     false,
+		"name with.space": false,
+		"usd.c":           false,
+		"names.tab":       false,
+	}
+	for term, expected := range tests {
+		if got := IsEnsName(term); got != expected {
+			t.Errorf("IsEnsName(%q) = %t, expected %t", term, got, expected)
+		}
+	}
+}
+
+func TestEnsCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ens", "ens.json")
+	cache := &ensCache{
+		path:    path,
+		ttl:     100,
+		Forward: map[string]cacheEntry{},
+		Reverse: map[string]cacheEntry{},
+	}
+
+	cache.set(forwardCache, "vitalik.eth", "0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	cache.set(forwardCache, "unregistered.eth", "")
+	cache.Reverse["0x1"] = cacheEntry{Value: "expired.eth", Expires: 1}
+
+	if value, ok := cache.get(forwardCache, "vitalik.eth"); !ok || value != "0xd8da6bf26964af9d7eed9e03e53415d37aa96045" {
+		t.Error("expected a cached resolution, got", value, ok)
+	}
+	if value, ok := cache.get(forwardCache, "unregistered.eth"); !ok || value != "" {
+		t.Error("expected a cached failure to resolve, got", value, ok)
+	}
+	if _, ok := cache.get(reverseCache, "0x1"); ok {
+		t.Error("expected an expired entry to be ignored")
+	}
+	if _, ok := cache.get(reverseCache, "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"); ok {
+		t.Error("expected the reverse cache to be separate from the forward cache")
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		t.Error("expected the cache to be written only when flushed")
+	}
+	cache.flush()
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onDisc := ensCache{}
+	if err = json.Unmarshal(contents, &onDisc); err != nil {
+		t.Fatal(err)
+	}
+	if len(onDisc.Forward) != 2 || len(onDisc.Reverse) != 0 {
+		t.Error("expected two forward entries and no expired reverse entries on disc, got", onDisc.Forward, onDisc.Reverse)
+	}
+
+	cache.ttl = 0
+	if _, ok := cache.get(forwardCache, "vitalik.eth"); ok {
+		t.Error("expected a zero ttl to disable the cache")
+	}
+}
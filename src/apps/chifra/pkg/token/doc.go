@@ -0,0 +1,3 @@
+// Package token learns what it can about a token (its name, symbol, decimals, and the token
+// standards it implements) by querying the token contract on chain
+package token
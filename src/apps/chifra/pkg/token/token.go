@@ -0,0 +1,139 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package token
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Selectors of the functions we query and the interface ids we ask about
+var (
+	nameSelector              = hexutil.MustDecode("0x06fdde03") // name()
+	symbolSelector            = hexutil.MustDecode("0x95d89b41") // symbol()
+	decimalsSelector          = hexutil.MustDecode("0x313ce567") // decimals()
+	totalSupplySelector       = hexutil.MustDecode("0x18160ddd") // totalSupply()
+	balanceOfSelector         = hexutil.MustDecode("0x70a08231") // balanceOf(address)
+	supportsInterfaceSelector = hexutil.MustDecode("0x01ffc9a7") // supportsInterface(bytes4)
+	erc721InterfaceId         = hexutil.MustDecode("0x80ac58cd")
+	erc1155InterfaceId        = hexutil.MustDecode("0xd9b67a26")
+)
+
+// Token is what the token contract told us about itself. The Has fields are false if the
+// contract did not answer the corresponding call.
+type Token struct {
+	Name        string
+	Symbol      string
+	Decimals    uint64
+	HasName     bool
+	HasSymbol   bool
+	HasDecimals bool
+	IsErc20     bool
+	IsErc721    bool
+	IsErc1155   bool
+}
+
+// GetToken queries the contract at the address at the given block (or, if bn is utils.NOPOS,
+// the latest block). A contract that answers decimals and either totalSupply or balanceOf is an
+// ERC-20 token unless it claims (through ERC-165) to be an ERC-721 or ERC-1155 token. Answering
+// name or symbol alone is not enough -- many contracts that are not tokens have a name. The caller
+// should make sure the address is a contract.
+func GetToken(chain string, addr base.Address, bn uint64) *Token {
+	t := Token{}
+	t.Name, t.HasName = callString(chain, addr, nameSelector, bn)
+	t.Symbol, t.HasSymbol = callString(chain, addr, symbolSelector, bn)
+	t.Decimals, t.HasDecimals = callUint(chain, addr, decimalsSelector, bn)
+	t.IsErc721 = SupportsInterface(chain, addr, erc721InterfaceId, bn)
+	t.IsErc1155 = !t.IsErc721 && SupportsInterface(chain, addr, erc1155InterfaceId, bn)
+	t.IsErc20 = !t.IsErc721 && !t.IsErc1155 && t.HasDecimals && hasErc20Balances(chain, addr, bn)
+	return &t
+}
+
+// hasErc20Balances returns true if the contract answers either totalSupply() or balanceOf(address)
+func hasErc20Balances(chain string, addr base.Address, bn uint64) bool {
+	if _, ok := callUint256(chain, addr, totalSupplySelector, bn); ok {
+		return true
+	}
+	data := make([]byte, 4+32) // balanceOf the zero address
+	copy(data, balanceOfSelector)
+	_, ok := callUint256(chain, addr, data, bn)
+	return ok
+}
+
+// GetSymbolAndDecimals queries only the token's symbol and decimals which is all that's needed
+// to display an amount of the token
+func GetSymbolAndDecimals(chain string, addr base.Address, bn uint64) *Token {
+	t := Token{}
+	t.Symbol, t.HasSymbol = callString(chain, addr, symbolSelector, bn)
+	t.Decimals, t.HasDecimals = callUint(chain, addr, decimalsSelector, bn)
+	return &t
+}
+
+// SupportsInterface asks the contract (per ERC-165) if it implements the interface
+func SupportsInterface(chain string, addr base.Address, interfaceId []byte, bn uint64) bool {
+	data := make([]byte, 4+32)
+	copy(data, supportsInterfaceSelector)
+	copy(data[4:], interfaceId)
+	output, err := rpcClient.CallContractAt(chain, addr.Hex(), data, bn)
+	if err != nil || len(output) != 32 {
+		return false
+	}
+	return new(big.Int).SetBytes(output).Cmp(big.NewInt(1)) == 0
+}
+
+// callString calls a function taking no arguments that returns a string. Some older tokens
+// return bytes32 instead, so we accept that as well.
+func callString(chain string, addr base.Address, selector []byte, bn uint64) (string, bool) {
+	output, err := rpcClient.CallContractAt(chain, addr.Hex(), selector, bn)
+	if err != nil || len(output) == 0 {
+		return "", false
+	}
+
+	if len(output) == 32 {
+		return CleanString(string(output)), true
+	}
+
+	stringType, _ := abi.NewType("string", "", nil)
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(output)
+	if err != nil || len(values) != 1 {
+		return "", false
+	}
+	str, ok := values[0].(string)
+	return CleanString(str), ok
+}
+
+// callUint256 sends the call data to the contract and expects a single 32-byte word in return
+func callUint256(chain string, addr base.Address, data []byte, bn uint64) (*big.Int, bool) {
+	output, err := rpcClient.CallContractAt(chain, addr.Hex(), data, bn)
+	if err != nil || len(output) != 32 {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(output), true
+}
+
+// callUint calls a function taking no arguments that returns an unsigned integer
+func callUint(chain string, addr base.Address, selector []byte, bn uint64) (uint64, bool) {
+	value, ok := callUint256(chain, addr, selector, bn)
+	if !ok || !value.IsUint64() {
+		return 0, false
+	}
+	return value.Uint64(), true
+}
+
+// CleanString removes the zero padding and any characters that would break the names database
+func CleanString(str string) string {
+	str = strings.TrimRight(str, "\x00")
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || r == 0 {
+			return ' '
+		}
+		return r
+	}, str))
+}
@@ -0,0 +1,17 @@
+package token
+
+import "testing"
+
+func TestCleanString(t *testing.T) {
+	tests := map[string]string{
+		"Maker\x00\x00\x00\x00":   "Maker",
+		"  Wrapped\tEther\n":      "Wrapped Ether",
+		"":                        "",
+		"DAI\x00\x00\x00\x00\x00": "DAI",
+	}
+	for input, expected := range tests {
+		if got := CleanString(input); got != expected {
+			t.Errorf("CleanString(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package transfer turns a transaction's traces and logs into normalized transfers of native ETH
+// and tokens (ERC-20, ERC-721, ERC-1155, and WETH)
+package transfer
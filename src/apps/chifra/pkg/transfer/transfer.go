@@ -0,0 +1,319 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package transfer
+
+import (
+	"math/big"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/articulate"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/token"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
+)
+
+// FakeEthAddress is the asset address of native ETH transfers
+var FakeEthAddress = base.HexToAddress("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+
+// The kinds of transfers (a transfer's TokenType)
+const (
+	Eth     = "eth"
+	Erc20   = "erc20"
+	Erc721  = "erc721"
+	Erc1155 = "erc1155"
+	Weth    = "weth"
+)
+
+var (
+	transferSingleTopic = base.HexToHash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62")
+	transferBatchTopic  = base.HexToHash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb")
+	depositTopic        = base.HexToHash("0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c")
+	withdrawalTopic     = base.HexToHash("0x7fcf532c15f0a6db0bd6d0e038bea71d30d808c7d98cb3bf7268a95bf5081b65")
+)
+
+// FromTransaction returns the transfers made by the transaction: native ETH moved by its traces
+// (or by the transaction itself if it carries no traces) followed by the token transfers in its
+// logs. A failed transaction transfers nothing. Symbols and decimals are taken from the names
+// findName finds (it may be nil, see names.FindName), so tokens it does not know are left with
+// zero (unknown) decimals (see TokenCache). ethSymbol names the chain's native asset.
+func FromTransaction(tx *types.SimpleTransaction, ethSymbol string, findName func(base.Address) (types.SimpleName, bool)) []types.SimpleTransfer {
+	if tx.IsError {
+		return []types.SimpleTransfer{}
+	}
+
+	transfers := ethTransfers(tx)
+	if tx.Receipt != nil {
+		for i := range tx.Receipt.Logs {
+			transfers = append(transfers, tokenTransfers(&tx.Receipt.Logs[i], transfers)...)
+		}
+	}
+
+	encoding := ""
+	if len(tx.Input) >= 10 {
+		encoding = tx.Input[:10]
+	}
+	for i := range transfers {
+		t := &transfers[i]
+		t.BlockNumber = tx.BlockNumber
+		t.TransactionIndex = tx.TransactionIndex
+		t.TransactionHash = tx.Hash
+		t.Timestamp = tx.Timestamp
+		t.Date = utils.FormattedDate(tx.Timestamp)
+		t.Encoding = encoding
+		describeAsset(t, ethSymbol, findName)
+	}
+	return transfers
+}
+
+// ethTransfers returns the transfers of value made by the (non-reverted) traces. If there are
+// no traces, the transaction's own value is all we know about.
+func ethTransfers(tx *types.SimpleTransaction) []types.SimpleTransfer {
+	transfers := []types.SimpleTransfer{}
+	if len(tx.Traces) == 0 {
+		if tx.Value.Sign() > 0 {
+			transfers = append(transfers, newEthTransfer(tx.From, tx.To, &tx.Value))
+		}
+		return transfers
+	}
+
+	reverted := [][]uint64{}
+	for i := range tx.Traces {
+		trace := &tx.Traces[i]
+		if trace.Action == nil || isBelow(trace.TraceAddress, reverted) {
+			continue
+		}
+		if len(trace.Error) > 0 {
+			reverted = append(reverted, trace.TraceAddress)
+			continue
+		}
+
+		switch trace.TraceType {
+		case "call":
+			// A delegatecall's value is that of its caller which has already been counted
+			if trace.Action.CallType != "delegatecall" && trace.Action.Value.Sign() > 0 {
+				transfers = append(transfers, newEthTransfer(trace.Action.From, trace.Action.To, &trace.Action.Value))
+			}
+		case "create":
+			if trace.Result != nil && trace.Action.Value.Sign() > 0 {
+				transfers = append(transfers, newEthTransfer(trace.Action.From, trace.Result.Address, &trace.Action.Value))
+			}
+		case "suicide":
+			if trace.Action.Balance.Sign() > 0 {
+				transfers = append(transfers, newEthTransfer(trace.Action.Address, trace.Action.RefundAddress, &trace.Action.Balance))
+			}
+		}
+	}
+	return transfers
+}
+
+// isBelow returns true if the trace is (or is beneath) one of the given traces
+func isBelow(traceAddress []uint64, parents [][]uint64) bool {
+	for _, parent := range parents {
+		if len(parent) > len(traceAddress) {
+			continue
+		}
+		below := true
+		for i := range parent {
+			if parent[i] != traceAddress[i] {
+				below = false
+				break
+			}
+		}
+		if below {
+			return true
+		}
+	}
+	return false
+}
+
+func newEthTransfer(sender, recipient base.Address, amount *base.Wei) types.SimpleTransfer {
+	t := types.SimpleTransfer{
+		Sender:    sender,
+		Recipient: recipient,
+		AssetAddr: FakeEthAddress,
+		TokenType: Eth,
+	}
+	t.Amount.Set(amount)
+	return t
+}
+
+// tokenTransfers returns the transfers the log records, if any. WETH deposits and withdrawals
+// count only if they are backed by a transfer of the same amount of ETH to (or from) the
+// contract, since other contracts emit events with the same signatures.
+func tokenTransfers(log *types.SimpleLog, ethTransfers []types.SimpleTransfer) []types.SimpleTransfer {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+
+	newTransfer := func(tokenType string, sender, recipient base.Address, amount *big.Int) types.SimpleTransfer {
+		t := types.SimpleTransfer{
+			Sender:    sender,
+			Recipient: recipient,
+			AssetAddr: log.Address,
+			LogIndex:  log.LogIndex,
+			TokenType: tokenType,
+		}
+		t.Amount.Set(amount)
+		return t
+	}
+
+	words := dataWords(log.Data)
+	switch {
+	case articulate.ParseTransferEvent(log) != nil:
+		sender, recipient := topicAddress(log.Topics[1]), topicAddress(log.Topics[2])
+		if len(log.Topics) == 4 {
+			t := newTransfer(Erc721, sender, recipient, big.NewInt(1))
+			t.TokenId = topicInt(log.Topics[3])
+			return []types.SimpleTransfer{t}
+		}
+		amount := big.NewInt(0)
+		if len(words) > 0 {
+			amount = words[0]
+		}
+		return []types.SimpleTransfer{newTransfer(Erc20, sender, recipient, amount)}
+
+	case log.Topics[0] == transferSingleTopic && len(log.Topics) == 4 && len(words) == 2:
+		t := newTransfer(Erc1155, topicAddress(log.Topics[2]), topicAddress(log.Topics[3]), words[1])
+		t.TokenId = words[0]
+		return []types.SimpleTransfer{t}
+
+	case log.Topics[0] == transferBatchTopic && len(log.Topics) == 4:
+		ids, values := wordArray(words, 0), wordArray(words, 1)
+		if ids == nil || len(ids) != len(values) {
+			return nil
+		}
+		ret := make([]types.SimpleTransfer, 0, len(ids))
+		for i := range ids {
+			t := newTransfer(Erc1155, topicAddress(log.Topics[2]), topicAddress(log.Topics[3]), values[i])
+			t.TokenId = ids[i]
+			ret = append(ret, t)
+		}
+		return ret
+
+	case log.Topics[0] == depositTopic && len(log.Topics) == 2 && len(words) == 1:
+		// Wrapping mints WETH for the depositor
+		if hasEthTransfer(ethTransfers, nil, &log.Address, words[0]) {
+			return []types.SimpleTransfer{newTransfer(Weth, base.Address{}, topicAddress(log.Topics[1]), words[0])}
+		}
+
+	case log.Topics[0] == withdrawalTopic && len(log.Topics) == 2 && len(words) == 1:
+		// Unwrapping burns the withdrawer's WETH
+		src := topicAddress(log.Topics[1])
+		if hasEthTransfer(ethTransfers, &log.Address, &src, words[0]) {
+			return []types.SimpleTransfer{newTransfer(Weth, src, base.Address{}, words[0])}
+		}
+	}
+
+	return nil
+}
+
+// hasEthTransfer returns true if there is an ETH transfer of the amount from the sender (if not
+// nil) to the recipient
+func hasEthTransfer(transfers []types.SimpleTransfer, sender, recipient *base.Address, amount *big.Int) bool {
+	for i := range transfers {
+		t := &transfers[i]
+		if t.TokenType == Eth && t.Recipient == *recipient && (sender == nil || t.Sender == *sender) && t.Amount.Cmp(amount) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// describeAsset sets the symbol and decimals of the transfer's asset
+func describeAsset(t *types.SimpleTransfer, ethSymbol string, findName func(base.Address) (types.SimpleName, bool)) {
+	if t.TokenType == Eth {
+		t.AssetSymbol, t.Decimals = ethSymbol, 18
+		return
+	}
+
+	if findName == nil {
+		return
+	}
+	if name, ok := findName(t.AssetAddr); ok {
+		t.AssetSymbol = name.Symbol
+		t.Decimals = name.Decimals
+	}
+}
+
+// TokenCache asks tokens missing from the names database for their symbol and decimals, asking
+// each token only once
+type TokenCache struct {
+	chain  string
+	tokens map[base.Address]*token.Token
+}
+
+// NewTokenCache returns an empty cache for the chain
+func NewTokenCache(chain string) *TokenCache {
+	return &TokenCache{
+		chain:  chain,
+		tokens: make(map[base.Address]*token.Token),
+	}
+}
+
+// Describe fills in the symbol and decimals the names database did not know. A token that does
+// not report its decimals keeps zero decimals (that is, unknown) rather than being guessed at.
+func (c *TokenCache) Describe(transfers []types.SimpleTransfer) {
+	for i := range transfers {
+		t := &transfers[i]
+		fungible := t.TokenType == Erc20 || t.TokenType == Weth
+		if t.TokenType == Eth || (len(t.AssetSymbol) > 0 && (!fungible || t.Decimals > 0)) {
+			continue
+		}
+
+		tok, ok := c.tokens[t.AssetAddr]
+		if !ok {
+			tok = token.GetSymbolAndDecimals(c.chain, t.AssetAddr, utils.NOPOS)
+			c.tokens[t.AssetAddr] = tok
+		}
+		if len(t.AssetSymbol) == 0 {
+			t.AssetSymbol = tok.Symbol
+		}
+		if fungible && t.Decimals == 0 {
+			t.Decimals = tok.Decimals
+		}
+	}
+}
+
+func topicAddress(topic base.Hash) base.Address {
+	return base.HexToAddress("0x" + topic.Hex()[26:])
+}
+
+func topicInt(topic base.Hash) *big.Int {
+	return new(big.Int).SetBytes(topic.Bytes())
+}
+
+// dataWords splits the log's data into 32-byte words. A trailing partial word is ignored.
+func dataWords(data string) []*big.Int {
+	if len(data) < 2 {
+		return nil
+	}
+	data = data[2:]
+	words := make([]*big.Int, 0, len(data)/64)
+	for i := 0; i+64 <= len(data); i += 64 {
+		word, ok := new(big.Int).SetString(data[i:i+64], 16)
+		if !ok {
+			return nil
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// wordArray decodes the ABI-encoded dynamic array of uint256 that is the n-th parameter of the
+// data. Returns nil if the data is malformed.
+func wordArray(words []*big.Int, n int) []*big.Int {
+	if n >= len(words) || !words[n].IsUint64() || words[n].Uint64()%32 != 0 {
+		return nil
+	}
+	start := words[n].Uint64() / 32
+	if start >= uint64(len(words)) || !words[start].IsUint64() {
+		return nil
+	}
+	count := words[start].Uint64()
+	if count > uint64(len(words))-start-1 {
+		return nil
+	}
+	return words[start+1 : start+1+count]
+}
@@ -0,0 +1,116 @@
+package transfer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+var (
+	alice = base.HexToAddress("0xf503017d7baf7fbc0fff7492b751025c6a78179b")
+	bob   = base.HexToAddress("0x054993ab0f2b1acc0fdc65405ee203b4271bebe6")
+	usdc  = base.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	weth  = base.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+)
+
+func word(n int64) string {
+	h := base.HexToHash("0x" + big.NewInt(n).Text(16))
+	return h.Hex()[2:]
+}
+
+func addrTopic(addr base.Address) base.Hash {
+	return base.HexToHash("0x000000000000000000000000" + addr.Hex()[2:])
+}
+
+func TestTransfers(t *testing.T) {
+	transferTopic := base.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	tx := types.SimpleTransaction{
+		BlockNumber:      100,
+		TransactionIndex: 2,
+		From:             alice,
+		To:               weth,
+		Input:            "0xd0e30db0",
+		Traces: []types.SimpleTrace{
+			{TraceType: "call", TraceAddress: []uint64{}, Action: &types.SimpleTraceAction{From: alice, To: weth, Value: *big.NewInt(5)}},
+			{TraceType: "call", TraceAddress: []uint64{0}, Error: "Reverted", Action: &types.SimpleTraceAction{From: weth, To: bob, Value: *big.NewInt(1)}},
+			{TraceType: "call", TraceAddress: []uint64{0, 0}, Action: &types.SimpleTraceAction{From: bob, To: alice, Value: *big.NewInt(1)}},
+			{TraceType: "call", TraceAddress: []uint64{1}, Action: &types.SimpleTraceAction{From: weth, To: bob, Value: *big.NewInt(1), CallType: "delegatecall"}},
+		},
+		Receipt: &types.SimpleReceipt{
+			Logs: []types.SimpleLog{
+				{Address: weth, LogIndex: 0, Topics: []base.Hash{depositTopic, addrTopic(alice)}, Data: "0x" + word(5)},
+				{Address: usdc, LogIndex: 1, Topics: []base.Hash{transferTopic, addrTopic(alice), addrTopic(bob)}, Data: "0x" + word(7)},
+				{Address: usdc, LogIndex: 2, Topics: []base.Hash{transferTopic, addrTopic(bob), addrTopic(alice), base.HexToHash("0x" + word(42))}},
+				{Address: usdc, LogIndex: 3, Topics: []base.Hash{transferSingleTopic, addrTopic(alice), addrTopic(alice), addrTopic(bob)}, Data: "0x" + word(9) + word(3)},
+				{Address: usdc, LogIndex: 4, Topics: []base.Hash{transferBatchTopic, addrTopic(alice), addrTopic(alice), addrTopic(bob)},
+					Data: "0x" + word(64) + word(160) + word(2) + word(10) + word(11) + word(2) + word(1) + word(2)},
+				// A deposit not backed by ETH is not a WETH deposit
+				{Address: usdc, LogIndex: 5, Topics: []base.Hash{depositTopic, addrTopic(alice)}, Data: "0x" + word(5)},
+			},
+		},
+	}
+
+	namesMap := map[base.Address]types.SimpleName{
+		usdc: {Symbol: "TOK", Decimals: 6},
+	}
+	findName := func(addr base.Address) (types.SimpleName, bool) {
+		name, ok := namesMap[addr]
+		return name, ok
+	}
+	transfers := FromTransaction(&tx, "ETH", findName)
+
+	expected := []struct {
+		tokenType string
+		sender    base.Address
+		recipient base.Address
+		amount    int64
+		tokenId   int64
+	}{
+		{Eth, alice, weth, 5, -1},
+		{Weth, base.Address{}, alice, 5, -1},
+		{Erc20, alice, bob, 7, -1},
+		{Erc721, bob, alice, 1, 42},
+		{Erc1155, alice, bob, 3, 9},
+		{Erc1155, alice, bob, 1, 10},
+		{Erc1155, alice, bob, 2, 11},
+	}
+	if len(transfers) != len(expected) {
+		t.Fatalf("expected %d transfers, got %d", len(expected), len(transfers))
+	}
+	for i, e := range expected {
+		got := transfers[i]
+		if got.TokenType != e.tokenType || got.Sender != e.sender || got.Recipient != e.recipient || got.Amount.Int64() != e.amount {
+			t.Errorf("%d: unexpected transfer %s %s %s %s", i, got.TokenType, got.Sender.Hex(), got.Recipient.Hex(), got.Amount.String())
+		}
+		if (e.tokenId < 0) != (got.TokenId == nil) || (got.TokenId != nil && got.TokenId.Int64() != e.tokenId) {
+			t.Errorf("%d: unexpected token id %v", i, got.TokenId)
+		}
+		if got.BlockNumber != 100 || got.TransactionIndex != 2 || got.Encoding != "0xd0e30db0" {
+			t.Errorf("%d: unexpected transaction fields", i)
+		}
+	}
+	if transfers[0].AssetAddr != FakeEthAddress || transfers[0].AssetSymbol != "ETH" || transfers[0].Decimals != 18 {
+		t.Error("unexpected ETH asset", transfers[0].AssetSymbol, transfers[0].Decimals)
+	}
+	if transfers[2].AssetSymbol != "TOK" || transfers[2].Decimals != 6 || transfers[2].LogIndex != 1 {
+		t.Error("unexpected token asset", transfers[2].AssetSymbol, transfers[2].Decimals)
+	}
+	if transfers[1].Decimals != 0 {
+		t.Error("expected a token missing from the names map to have unknown decimals", transfers[1].Decimals)
+	}
+
+	tx.IsError = true
+	if len(FromTransaction(&tx, "ETH", nil)) != 0 {
+		t.Error("expected a failed transaction to transfer nothing")
+	}
+
+	// Without traces, only the transaction's own value is known
+	tx = types.SimpleTransaction{From: alice, To: bob}
+	tx.Value.SetInt64(3)
+	if transfers := FromTransaction(&tx, "ETH", nil); len(transfers) != 1 || transfers[0].Amount.Int64() != 3 {
+		t.Error("expected the transaction's value to be transferred")
+	}
+}
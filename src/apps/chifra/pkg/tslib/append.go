@@ -1,7 +1,7 @@
 package tslib
 
 import (
-	"encoding/binary"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -9,8 +9,45 @@ import (
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
 )
 
+// Append adds the records to the end of the timestamp file, creating the file if needed. Records
+// must follow the last record in the file. Version 1 files must remain dense and their values
+// must fit in 32 bits (otherwise ErrNeedsMigration is returned).
 func Append(chain string, tsArray []TimestampRecord) error {
-	tsFn := config.GetPathToIndex(chain) + "ts.bin"
+	if len(tsArray) == 0 {
+		return nil
+	}
+
+	tsFn := getPathToTimestamps(chain)
+	if !file.FileExists(tsFn) {
+		DeCache(chain)
+		header := newHeader(tsArray[0])
+		data, err := toDisc(header, tsArray)
+		if err != nil {
+			return err
+		}
+		return writeTimestamps(tsFn, header, data)
+	}
+
+	header, err := Header(chain)
+	if err != nil {
+		return err
+	}
+	next, err := NextBn(chain)
+	if err != nil {
+		return err
+	}
+	for _, record := range tsArray {
+		if header.Version == TsVersion1 && record.Bn != next {
+			if record.Bn > next {
+				return fmt.Errorf("block %d would leave a gap after block %d: %w", record.Bn, next-1, ErrNeedsMigration)
+			}
+			return fmt.Errorf("block %d is out of order, expected block %d", record.Bn, next)
+		} else if record.Bn < next {
+			return fmt.Errorf("block %d is out of order, expected block %d or later", record.Bn, next)
+		}
+		next = record.Bn + 1
+	}
+
 	tmpPath := filepath.Join(config.GetPathToCache(chain), "tmp")
 	if backupFn, err := file.MakeBackup(tmpPath, tsFn); err == nil {
 		DeCache(chain)
@@ -29,7 +66,7 @@ func Append(chain string, tsArray []TimestampRecord) error {
 			fp.Close()
 		}()
 
-		err = binary.Write(fp, binary.LittleEndian, tsArray)
+		err = writeRecords(fp, header, tsArray)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,212 @@
+package tslib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// A hard fork as it appears in a chain config (the "config" section of a geth genesis file)
+type chainConfigFork struct {
+	key  string // the fork's key in the chain config
+	name string // the name of the special block
+}
+
+// Forks activated at a block number, in the order they happened on mainnet. The names match
+// mainnet's specials where they exist.
+var blockForks = []chainConfigFork{
+	{"homesteadBlock", "homestead"},
+	{"daoForkBlock", "daofork"},
+	{"eip150Block", "tangerine"},
+	{"eip158Block", "spurious"},
+	{"byzantiumBlock", "byzantium"},
+	{"constantinopleBlock", "constantinople"},
+	{"petersburgBlock", "petersburg"},
+	{"istanbulBlock", "istanbul"},
+	{"muirGlacierBlock", "muirglacier"},
+	{"berlinBlock", "berlin"},
+	{"londonBlock", "london"},
+	{"arrowGlacierBlock", "arrowglacier"},
+	{"grayGlacierBlock", "grayglacier"},
+}
+
+// Forks activated at a timestamp (after the merge)
+var timeForks = []chainConfigFork{
+	{"shanghaiTime", "shanghai"},
+	{"cancunTime", "cancun"},
+	{"pragueTime", "prague"},
+	{"osakaTime", "osaka"},
+}
+
+// GetForkSpecials derives the hard fork special blocks of a chain from its chain config, which may
+// be either a geth genesis file or its "config" section. Forks activated at a block are read
+// directly. Forks activated at a timestamp are found at the first block at or after that time. The
+// merge (if the config has a terminalTotalDifficulty) is found at the first block with a difficulty
+// of zero. Forks that have not yet happened are skipped.
+func GetForkSpecials(chain string, chainConfig []byte) ([]types.SimpleNamedBlock, error) {
+	values, err := parseChainConfig(chainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := rpcClient.BlockNumber(config.GetRpcProvider(chain))
+	latestBlock, err := rpcClient.GetBlockHeaderByNumber(chain, latest)
+	if err != nil {
+		return nil, err
+	}
+
+	forks := []types.SimpleNamedBlock{}
+	add := func(name string, bn uint64) error {
+		special, err := newSpecial(chain, name, bn)
+		if err != nil {
+			return err
+		}
+		forks = append(forks, *special)
+		return nil
+	}
+
+	for _, fork := range blockForks {
+		if bn, ok := values[fork.key]; ok && bn <= latest {
+			if err := add(fork.name, bn); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, ok := values["terminalTotalDifficulty"]; ok && latestBlock.Difficulty == 0 {
+		bn, err := firstBlockWhere(chain, latest, func(block *types.SimpleBlock[string]) bool {
+			return block.Difficulty == 0
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add("merge", bn); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fork := range timeForks {
+		if ts, ok := values[fork.key]; ok && base.Timestamp(ts) <= latestBlock.Timestamp {
+			bn, err := firstBlockWhere(chain, latest, func(block *types.SimpleBlock[string]) bool {
+				return block.Timestamp >= base.Timestamp(ts)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := add(fork.name, bn); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(forks, func(i, j int) bool {
+		return forks[i].BlockNumber < forks[j].BlockNumber
+	})
+	return forks, nil
+}
+
+// WriteForkSpecials adds the forks to the chain's built-in special blocks (specials.csv), creating
+// the file if needed. Forks whose names are already present are left unchanged and forks hidden
+// by a user-defined block of the same name are skipped with a warning. Returns the forks that were
+// added.
+func WriteForkSpecials(chain string, forks []types.SimpleNamedBlock) ([]types.SimpleNamedBlock, error) {
+	specialsPath := config.GetPathToChainConfig(chain) + "specials.csv"
+	existing := []types.SimpleNamedBlock{}
+	if _, err := os.Stat(specialsPath); err == nil {
+		if existing, err = readSpecials(specialsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	present := make(map[string]bool, len(existing))
+	for _, special := range existing {
+		present[special.Name] = true
+	}
+	userSpecials, err := GetUserSpecials(chain)
+	if err != nil {
+		return nil, err
+	}
+	userNames := make(map[string]bool, len(userSpecials))
+	for _, special := range userSpecials {
+		userNames[special.Name] = true
+	}
+
+	added := []types.SimpleNamedBlock{}
+	for _, fork := range forks {
+		if present[fork.Name] {
+			continue
+		}
+		present[fork.Name] = true
+		if userNames[fork.Name] {
+			logger.Warn("The user-defined block", fork.Name, "hides a fork of the same name")
+			continue
+		}
+		added = append(added, fork)
+	}
+	if len(added) == 0 {
+		return added, nil
+	}
+
+	return added, writeSpecials(specialsPath, append(existing, added...))
+}
+
+// parseChainConfig returns the fork values (block numbers or timestamps) found in the chain config
+func parseChainConfig(chainConfig []byte) (map[string]uint64, error) {
+	decoder := json.NewDecoder(bytes.NewReader(chainConfig))
+	decoder.UseNumber()
+	var top map[string]any
+	if err := decoder.Decode(&top); err != nil {
+		return nil, fmt.Errorf("invalid chain config: %w", err)
+	}
+
+	values := top
+	if section, ok := top["config"].(map[string]any); ok {
+		values = section
+	}
+	if _, ok := values["chainId"]; !ok {
+		return nil, errors.New("invalid chain config: no chainId found")
+	}
+
+	ret := make(map[string]uint64)
+	for key, value := range values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if v, err := number.Int64(); err == nil && v >= 0 {
+			ret[key] = uint64(v)
+		} else if key == "terminalTotalDifficulty" {
+			// Too large for an int64, but we only need to know it's there
+			ret[key] = 0
+		}
+	}
+	return ret, nil
+}
+
+// firstBlockWhere returns the first block at or before latest for which the condition holds. The
+// condition must hold for every block after the first one for which it holds.
+func firstBlockWhere(chain string, latest uint64, condition func(*types.SimpleBlock[string]) bool) (uint64, error) {
+	low, high := uint64(0), latest
+	for low < high {
+		mid := low + (high-low)/2
+		block, err := rpcClient.GetBlockHeaderByNumber(chain, mid)
+		if err != nil {
+			return 0, err
+		}
+		if condition(&block) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
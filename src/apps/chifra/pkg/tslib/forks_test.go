@@ -0,0 +1,77 @@
+package tslib
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestParseChainConfig(t *testing.T) {
+	genesis := `{
+		"config": {
+			"chainId": 11155111,
+			"homesteadBlock": 0,
+			"londonBlock": 12965000,
+			"terminalTotalDifficulty": 58750000000000000000000,
+			"shanghaiTime": 1677557088,
+			"ethash": {}
+		},
+		"difficulty": "0x20000"
+	}`
+	values, err := parseChainConfig([]byte(genesis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]uint64{
+		"chainId":                 11155111,
+		"homesteadBlock":          0,
+		"londonBlock":             12965000,
+		"terminalTotalDifficulty": 0,
+		"shanghaiTime":            1677557088,
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Error("unexpected values", values)
+	}
+
+	// The config section on its own is accepted as well
+	if values, err = parseChainConfig([]byte(`{"chainId": 1, "berlinBlock": 12244000}`)); err != nil || values["berlinBlock"] != 12244000 {
+		t.Error("unexpected result for a bare config", values, err)
+	}
+	if _, err = parseChainConfig([]byte(`{"config": {"londonBlock": 1}}`)); err == nil {
+		t.Error("expected an error for a config without a chainId")
+	}
+}
+
+func TestWriteSpecials(t *testing.T) {
+	specialsPath := filepath.Join(t.TempDir(), "specials.csv")
+	specials := []types.SimpleNamedBlock{
+		{BlockNumber: 12965000, Name: "london", Timestamp: 1628166822, Date: "2021-08-05 12:33:42"},
+		{BlockNumber: 0, Name: "first", Timestamp: 1438269975, Date: "2015-07-30 15:26:15"},
+	}
+	if err := writeSpecials(specialsPath, specials); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := readSpecials(specialsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 || read[0].Name != "first" || read[1].Timestamp != 1628166822 {
+		t.Error("specials should be read back sorted by block number", read)
+	}
+}
+
+func TestValidateSpecialName(t *testing.T) {
+	for _, name := range []string{"shanghai", "my_block", "devcon7"} {
+		if err := ValidateSpecialName(name); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, name := range []string{"latest", "Shanghai", "block-1", "7up", ""} {
+		if ValidateSpecialName(name) == nil {
+			t.Error("expected", name, "to be invalid")
+		}
+	}
+}
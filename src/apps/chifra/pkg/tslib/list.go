@@ -7,16 +7,22 @@ package tslib
 import (
 	"encoding/csv"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/logger"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
 )
 
-// GetSpecials returns a chain-specific list of special block names and numbers
+// GetSpecials returns a chain-specific list of special block names and numbers. The built-in
+// special blocks (specials.csv) are followed by the user-defined named blocks (user_specials.csv),
+// both of which are found in the chain's config folder.
 func GetSpecials(chain string) (specials []types.SimpleNamedBlock, err error) {
 	specialsPath := config.GetPathToChainConfig(chain) + "specials.csv"
 	_, err = os.Stat(specialsPath)
@@ -26,13 +32,31 @@ func GetSpecials(chain string) (specials []types.SimpleNamedBlock, err error) {
 			// should not happen, but we want to know if it does
 			logger.Info("No special block file found for chain", chain)
 		}
-		return specials, nil
+	} else if specials, err = readSpecials(specialsPath); err != nil {
+		return
+	}
+
+	userSpecials, err := GetUserSpecials(chain)
+	if err != nil {
+		return
 	}
+	specials = append(specials, userSpecials...)
 
+	if len(specials) == 0 && err == nil && file.FileExists(specialsPath) {
+		err = errors.New("found no special blocks")
+	}
+
+	return
+}
+
+// readSpecials reads a special blocks file (with columns bn, name, ts, and date)
+func readSpecials(specialsPath string) (specials []types.SimpleNamedBlock, err error) {
 	file, err := os.OpenFile(specialsPath, os.O_RDONLY, 0)
 	if err != nil {
 		return
 	}
+	defer file.Close()
+
 	reader := csv.NewReader(file)
 	reader.FieldsPerRecord = 4
 
@@ -58,13 +82,23 @@ func GetSpecials(chain string) (specials []types.SimpleNamedBlock, err error) {
 		}
 	}
 
-	if len(specials) == 0 {
-		err = errors.New("found no special blocks")
-	}
-
 	return
 }
 
+// writeSpecials writes a special blocks file sorted by block number
+func writeSpecials(specialsPath string, specials []types.SimpleNamedBlock) error {
+	sort.SliceStable(specials, func(i, j int) bool {
+		return specials[i].BlockNumber < specials[j].BlockNumber
+	})
+
+	lines := make([]string, 0, len(specials)+1)
+	lines = append(lines, "bn,name,ts,date")
+	for _, special := range specials {
+		lines = append(lines, fmt.Sprintf("%d,%s,%d,%s", special.BlockNumber, special.Name, special.Timestamp, special.Date))
+	}
+	return os.WriteFile(specialsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
 // IsSpecialBlock returns true if the given chain-specific name is a special block
 func IsSpecialBlock(chain, needle string) bool {
 	_, err := FromNameToBn(chain, needle)
@@ -0,0 +1,66 @@
+package tslib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
+)
+
+// Migrate converts a version 1 timestamp file to version 2. The records are unchanged, but the
+// file may then start at a block other than zero, have gaps, and hold timestamps past 2106. Note
+// that version 2 files are not readable by older versions of chifra.
+func Migrate(chain string) error {
+	header, err := Header(chain)
+	if err != nil {
+		return err
+	}
+	if header.Version != TsVersion1 {
+		return fmt.Errorf("the timestamp file is already at version %d", header.Version)
+	}
+
+	tsFn := getPathToTimestamps(chain)
+	tmpPath := filepath.Join(config.GetPathToCache(chain), "tmp")
+	if backupFn, err := file.MakeBackup(tmpPath, tsFn); err == nil {
+		defer func() {
+			DeCache(chain)
+			if file.FileExists(backupFn) {
+				// If the backup file exists, something failed, so we replace the original file.
+				os.Rename(backupFn, tsFn)
+				os.Remove(backupFn) // seems redundant, but may not be on some operating systems
+			}
+		}()
+
+		if err = migrateFile(tsFn); err != nil {
+			return err
+		}
+
+		os.Remove(backupFn)
+		return nil
+	} else {
+		return err
+	}
+}
+
+// migrateFile rewrites a version 1 timestamp file as a version 2 file
+func migrateFile(path string) error {
+	db, err := readTimestamps(path)
+	if err != nil {
+		return err
+	}
+	if db.header.Version != TsVersion1 {
+		return fmt.Errorf("the timestamp file is already at version %d", db.header.Version)
+	}
+
+	header := TimestampHeader{Version: TsVersion2, Resolution: 1}
+	records := make([]timestampRecordV2, len(db.v1))
+	for i, record := range db.v1 {
+		records[i] = timestampRecordV2{Bn: uint64(record.Bn), Ts: uint64(record.Ts)}
+	}
+	if len(records) > 0 {
+		header.FirstBn = records[0].Bn
+	}
+	return writeTimestamps(path, header, records)
+}
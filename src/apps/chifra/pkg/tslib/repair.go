@@ -1,13 +1,11 @@
 package tslib
 
 import (
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"unsafe"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
@@ -20,12 +18,21 @@ func Repair(chain string, bn uint64) error {
 	if err != nil {
 		return err
 	}
-	if bn >= cnt {
-		msg := fmt.Sprintf("Block number %d out of range %d", bn, cnt)
+
+	err = loadTimestamps(chain)
+	if err != nil {
+		return err
+	}
+
+	db := perChainTimestamps[chain]
+	header := db.header
+	index, found := db.find(bn)
+	if !found {
+		msg := fmt.Sprintf("Block number %d not found in %d timestamps", bn, cnt)
 		return errors.New(msg)
 	}
 
-	tsFn := filepath.Join(config.GetPathToIndex(chain), "ts.bin")
+	tsFn := getPathToTimestamps(chain)
 	tmpPath := filepath.Join(config.GetPathToCache(chain), "tmp")
 	if backupFn, err := file.MakeBackup(tmpPath, tsFn); err == nil {
 		defer func() {
@@ -44,13 +51,15 @@ func Repair(chain string, bn uint64) error {
 				// writeMutex.Unlock()
 			}()
 
-			recordSize := int64(unsafe.Sizeof(uint32(0))) * 2
-			pos := (recordSize * int64(bn))
+			pos := recordSizeV1 * int64(index)
+			if header.Version == TsVersion2 {
+				pos = headerSize + recordSizeV2*int64(index)
+			}
 			fp.Seek(pos, io.SeekStart)
 
 			block, _ := rpcClient.GetBlockHeaderByNumber(chain, bn)
-			record := TimestampRecord{Bn: uint32(block.BlockNumber), Ts: uint32(block.Timestamp)}
-			err = binary.Write(fp, binary.LittleEndian, &record)
+			record := TimestampRecord{Bn: block.BlockNumber, Ts: uint64(block.Timestamp)}
+			err = writeRecords(fp, header, []TimestampRecord{record})
 			if err != nil {
 				return err
 			}
@@ -1,12 +1,17 @@
 package tslib
 
 import (
+	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpc"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/utils"
 	"github.com/ethereum/go-ethereum"
+	gethRpc "github.com/ethereum/go-ethereum/rpc"
 )
 
 // FromDateToBn returns a chain-specific block number given a date string (date strings are valid JSON dates).
@@ -22,7 +27,14 @@ func FromDateToBn(chain, dateStr string) (uint64, error) {
 	return uint64(ret.Bn), err
 }
 
+// anchorTags are the block tags (other than latest) that may be used as special blocks
+var anchorTags = map[string]gethRpc.BlockNumber{
+	"finalized": gethRpc.FinalizedBlockNumber,
+	"safe":      gethRpc.SafeBlockNumber,
+}
+
 // FromNameToBn returns the chain-specific block number (if found) given the name of a special block. The list of special blocks is per-chain.
+// The anchors latest, finalized, and safe are queried from the node.
 func FromNameToBn(chain, name string) (uint64, error) {
 	if name == "latest" {
 		meta, err := rpcClient.GetMetaData(chain, false)
@@ -32,6 +44,17 @@ func FromNameToBn(chain, name string) (uint64, error) {
 		return meta.Latest, nil
 	}
 
+	if tag, ok := anchorTags[name]; ok {
+		provider := config.GetRpcProvider(chain)
+		ec := rpc.GetClient(provider)
+		defer ec.Close()
+		header, err := ec.HeaderByNumber(context.Background(), big.NewInt(int64(tag)))
+		if err != nil {
+			return uint64(utils.NOPOS), fmt.Errorf("block at %s returned an error: %w", name, err)
+		}
+		return header.Number.Uint64(), nil
+	}
+
 	specials, _ := GetSpecials(chain) // it's okay if it's empty
 	for _, value := range specials {
 		if value.Name == name {
@@ -31,6 +31,11 @@ func FromNameToDate(chain, name string) (gostradamus.DateTime, error) {
 		return FromTsToDate(ts)
 	}
 
+	if _, ok := anchorTags[name]; ok {
+		bn, _ := FromNameToBn(chain, name)
+		return FromBnToDate(chain, bn)
+	}
+
 	for _, value := range specials {
 		if value.Name == name {
 			return FromBnToDate(chain, value.BlockNumber)
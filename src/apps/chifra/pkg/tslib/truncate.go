@@ -1,8 +1,6 @@
 package tslib
 
 import (
-	"encoding/binary"
-	"io"
 	"os"
 	"path/filepath"
 
@@ -12,14 +10,15 @@ import (
 
 // var writeMutex sync.Mutex
 
+// Truncate removes the records for maxBn and all later blocks from the timestamp file
 func Truncate(chain string, maxBn uint64) error {
-	cnt, err := NTimestamps(chain)
+	next, err := NextBn(chain)
 	if err != nil {
 		return err
 	}
 
 	// It's already done
-	if maxBn >= cnt {
+	if maxBn >= next {
 		return nil
 	}
 
@@ -28,9 +27,10 @@ func Truncate(chain string, maxBn uint64) error {
 		return err
 	}
 
-	truncated := perChainTimestamps[chain].memory[0:maxBn]
+	db := perChainTimestamps[chain]
+	truncated := db.onDisc(0, db.search(maxBn))
 
-	tsFn := filepath.Join(config.GetPathToIndex(chain), "ts.bin")
+	tsFn := getPathToTimestamps(chain)
 	tmpPath := filepath.Join(config.GetPathToCache(chain), "tmp")
 	if backupFn, err := file.MakeBackup(tmpPath, tsFn); err == nil {
 		defer func() {
@@ -41,23 +41,13 @@ func Truncate(chain string, maxBn uint64) error {
 				os.Remove(backupFn) // seems redundant, but may not be on some operating systems
 			}
 		}()
-		if fp, err := os.OpenFile(tsFn, os.O_WRONLY|os.O_CREATE, 0644); err == nil {
-			defer func() {
-				fp.Close()
-			}()
 
-			fp.Seek(0, io.SeekStart)
-			err = binary.Write(fp, binary.LittleEndian, truncated)
-			if err != nil {
-				return err
-			}
-			fp.Sync()
-
-			os.Remove(backupFn)
-			return nil
-		} else {
+		if err = writeTimestamps(tsFn, db.header, truncated); err != nil {
 			return err
 		}
+
+		os.Remove(backupFn)
+		return nil
 	} else {
 		return err
 	}
@@ -4,77 +4,138 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
+	"unsafe"
 
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/file"
 )
 
+// Versions of the timestamp file. Version 1 files have no header and contain one (uint32, uint32)
+// record per block starting at block zero. Version 2 files start with a TimestampHeader followed by
+// (uint64, uint64) records sorted by block number. Version 2 files may start at any block and may
+// have gaps.
+const (
+	TsVersion1 uint32 = 1
+	TsVersion2 uint32 = 2
+)
+
+// TimestampHeader is the header of a version 2 timestamp file
+type TimestampHeader struct {
+	Magic      uint32 `json:"-"`
+	Version    uint32 `json:"version"`
+	FirstBn    uint64 `json:"firstBn"`
+	Resolution uint32 `json:"resolution"` // the number of timestamp units per second (chifra writes seconds, so this is 1)
+	Unused1    uint32 `json:"-"`
+	Unused2    uint64 `json:"-"`
+}
+
+// TimestampRecord is a block number and its timestamp in seconds
 type TimestampRecord struct {
-	Bn uint32 `json:"bn"`
-	Ts uint32 `json:"ts"`
+	Bn uint64 `json:"bn"`
+	Ts uint64 `json:"ts"`
+}
+
+// timestampRecordV1 is the on-disc record of a version 1 timestamp file
+type timestampRecordV1 struct {
+	Bn uint32
+	Ts uint32
+}
+
+// timestampRecordV2 is the on-disc record of a version 2 timestamp file. Ts is in units of the
+// header's Resolution.
+type timestampRecordV2 struct {
+	Bn uint64
+	Ts uint64
 }
 
+var (
+	headerSize   = int64(unsafe.Sizeof(TimestampHeader{}))
+	recordSizeV1 = int64(unsafe.Sizeof(timestampRecordV1{}))
+	recordSizeV2 = int64(unsafe.Sizeof(timestampRecordV2{}))
+)
+
+// TimestampDatabase holds the records of a timestamp file in memory exactly as they are on disc
+// (in v1 or v2 depending on the file's version), so a dense version 1 file costs no more than it
+// always has. Timestamps are converted to seconds only as records are handed out.
 type TimestampDatabase struct {
 	loaded bool
 	count  uint64
-	memory []TimestampRecord
+	header TimestampHeader
+	v1     []timestampRecordV1
+	v2     []timestampRecordV2
 }
 
 var perChainTimestamps = map[string]TimestampDatabase{}
 
+// ErrNeedsMigration is returned when data does not fit in a version 1 timestamp file
+var ErrNeedsMigration = errors.New("the timestamp file must be migrated (see chifra when --timestamps --migrate)")
+
+func getPathToTimestamps(chain string) string {
+	return config.GetPathToIndex(chain) + "ts.bin"
+}
+
 // NTimestamps returns the number of records in the timestamp file
 func NTimestamps(chain string) (uint64, error) {
 	if perChainTimestamps[chain].count > 0 {
 		return perChainTimestamps[chain].count, nil
 	}
 
-	tsPath := config.GetPathToIndex(chain) + "ts.bin"
-
-	fileStat, err := os.Stat(tsPath)
+	header, count, err := readHeader(getPathToTimestamps(chain))
 	if err != nil {
 		return 0, err
 	}
 
-	perChainTimestamps[chain] = TimestampDatabase{
-		loaded: perChainTimestamps[chain].loaded,
-		count:  uint64(fileStat.Size()) / 8,
-		memory: perChainTimestamps[chain].memory,
-	}
+	db := perChainTimestamps[chain]
+	db.count = count
+	db.header = header
+	perChainTimestamps[chain] = db
 	return perChainTimestamps[chain].count, nil
 }
 
-// loadTimestamps loads the timestamp data from the file into memory. If the timestamps are already loaded, we short circiut.
-func loadTimestamps(chain string) error {
-	if perChainTimestamps[chain].loaded {
-		return nil
+// Header returns the header of the timestamp file. For version 1 files, which have no header,
+// the returned header describes the file.
+func Header(chain string) (TimestampHeader, error) {
+	if _, err := NTimestamps(chain); err != nil {
+		return TimestampHeader{}, err
 	}
+	return perChainTimestamps[chain].header, nil
+}
 
+// NextBn returns the block number following the last record in the timestamp file, that is, the
+// first block that may be appended to it. For version 1 files this is the same as NTimestamps.
+func NextBn(chain string) (uint64, error) {
 	cnt, err := NTimestamps(chain)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	header := perChainTimestamps[chain].header
+	if cnt == 0 || header.Version == TsVersion1 {
+		return header.FirstBn + cnt, nil
 	}
 
-	tsPath := config.GetPathToIndex(chain) + "ts.bin"
-	tsFile, err := os.OpenFile(tsPath, os.O_RDONLY, 0)
+	last, err := FromIndex(chain, cnt-1)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	defer tsFile.Close()
+	return last.Bn + 1, nil
+}
 
-	memory := make([]TimestampRecord, cnt)
-	err = binary.Read(tsFile, binary.LittleEndian, memory)
-	if err != nil {
-		return err
+// loadTimestamps loads the timestamp data from the file into memory. If the timestamps are already loaded, we short circiut.
+func loadTimestamps(chain string) error {
+	if perChainTimestamps[chain].loaded {
+		return nil
 	}
 
-	perChainTimestamps[chain] = TimestampDatabase{
-		loaded: true,
-		count:  perChainTimestamps[chain].count,
-		memory: memory,
+	db, err := readTimestamps(getPathToTimestamps(chain))
+	if err != nil {
+		return err
 	}
 
+	perChainTimestamps[chain] = db
 	return nil
 }
 
@@ -94,20 +155,22 @@ func FromTs(chain string, ts base.Timestamp) (*TimestampRecord, error) {
 		return &TimestampRecord{}, err
 	}
 
-	if ts > base.Timestamp(perChainTimestamps[chain].memory[cnt-1].Ts) {
-		last := perChainTimestamps[chain].memory[cnt-1]
+	if cnt == 0 {
+		return &TimestampRecord{}, errors.New("the timestamp file is empty")
+	}
+
+	db := perChainTimestamps[chain]
+	if last := db.record(int(cnt) - 1); ts > base.Timestamp(last.Ts) {
 		secs := ts - base.Timestamp(last.Ts)
-		blks := uint32(float64(secs) / 13.3)
+		blks := uint64(float64(secs) / 13.3)
 		last.Bn = last.Bn + blks
-		last.Ts = uint32(ts)
-		return &last, ErrInTheFuture
+		last.Ts = uint64(ts)
+		return last, ErrInTheFuture
 	}
 
 	// Go docs: Search uses binary search to find and return the smallest index i in [0, n) at which f(i) is true,
 	index := sort.Search(int(cnt), func(i int) bool {
-		d := perChainTimestamps[chain].memory[i]
-		v := base.Timestamp(d.Ts)
-		return v > ts
+		return base.Timestamp(db.record(i).Ts) > ts
 	})
 
 	// ts should not be before the first block
@@ -118,15 +181,11 @@ func FromTs(chain string, ts base.Timestamp) (*TimestampRecord, error) {
 	// The index is one past where we want to be because it's the first block larger
 	index--
 
-	return &perChainTimestamps[chain].memory[index], nil
+	return db.record(index), nil
 }
 
 func DeCache(chain string) {
-	perChainTimestamps[chain] = TimestampDatabase{
-		loaded: false,
-		count:  0,
-		memory: nil,
-	}
+	perChainTimestamps[chain] = TimestampDatabase{}
 }
 
 // FromBn is a local function that returns a Timestamp record given a blockNum. It
@@ -137,14 +196,211 @@ func FromBn(chain string, bn uint64) (*TimestampRecord, error) {
 		return &TimestampRecord{}, err
 	}
 
-	if bn > cnt {
+	err = loadTimestamps(chain)
+	if err != nil {
+		return &TimestampRecord{}, err
+	}
+
+	db := perChainTimestamps[chain]
+	index, found := db.find(bn)
+	if !found {
 		return &TimestampRecord{}, errors.New("invalid block number " + fmt.Sprintf("%d of %d", bn, cnt))
 	}
 
+	return db.record(index), nil
+}
+
+// FromIndex returns the index'th record in the timestamp file. For version 1 files the index
+// is the block number.
+func FromIndex(chain string, index uint64) (*TimestampRecord, error) {
+	cnt, err := NTimestamps(chain)
+	if err != nil {
+		return &TimestampRecord{}, err
+	}
+
+	if index >= cnt {
+		return &TimestampRecord{}, errors.New("invalid index " + fmt.Sprintf("%d of %d", index, cnt))
+	}
+
 	err = loadTimestamps(chain)
 	if err != nil {
 		return &TimestampRecord{}, err
 	}
 
-	return &perChainTimestamps[chain].memory[bn], nil
+	db := perChainTimestamps[chain]
+	return db.record(int(index)), nil
+}
+
+// len returns the number of records in memory
+func (db *TimestampDatabase) len() int {
+	if db.header.Version == TsVersion1 {
+		return len(db.v1)
+	}
+	return len(db.v2)
+}
+
+// bn returns the block number of the i'th record
+func (db *TimestampDatabase) bn(i int) uint64 {
+	if db.header.Version == TsVersion1 {
+		return uint64(db.v1[i].Bn)
+	}
+	return db.v2[i].Bn
+}
+
+// record returns the i'th record with its timestamp in seconds
+func (db *TimestampDatabase) record(i int) *TimestampRecord {
+	if db.header.Version == TsVersion1 {
+		return &TimestampRecord{Bn: uint64(db.v1[i].Bn), Ts: uint64(db.v1[i].Ts)}
+	}
+	return &TimestampRecord{Bn: db.v2[i].Bn, Ts: db.v2[i].Ts / uint64(db.header.Resolution)}
+}
+
+// find returns the index of the record for the block. Records are usually dense, so we look
+// where the block would be if they were before searching.
+func (db *TimestampDatabase) find(bn uint64) (int, bool) {
+	firstBn, n := db.header.FirstBn, db.len()
+	if bn >= firstBn && bn-firstBn < uint64(n) && db.bn(int(bn-firstBn)) == bn {
+		return int(bn - firstBn), true
+	}
+	index := db.search(bn)
+	return index, index < n && db.bn(index) == bn
+}
+
+// search returns the index of the first record at or after the block
+func (db *TimestampDatabase) search(bn uint64) int {
+	return sort.Search(db.len(), func(i int) bool {
+		return db.bn(i) >= bn
+	})
+}
+
+// onDisc returns the records in memory from first up to (but not including) last in the form
+// they take on disc
+func (db *TimestampDatabase) onDisc(first, last int) interface{} {
+	if db.header.Version == TsVersion1 {
+		return db.v1[first:last]
+	}
+	return db.v2[first:last]
+}
+
+// readHeader returns the header of the timestamp file and the number of records it contains
+func readHeader(path string) (TimestampHeader, uint64, error) {
+	fp, err := os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return TimestampHeader{}, 0, err
+	}
+	defer fp.Close()
+
+	fileStat, err := fp.Stat()
+	if err != nil {
+		return TimestampHeader{}, 0, err
+	}
+
+	header := TimestampHeader{}
+	if fileStat.Size() >= headerSize {
+		if err = binary.Read(fp, binary.LittleEndian, &header); err != nil {
+			return TimestampHeader{}, 0, err
+		}
+	}
+
+	if header.Magic != file.MagicNumber {
+		// The first record of a version 1 file is block zero, so it can't look like a header
+		return TimestampHeader{Version: TsVersion1, Resolution: 1}, uint64(fileStat.Size() / recordSizeV1), nil
+	}
+
+	if header.Version != TsVersion2 {
+		return TimestampHeader{}, 0, fmt.Errorf("unsupported timestamp file version %d in %s", header.Version, path)
+	}
+	if header.Resolution == 0 {
+		header.Resolution = 1
+	}
+	return header, uint64((fileStat.Size() - headerSize) / recordSizeV2), nil
+}
+
+// readTimestamps reads a timestamp file of either version into memory as it is on disc
+func readTimestamps(path string) (TimestampDatabase, error) {
+	header, cnt, err := readHeader(path)
+	if err != nil {
+		return TimestampDatabase{}, err
+	}
+
+	fp, err := os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return TimestampDatabase{}, err
+	}
+	defer fp.Close()
+
+	db := TimestampDatabase{loaded: true, count: cnt, header: header}
+	if header.Version == TsVersion1 {
+		db.v1 = make([]timestampRecordV1, cnt)
+		err = binary.Read(fp, binary.LittleEndian, db.v1)
+		return db, err
+	}
+
+	if _, err = fp.Seek(headerSize, io.SeekStart); err != nil {
+		return TimestampDatabase{}, err
+	}
+	db.v2 = make([]timestampRecordV2, cnt)
+	err = binary.Read(fp, binary.LittleEndian, db.v2)
+	return db, err
+}
+
+// toDisc converts records (with timestamps in seconds) to the form they take on disc in a file
+// with the given header. Values that don't fit in a version 1 file return ErrNeedsMigration.
+func toDisc(header TimestampHeader, records []TimestampRecord) (interface{}, error) {
+	if header.Version == TsVersion1 {
+		v1 := make([]timestampRecordV1, len(records))
+		for i, record := range records {
+			if record.Bn > uint64(^uint32(0)) || record.Ts > uint64(^uint32(0)) {
+				return nil, ErrNeedsMigration
+			}
+			v1[i] = timestampRecordV1{Bn: uint32(record.Bn), Ts: uint32(record.Ts)}
+		}
+		return v1, nil
+	}
+
+	v2 := make([]timestampRecordV2, len(records))
+	for i, record := range records {
+		v2[i] = timestampRecordV2{Bn: record.Bn, Ts: record.Ts * uint64(header.Resolution)}
+	}
+	return v2, nil
+}
+
+// writeRecords writes the records (with timestamps in seconds) to the (already positioned) file
+// in the file's format
+func writeRecords(fp *os.File, header TimestampHeader, records []TimestampRecord) error {
+	data, err := toDisc(header, records)
+	if err != nil {
+		return err
+	}
+	return binary.Write(fp, binary.LittleEndian, data)
+}
+
+// writeTimestamps (re)writes an entire timestamp file given its header and its records as they
+// are to be written (see toDisc)
+func writeTimestamps(path string, header TimestampHeader, data interface{}) error {
+	fp, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	if header.Version == TsVersion2 {
+		header.Magic = file.MagicNumber
+		if err = binary.Write(fp, binary.LittleEndian, &header); err != nil {
+			return err
+		}
+	}
+	if err = binary.Write(fp, binary.LittleEndian, data); err != nil {
+		return err
+	}
+	return fp.Sync()
+}
+
+// newHeader returns the header for a new timestamp file whose first record is given. Files that
+// start at block zero are written in version 1 format so they may be shared with older versions.
+func newHeader(first TimestampRecord) TimestampHeader {
+	if first.Bn == 0 && first.Ts <= uint64(^uint32(0)) {
+		return TimestampHeader{Version: TsVersion1, Resolution: 1}
+	}
+	return TimestampHeader{Version: TsVersion2, FirstBn: first.Bn, Resolution: 1}
 }
@@ -0,0 +1,80 @@
+package tslib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTimestampFileVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ts.bin")
+	records := []TimestampRecord{{Bn: 0, Ts: 1438269973}, {Bn: 1, Ts: 1438269988}, {Bn: 2, Ts: 1438270017}}
+
+	header := newHeader(records[0])
+	data, err := toDisc(header, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTimestamps(path, header, data); err != nil {
+		t.Fatal(err)
+	}
+	if stat, _ := os.Stat(path); stat.Size() != 3*recordSizeV1 {
+		t.Fatal("expected a version 1 file, got size", stat.Size())
+	}
+
+	if err := migrateFile(path); err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := readTimestamps(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated.header.Version != TsVersion2 || migrated.header.FirstBn != 0 || migrated.header.Resolution != 1 || migrated.len() != len(records) {
+		t.Fatal("unexpected migrated file", migrated.header, migrated.len())
+	}
+	for i := range records {
+		if *migrated.record(i) != records[i] {
+			t.Error("record", i, "changed during migration", migrated.record(i), records[i])
+		}
+	}
+	if err := migrateFile(path); err == nil {
+		t.Error("expected a version 2 file to refuse migration")
+	}
+}
+
+func TestTimestampFileGaps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ts.bin")
+	records := []TimestampRecord{{Bn: 1000, Ts: 5000000000}, {Bn: 1001, Ts: 5000000002}, {Bn: 1005, Ts: 5000000010}}
+
+	header := newHeader(records[0])
+	if header.Version != TsVersion2 || header.FirstBn != 1000 || header.Resolution != 1 {
+		t.Fatal("expected a version 2 header", header)
+	}
+	if _, err := toDisc(TimestampHeader{Version: TsVersion1}, records); err != ErrNeedsMigration {
+		t.Fatal("expected version 1 files to reject 64-bit timestamps", err)
+	}
+
+	data, err := toDisc(header, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTimestamps(path, header, data); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := readTimestamps(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range records {
+		if *loaded.record(i) != records[i] {
+			t.Error("record", i, "did not round trip", loaded.record(i), records[i])
+		}
+	}
+
+	if index, found := loaded.find(1005); !found || index != 2 {
+		t.Error("expected to find block 1005 at index 2", index, found)
+	}
+	if _, found := loaded.find(1003); found {
+		t.Error("block 1003 is in a gap and should not be found")
+	}
+}
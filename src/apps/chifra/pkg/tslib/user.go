@@ -0,0 +1,123 @@
+package tslib
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/config"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/rpcClient"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// Names of special blocks must be parsable as block identifiers (see the identifiers package)
+var specialNameRe = regexp.MustCompile(`^[a-z_]+[0-9]*$`)
+
+// reservedNames may not be used for user-defined named blocks
+var reservedNames = map[string]bool{
+	"latest":    true,
+	"finalized": true,
+	"safe":      true,
+}
+
+func getPathToUserSpecials(chain string) string {
+	return config.GetPathToChainConfig(chain) + "user_specials.csv"
+}
+
+// GetUserSpecials returns the chain-specific list of user-defined named blocks
+func GetUserSpecials(chain string) ([]types.SimpleNamedBlock, error) {
+	userPath := getPathToUserSpecials(chain)
+	if _, err := os.Stat(userPath); err != nil {
+		return nil, nil
+	}
+	return readSpecials(userPath)
+}
+
+// CreateUserSpecial adds a user-defined named block at the given block. The name may not be that of
+// any other special block.
+func CreateUserSpecial(chain, name string, bn uint64) (*types.SimpleNamedBlock, error) {
+	if err := ValidateSpecialName(name); err != nil {
+		return nil, err
+	}
+	if IsSpecialBlock(chain, name) {
+		return nil, fmt.Errorf("a special block named %s already exists", name)
+	}
+
+	special, err := newSpecial(chain, name, bn)
+	if err != nil {
+		return nil, err
+	}
+
+	userSpecials, err := GetUserSpecials(chain)
+	if err != nil {
+		return nil, err
+	}
+	return special, writeSpecials(getPathToUserSpecials(chain), append(userSpecials, *special))
+}
+
+// UpdateUserSpecial moves an existing user-defined named block to the given block. Built-in special
+// blocks may not be changed.
+func UpdateUserSpecial(chain, name string, bn uint64) (*types.SimpleNamedBlock, error) {
+	userSpecials, index, err := findUserSpecial(chain, name)
+	if err != nil {
+		return nil, err
+	}
+
+	special, err := newSpecial(chain, name, bn)
+	if err != nil {
+		return nil, err
+	}
+	userSpecials[index] = *special
+	return special, writeSpecials(getPathToUserSpecials(chain), userSpecials)
+}
+
+// RemoveUserSpecial removes a user-defined named block. Built-in special blocks may not be removed.
+func RemoveUserSpecial(chain, name string) (*types.SimpleNamedBlock, error) {
+	userSpecials, index, err := findUserSpecial(chain, name)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := userSpecials[index]
+	userSpecials = append(userSpecials[:index], userSpecials[index+1:]...)
+	return &removed, writeSpecials(getPathToUserSpecials(chain), userSpecials)
+}
+
+// ValidateSpecialName returns an error if the name may not be used for a named block
+func ValidateSpecialName(name string) error {
+	if !specialNameRe.MatchString(name) || reservedNames[name] {
+		return fmt.Errorf("%s is not a valid name for a block (use lower case letters and underscores optionally followed by digits)", name)
+	}
+	return nil
+}
+
+func findUserSpecial(chain, name string) ([]types.SimpleNamedBlock, int, error) {
+	userSpecials, err := GetUserSpecials(chain)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, special := range userSpecials {
+		if special.Name == name {
+			return userSpecials, i, nil
+		}
+	}
+	if IsSpecialBlock(chain, name) {
+		return nil, 0, fmt.Errorf("%s is a built-in special block and may not be changed", name)
+	}
+	return nil, 0, fmt.Errorf("no user-defined block named %s", name)
+}
+
+// newSpecial returns a special block with the given name and the block's timestamp and date
+func newSpecial(chain, name string, bn uint64) (*types.SimpleNamedBlock, error) {
+	block, err := rpcClient.GetBlockHeaderByNumber(chain, bn)
+	if err != nil {
+		return nil, err
+	}
+	date, _ := FromTsToDate(block.Timestamp)
+	return &types.SimpleNamedBlock{
+		BlockNumber: bn,
+		Name:        name,
+		Timestamp:   block.Timestamp,
+		Date:        date.Format("YYYY-MM-DD HH:mm:ss"),
+	}, nil
+}
@@ -34,6 +34,7 @@ type SimpleAppearance struct {
 	Date             string         `json:"date"`
 	raw              *RawAppearance `json:"-"`
 	// EXISTING_CODE
+	EnsName string `json:"ensName,omitempty"`
 	// EXISTING_CODE
 }
 
@@ -87,6 +88,11 @@ func (s *SimpleAppearance) Model(showHidden bool, format string, extraOptions ma
 		}...)
 	}
 
+	if extraOptions["ens"] == true {
+		model["ensName"] = s.EnsName
+		order = append(order, "ensName")
+	}
+
 	// EXISTING_CODE
 
 	return Model{
@@ -16,6 +16,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -57,6 +58,12 @@ type SimpleFunction struct {
 	payable   bool
 	abiMethod *abi.Method
 	abiEvent  *abi.Event
+	abiError  *abi.Error
+	// Nested holds the articulated inner calls of wrapper functions such as multicall
+	Nested []SimpleNestedCall `json:"nested,omitempty"`
+	// Ambiguous lists the signatures of all candidates when a function or event articulated from
+	// the four-byte database could be decoded with more than one signature
+	Ambiguous []string `json:"ambiguous,omitempty"`
 	// EXISTING_CODE
 }
 
@@ -121,6 +128,31 @@ func (s *SimpleFunction) Model(showHidden bool, format string, extraOptions map[
 			model["stateMutability"] = sm
 		}
 	}
+
+	if extraOptions["decode"] == true {
+		// The values of decoded parameters (see chifra abis --decode) replace their descriptions
+		model["abiSource"] = s.AbiSource
+		order = append(order, "abiSource")
+		inputs := ParametersToMap(s.Inputs)
+		outputs := ParametersToMap(s.Outputs)
+		if format == "json" {
+			delete(model, "inputs")
+			delete(model, "outputs")
+			if inputs != nil {
+				model["inputs"] = inputs
+			}
+			if outputs != nil {
+				model["outputs"] = outputs
+			}
+			if len(s.Ambiguous) > 0 {
+				model["ambiguous"] = s.Ambiguous
+			}
+		} else {
+			model["inputs"] = MakeCompressed(inputs)
+			model["outputs"] = MakeCompressed(outputs)
+			order = append(order, "inputs", "outputs")
+		}
+	}
 	// EXISTING_CODE
 
 	return Model{
@@ -159,6 +191,23 @@ func FunctionFromAbiEvent(ethEvent *abi.Event, abiSource string) *SimpleFunction
 	return function
 }
 
+// FunctionFromAbiError converts go-ethereum's abi.Error (a custom error) to our SimpleFunction
+func FunctionFromAbiError(ethError *abi.Error, abiSource string) *SimpleFunction {
+	// ID is the hash of the signature, the first four bytes of which are the selector
+	fourByte := "0x" + strings.ToLower(common.Bytes2Hex(ethError.ID.Bytes()[:4]))
+	inputs := argumentsToSimpleParameters(ethError.Inputs)
+	function := &SimpleFunction{
+		Encoding:     fourByte,
+		Signature:    ethError.Sig,
+		Name:         ethError.Name,
+		AbiSource:    abiSource,
+		FunctionType: "error",
+		Inputs:       inputs,
+	}
+	function.SetAbiError(ethError)
+	return function
+}
+
 // FunctionFromAbiMethod converts go-ethereum's abi.Method to our SimpleFunction
 func FunctionFromAbiMethod(ethMethod *abi.Method, abiSource string) *SimpleFunction {
 	// method.ID is our "four-byte"
@@ -285,6 +334,30 @@ func AbiEventFromFunction(function *SimpleFunction) (ethMethod *abi.Event, err e
 	return
 }
 
+func AbiErrorFromFunction(function *SimpleFunction) (ethError *abi.Error, err error) {
+	if !function.IsError() {
+		err = fmt.Errorf("functionToAbiError called for a method or event")
+		return
+	}
+
+	removeUnknownTuples(function)
+	jsonAbi, err := json.Marshal([]any{function})
+	if err != nil {
+		return
+	}
+	res, err := abi.JSON(bytes.NewReader(jsonAbi))
+	if err != nil {
+		return
+	}
+	found, ok := res.Errors[function.Name]
+	if !ok {
+		err = fmt.Errorf("generating ABI error: error not found: %s", function.Name)
+		return
+	}
+	ethError = &found
+	return
+}
+
 // removeUnknownTuples replaces unknown tuple type with `bytes` type.
 // A tuple is unknown if we don't know its components (this can happen for
 // inputs/outputs of internal methods)
@@ -305,7 +378,11 @@ func removeUnknownTuples(function *SimpleFunction) {
 }
 
 func (s *SimpleFunction) IsMethod() bool {
-	return s.FunctionType != "event"
+	return s.FunctionType != "event" && s.FunctionType != "error"
+}
+
+func (s *SimpleFunction) IsError() bool {
+	return s.FunctionType == "error"
 }
 
 func (s *SimpleFunction) SetAbiMethod(method *abi.Method) {
@@ -340,6 +417,79 @@ func (s *SimpleFunction) GetAbiEvent() (abiEvent *abi.Event, err error) {
 	return s.abiEvent, nil
 }
 
+func (s *SimpleFunction) SetAbiError(ethError *abi.Error) {
+	s.abiError = ethError
+}
+
+func (s *SimpleFunction) GetAbiError() (abiError *abi.Error, err error) {
+	if s.abiError == nil {
+		abiError, err = AbiErrorFromFunction(s)
+		if err != nil {
+			return
+		}
+		s.SetAbiError(abiError)
+		return
+	}
+	return s.abiError, nil
+}
+
+// SimpleNestedCall is a call made on behalf of a wrapper function (such as multicall, a Safe's
+// execTransaction, or an EntryPoint's handleOps). If the call's ABI is known, Function holds the
+// articulated call (which may itself have nested calls), otherwise only the raw Input is known.
+type SimpleNestedCall struct {
+	To       base.Address    `json:"to"`
+	Input    string          `json:"input,omitempty"`
+	Function *SimpleFunction `json:"function,omitempty"`
+}
+
+// Copy returns a copy of the function that may be articulated without changing the original
+func (s *SimpleFunction) Copy() *SimpleFunction {
+	ret := *s
+	ret.Inputs = make([]SimpleParameter, len(s.Inputs))
+	copy(ret.Inputs, s.Inputs)
+	ret.Outputs = make([]SimpleParameter, len(s.Outputs))
+	copy(ret.Outputs, s.Outputs)
+	ret.Nested = nil
+	return &ret
+}
+
+// NestedToMap returns the models of nested calls (see SimpleNestedCall)
+func NestedToMap(nested []SimpleNestedCall) []map[string]any {
+	ret := make([]map[string]any, 0, len(nested))
+	for _, call := range nested {
+		item := map[string]any{
+			"to": call.To.Hex(),
+		}
+		if call.Function == nil {
+			item["input"] = call.Input
+		} else {
+			function := map[string]any{
+				"name": call.Function.Name,
+			}
+			if inputModels := ParametersToMap(call.Function.Inputs); inputModels != nil {
+				function["inputs"] = inputModels
+			}
+			if len(call.Function.Nested) > 0 {
+				function["nested"] = NestedToMap(call.Function.Nested)
+			}
+			item["function"] = function
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// ErrorToMap returns the model of an articulated error (a revert reason, panic, or custom error)
+func ErrorToMap(s *SimpleFunction) map[string]any {
+	ret := map[string]any{
+		"name": s.Name,
+	}
+	if inputModels := ParametersToMap(s.Inputs); inputModels != nil {
+		ret["inputs"] = inputModels
+	}
+	return ret
+}
+
 // TODO: I feel like we might be able to remove stateMutability since we don't really use it.
 // Normalize sets StateMutability from `payable` field. It is only useful when
 // reading ABIs generated before Solidity 0.5.0, which use `payable` field:
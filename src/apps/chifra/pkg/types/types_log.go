@@ -95,6 +95,9 @@ func (s *SimpleLog) Model(showHidden bool, format string, extraOptions map[strin
 		if inputModels != nil {
 			articulatedLog["inputs"] = inputModels
 		}
+		if len(s.ArticulatedLog.Ambiguous) > 0 {
+			articulatedLog["ambiguous"] = s.ArticulatedLog.Ambiguous
+		}
 	}
 
 	if format == "json" {
@@ -52,6 +52,8 @@ type SimpleName struct {
 	Tags       string       `json:"tags"`
 	raw        *RawName     `json:"-"`
 	// EXISTING_CODE
+	EnsName string `json:"ensName,omitempty"`
+	Score   int    `json:"score,omitempty"`
 	// EXISTING_CODE
 }
 
@@ -191,6 +193,16 @@ func (s *SimpleName) Model(showHidden bool, format string, extraOptions map[stri
 		}
 	}
 
+	if extraOptions["ens"] == true {
+		model["ensName"] = s.EnsName
+		order = append(order, "ensName")
+	}
+
+	if extraOptions["score"] == true {
+		model["score"] = s.Score
+		order = append(order, "score")
+	}
+
 	// EXISTING_CODE
 
 	return Model{
@@ -36,20 +36,21 @@ type RawReceipt struct {
 }
 
 type SimpleReceipt struct {
-	BlockHash         base.Hash    `json:"blockHash,omitempty"`
-	BlockNumber       base.Blknum  `json:"blockNumber"`
-	ContractAddress   base.Address `json:"contractAddress,omitempty"`
-	CumulativeGasUsed string       `json:"cumulativeGasUsed,omitempty"`
-	EffectiveGasPrice base.Gas     `json:"effectiveGasPrice,omitempty"`
-	From              base.Address `json:"from,omitempty"`
-	GasUsed           base.Gas     `json:"gasUsed"`
-	IsError           bool         `json:"isError,omitempty"`
-	Logs              []SimpleLog  `json:"logs"`
-	Status            uint32       `json:"status"`
-	To                base.Address `json:"to,omitempty"`
-	TransactionHash   base.Hash    `json:"transactionHash"`
-	TransactionIndex  base.Blknum  `json:"transactionIndex"`
-	raw               *RawReceipt  `json:"-"`
+	ArticulatedError  *SimpleFunction `json:"articulatedError,omitempty"`
+	BlockHash         base.Hash       `json:"blockHash,omitempty"`
+	BlockNumber       base.Blknum     `json:"blockNumber"`
+	ContractAddress   base.Address    `json:"contractAddress,omitempty"`
+	CumulativeGasUsed string          `json:"cumulativeGasUsed,omitempty"`
+	EffectiveGasPrice base.Gas        `json:"effectiveGasPrice,omitempty"`
+	From              base.Address    `json:"from,omitempty"`
+	GasUsed           base.Gas        `json:"gasUsed"`
+	IsError           bool            `json:"isError,omitempty"`
+	Logs              []SimpleLog     `json:"logs"`
+	Status            uint32          `json:"status"`
+	To                base.Address    `json:"to,omitempty"`
+	TransactionHash   base.Hash       `json:"transactionHash"`
+	TransactionIndex  base.Blknum     `json:"transactionIndex"`
+	raw               *RawReceipt     `json:"-"`
 	// EXISTING_CODE
 	// EXISTING_CODE
 }
@@ -110,11 +111,17 @@ func (s *SimpleReceipt) Model(showHidden bool, format string, extraOptions map[s
 					if inputModels != nil {
 						articulatedLog["inputs"] = inputModels
 					}
+					if len(log.ArticulatedLog.Ambiguous) > 0 {
+						articulatedLog["ambiguous"] = log.ArticulatedLog.Ambiguous
+					}
 					logModel["articulatedLog"] = articulatedLog
 				}
 				logs = append(logs, logModel)
 			}
 			model["logs"] = logs
+			if s.ArticulatedError != nil {
+				model["articulatedError"] = ErrorToMap(s.ArticulatedError)
+			}
 		} else {
 			model["logs"] = s.Logs
 			// order = append(order, "logs")
@@ -142,6 +149,11 @@ func (s *SimpleReceipt) Model(showHidden bool, format string, extraOptions map[s
 		model["isError"] = s.IsError
 		order = append(order, "isError")
 
+		if extraOptions["articulate"] == true && s.ArticulatedError != nil {
+			model["compressedError"] = MakeCompressed(ErrorToMap(s.ArticulatedError))
+			order = append(order, "compressedError")
+		}
+
 		if showHidden {
 			model["contractAddress"] = s.ContractAddress.Hex()
 			order = append(order, "contractAddress")
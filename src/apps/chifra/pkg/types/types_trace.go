@@ -38,6 +38,7 @@ type RawTrace struct {
 
 type SimpleTrace struct {
 	Action           *SimpleTraceAction `json:"action"`
+	ArticulatedError *SimpleFunction    `json:"articulatedError,omitempty"`
 	ArticulatedTrace *SimpleFunction    `json:"articulatedTrace,omitempty"`
 	BlockHash        base.Hash          `json:"blockHash"`
 	BlockNumber      base.Blknum        `json:"blockNumber"`
@@ -112,6 +113,18 @@ func (s *SimpleTrace) Model(showHidden bool, format string, extraOptions map[str
 		if sm != "" && sm != "nonpayable" && sm != "view" {
 			articulatedTrace["stateMutability"] = sm
 		}
+		if len(s.ArticulatedTrace.Nested) > 0 {
+			articulatedTrace["nested"] = NestedToMap(s.ArticulatedTrace.Nested)
+		}
+		if len(s.ArticulatedTrace.Ambiguous) > 0 {
+			articulatedTrace["ambiguous"] = s.ArticulatedTrace.Ambiguous
+		}
+	}
+
+	var articulatedError map[string]interface{}
+	hasArticulatedError := extraOptions["articulate"] == true && s.ArticulatedError != nil
+	if hasArticulatedError {
+		articulatedError = ErrorToMap(s.ArticulatedError)
 	}
 
 	if format == "json" {
@@ -132,6 +145,9 @@ func (s *SimpleTrace) Model(showHidden bool, format string, extraOptions map[str
 		if isArticulated {
 			model["articulatedTrace"] = articulatedTrace
 		}
+		if hasArticulatedError {
+			model["articulatedError"] = articulatedError
+		}
 
 	} else {
 		to := hexutil.Encode(s.Action.To.Bytes())
@@ -173,6 +189,10 @@ func (s *SimpleTrace) Model(showHidden bool, format string, extraOptions map[str
 			model["compressedTrace"] = MakeCompressed(articulatedTrace)
 			order = append(order, "compressedTrace")
 		}
+		if hasArticulatedError {
+			model["compressedError"] = MakeCompressed(articulatedError)
+			order = append(order, "compressedError")
+		}
 	}
 	// EXISTING_CODE
 
@@ -153,6 +153,12 @@ func (s *SimpleTransaction) Model(showHidden bool, format string, extraOptions m
 		if sm != "" && sm != "nonpayable" && sm != "view" {
 			articulatedTx["stateMutability"] = sm
 		}
+		if len(s.ArticulatedTx.Nested) > 0 {
+			articulatedTx["nested"] = NestedToMap(s.ArticulatedTx.Nested)
+		}
+		if len(s.ArticulatedTx.Ambiguous) > 0 {
+			articulatedTx["ambiguous"] = s.ArticulatedTx.Ambiguous
+		}
 	}
 
 	if format == "json" {
@@ -222,11 +228,17 @@ func (s *SimpleTransaction) Model(showHidden bool, format string, extraOptions m
 						"name":   log.ArticulatedLog.Name,
 						"inputs": inputModels,
 					}
+					if len(log.ArticulatedLog.Ambiguous) > 0 {
+						articulatedLog["ambiguous"] = log.ArticulatedLog.Ambiguous
+					}
 					logModel["articulatedLog"] = articulatedLog
 				}
 				logs = append(logs, logModel)
 			}
 			receiptModel["logs"] = logs
+			if extraOptions["articulate"] == true && s.Receipt.ArticulatedError != nil {
+				receiptModel["articulatedError"] = ErrorToMap(s.Receipt.ArticulatedError)
+			}
 			model["receipt"] = receiptModel
 		}
 
@@ -276,6 +288,11 @@ func (s *SimpleTransaction) Model(showHidden bool, format string, extraOptions m
 			model["nTraces"] = len(s.Traces)
 			order = append(order, "nTraces")
 		}
+
+		if extraOptions["articulate"] == true && s.Receipt != nil && s.Receipt.ArticulatedError != nil {
+			model["compressedError"] = MakeCompressed(ErrorToMap(s.Receipt.ArticulatedError))
+			order = append(order, "compressedError")
+		}
 	}
 	// EXISTING_CODE
 
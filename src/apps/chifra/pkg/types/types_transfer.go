@@ -55,6 +55,8 @@ type SimpleTransfer struct {
 	TransactionIndex base.Blknum    `json:"transactionIndex"`
 	raw              *RawTransfer   `json:"-"`
 	// EXISTING_CODE
+	TokenType string    `json:"tokenType,omitempty"`
+	TokenId   *base.Wei `json:"tokenId,omitempty"`
 	// EXISTING_CODE
 }
 
@@ -71,6 +73,47 @@ func (s *SimpleTransfer) Model(showHidden bool, format string, extraOptions map[
 	var order = []string{}
 
 	// EXISTING_CODE
+	model = map[string]interface{}{
+		"blockNumber":      s.BlockNumber,
+		"transactionIndex": s.TransactionIndex,
+		"logIndex":         s.LogIndex,
+		"transactionHash":  s.TransactionHash,
+		"timestamp":        s.Timestamp,
+		"date":             s.Date,
+		"sender":           s.Sender,
+		"recipient":        s.Recipient,
+		"assetAddr":        s.AssetAddr,
+		"assetSymbol":      s.AssetSymbol,
+		"decimals":         s.Decimals,
+		"amount":           s.Amount.String(),
+		"tokenType":        s.TokenType,
+		"tokenId":          "",
+		"encoding":         s.Encoding,
+	}
+
+	order = []string{
+		"blockNumber",
+		"transactionIndex",
+		"logIndex",
+		"transactionHash",
+		"timestamp",
+		"date",
+		"sender",
+		"recipient",
+		"assetAddr",
+		"assetSymbol",
+		"decimals",
+		"amount",
+		"tokenType",
+		"tokenId",
+		"encoding",
+	}
+
+	if s.TokenId != nil {
+		model["tokenId"] = s.TokenId.String()
+	} else if format == "json" {
+		delete(model, "tokenId")
+	}
 	// EXISTING_CODE
 
 	return Model{
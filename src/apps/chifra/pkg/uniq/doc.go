@@ -0,0 +1,3 @@
+// Package uniq extracts the addresses that appear in transactions, traces, and logs using the
+// same rules the scraper uses when it builds the index
+package uniq
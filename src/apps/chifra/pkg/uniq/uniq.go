@@ -0,0 +1,145 @@
+// Copyright 2021 The TrueBlocks Authors. All rights reserved.
+// Use of this source code is governed by a license that can
+// be found in the LICENSE file.
+
+package uniq
+
+import (
+	"strings"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+// AddressFunc is called for each address found along with the reason it was found (for
+// example `from` or `log_topic`). It may be called more than once for the same address.
+type AddressFunc func(address base.Address, reason string)
+
+// IsAddress Returns true if the address is not a precompile and not the zero address
+func IsAddress(addr string) bool {
+	// As per EIP 1352, all addresses less or equal to the following value are reserved for pre-compiles.
+	// We don't index precompiles. https://eips.ethereum.org/EIPS/eip-1352
+	return addr > "0x000000000000000000000000000000000000ffff"
+}
+
+// IsImplicitAddress processes a transaction's 'input' data and 'output' data or an event's data field.
+// Anything with 12 bytes of leading zeros but not more than 19 leading zeros (24 and 38 characters
+// respectively).
+func IsImplicitAddress(addr string) bool {
+	// Any 32-byte value smaller than this number is assumed to be a 'value'. We call them baddresses.
+	// While this may seem like a lot of addresses being labeled as baddresses, it's not very many:
+	// ---> 2 out of every 10000000000000000000000000000000000000000000000 are baddresses.
+	small := "00000000000000000000000000000000000000ffffffffffffffffffffffffff"
+	//        -------+-------+-------+-------+-------+-------+-------+-------+
+	if addr <= small {
+		return false
+	}
+
+	// Any 32-byte value with less than this many leading zeros is not an address (address are 20-bytes and
+	// zero padded to the left)
+	largePrefix := "000000000000000000000000"
+	//              -------+-------+-------+
+	if !strings.HasPrefix(addr, largePrefix) {
+		return false
+	}
+
+	// Of the valid addresses, we assume any ending with this many trailing zeros is also a baddress.
+	if strings.HasSuffix(addr, "00000000") {
+		return false
+	}
+
+	// extract the potential address
+	return IsAddress("0x" + string(addr[24:]))
+}
+
+// UniqFromTransaction reports the sender, the recipient, and any contract the transaction created.
+// If the transaction carries its traces, they are reported as well. If not, the addresses found
+// in the transaction's input are reported instead (the top-level trace would have carried them).
+// If the transaction carries a receipt, its logs are reported.
+func UniqFromTransaction(tx *types.SimpleTransaction, addrFunc AddressFunc) {
+	addAddress(tx.From, "from", addrFunc)
+	addAddress(tx.To, "to", addrFunc)
+
+	if len(tx.Traces) > 0 {
+		for i := range tx.Traces {
+			UniqFromTrace(&tx.Traces[i], addrFunc)
+		}
+	} else if len(tx.Input) > 10 {
+		addImplicit(tx.Input[10:], "input", addrFunc)
+	}
+
+	if tx.Receipt != nil {
+		addAddress(tx.Receipt.ContractAddress, "creation", addrFunc)
+		for i := range tx.Receipt.Logs {
+			UniqFromLog(&tx.Receipt.Logs[i], addrFunc)
+		}
+	}
+}
+
+// UniqFromTrace reports the addresses found in a trace's action, its input, and its output.
+// Reward traces are not reported since they only appear among a block's traces.
+func UniqFromTrace(trace *types.SimpleTrace, addrFunc AddressFunc) {
+	if trace.Action == nil {
+		return
+	}
+
+	switch trace.TraceType {
+	case "call":
+		addAddress(trace.Action.From, "trace_from", addrFunc)
+		addAddress(trace.Action.To, "trace_to", addrFunc)
+
+	case "suicide":
+		// the contract that died and where it sent its money
+		addAddress(trace.Action.Address, "suicide", addrFunc)
+		addAddress(trace.Action.RefundAddress, "refund", addrFunc)
+
+	case "create":
+		// the creator and the new contract
+		addAddress(trace.Action.From, "creator", addrFunc)
+		if trace.Result != nil {
+			addAddress(trace.Result.Address, "creation", addrFunc)
+		}
+		// If it's a top level trace, then the call data is the init
+		if len(trace.TraceAddress) == 0 && len(trace.Action.Init) > 10 {
+			addImplicit(trace.Action.Init[10:], "init", addrFunc)
+		}
+	}
+
+	if len(trace.Action.Input) > 10 {
+		addImplicit(trace.Action.Input[10:], "input", addrFunc)
+	}
+
+	if trace.Result != nil && len(trace.Result.Output) > 2 {
+		addImplicit(trace.Result.Output[2:], "output", addrFunc)
+	}
+}
+
+// UniqFromLog reports the addresses found in a log's topics and data
+func UniqFromLog(log *types.SimpleLog, addrFunc AddressFunc) {
+	for i := range log.Topics {
+		topic := log.Topics[i].Hex()
+		if len(topic) > 2 && IsImplicitAddress(topic[2:]) {
+			addrFunc(base.HexToAddress("0x"+topic[26:]), "log_topic")
+		}
+	}
+
+	if len(log.Data) > 2 {
+		addImplicit(log.Data[2:], "log_data", addrFunc)
+	}
+}
+
+func addAddress(address base.Address, reason string, addrFunc AddressFunc) {
+	if IsAddress(address.Hex()) {
+		addrFunc(address, reason)
+	}
+}
+
+// addImplicit reports each 32-byte word of the (non-0x-prefixed) data that looks like an address
+func addImplicit(data, reason string, addrFunc AddressFunc) {
+	for i := 0; i < len(data)/64; i++ {
+		word := data[i*64 : (i+1)*64]
+		if IsImplicitAddress(word) {
+			addrFunc(base.HexToAddress("0x"+word[24:]), reason)
+		}
+	}
+}
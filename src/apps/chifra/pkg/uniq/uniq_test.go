@@ -0,0 +1,86 @@
+package uniq
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/base"
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/types"
+)
+
+func TestIsImplicitAddress(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected bool
+	}{
+		{"000000000000000000000000f503017d7baf7fbc0fff7492b751025c6a78179b", true},
+		{"0000000000000000000000000000000000000000000000000de0b6b3a7640000", false}, // a value
+		{"000000000000000000000000f503017d7baf7fbc0fff7492b751025c00000000", false}, // trailing zeros
+		{"100000000000000000000000f503017d7baf7fbc0fff7492b751025c6a78179b", false}, // too large
+		{"000000000000000000000000000000000000000000000000000000000000ffff", false}, // precompile
+	}
+	for _, test := range tests {
+		if got := IsImplicitAddress(test.word); got != test.expected {
+			t.Errorf("IsImplicitAddress(%s) = %t, expected %t", test.word, got, test.expected)
+		}
+	}
+}
+
+func TestUniqFromTransaction(t *testing.T) {
+	sender := "0xf503017d7baf7fbc0fff7492b751025c6a78179b"
+	token := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	recipient := "0x054993ab0f2b1acc0fdc65405ee203b4271bebe6"
+	word := func(addr string) string { return "000000000000000000000000" + addr[2:] }
+
+	tx := types.SimpleTransaction{
+		From:  base.HexToAddress(sender),
+		To:    base.HexToAddress(token),
+		Input: "0xa9059cbb" + word(recipient) + "0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		Receipt: &types.SimpleReceipt{
+			Logs: []types.SimpleLog{{
+				Address: base.HexToAddress(token),
+				Topics: []base.Hash{
+					base.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+					base.HexToHash("0x" + word(sender)),
+					base.HexToHash("0x" + word(recipient)),
+				},
+				Data: "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+			}},
+		},
+	}
+
+	found := map[string][]string{}
+	UniqFromTransaction(&tx, func(address base.Address, reason string) {
+		found[address.Hex()] = append(found[address.Hex()], reason)
+	})
+
+	expected := map[string]string{
+		sender:    "from|log_topic",
+		token:     "to",
+		recipient: "input|log_topic",
+	}
+	if len(found) != len(expected) {
+		t.Fatal("unexpected addresses", found)
+	}
+	for addr, reasons := range expected {
+		sort.Strings(found[addr])
+		if got := strings.Join(found[addr], "|"); got != reasons {
+			t.Errorf("%s was found as %s, expected %s", addr, got, reasons)
+		}
+	}
+
+	// With traces, the input is taken from the traces rather than the transaction
+	tx.Receipt = nil
+	tx.Traces = []types.SimpleTrace{{
+		TraceType: "call",
+		Action:    &types.SimpleTraceAction{From: base.HexToAddress(sender), To: base.HexToAddress(token)},
+	}}
+	found = map[string][]string{}
+	UniqFromTransaction(&tx, func(address base.Address, reason string) {
+		found[address.Hex()] = append(found[address.Hex()], reason)
+	})
+	if len(found[recipient]) != 0 || len(found[sender]) != 2 {
+		t.Error("unexpected addresses", found)
+	}
+}
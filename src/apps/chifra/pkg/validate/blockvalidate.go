@@ -84,7 +84,32 @@ func IsDateTimeString(str string) bool {
 	if err != nil {
 		return false
 	}
-	return bRange.StartType == identifiers.BlockDate
+	return bRange.StartType == identifiers.BlockDate && len(bRange.Start.Offsets) == 0
+}
+
+// IsOffsetBlock returns true if the string is a single block identifier followed by one or more
+// offsets (for example, latest-1000, london+50, or 2023-01-01T00:00+6h)
+func IsOffsetBlock(chain, str string) (bool, error) {
+	bRange, err := identifiers.NewBlockRange(str)
+	if err != nil || bRange.EndType != identifiers.NotDefined || len(bRange.Start.Offsets) == 0 {
+		return false, nil
+	}
+	return true, validateOffsetPoint(chain, bRange.StartType, &bRange.Start)
+}
+
+// validateOffsetPoint checks the base of a point that has offsets and the offsets themselves
+func validateOffsetPoint(chain string, pointType identifiers.IdentifierType, point *identifiers.Point) error {
+	if pointType == identifiers.BlockSpecial && !tslib.IsSpecialBlock(chain, point.Special) {
+		return &InvalidIdentifierLiteralError{
+			Value: point.Special,
+		}
+	}
+	for _, offset := range point.Offsets {
+		if _, _, err := identifiers.ParseOffset(offset); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func ToIsoDateStr2(dateStr string) string {
@@ -126,10 +151,19 @@ func IsRange(chain, str string) (bool, error) {
 	bRange, err := identifiers.NewBlockRange(str)
 
 	if err == nil {
-		if bRange.Start.Special == "latest" {
+		if bRange.Start.Special == "latest" && len(bRange.Start.Offsets) == 0 {
 			return false, errors.New("cannot start range with 'latest'")
 		}
 
+		for _, offsetErr := range []error{
+			validateOffsetPoint(chain, bRange.StartType, &bRange.Start),
+			validateOffsetPoint(chain, bRange.EndType, &bRange.End),
+		} {
+			if offsetErr != nil {
+				return false, offsetErr
+			}
+		}
+
 		if bRange.StartType == identifiers.BlockSpecial &&
 			!tslib.IsSpecialBlock(chain, bRange.Start.Special) {
 			return false, &InvalidIdentifierLiteralError{
@@ -145,7 +179,8 @@ func IsRange(chain, str string) (bool, error) {
 		}
 
 		onlyNumbers := bRange.StartType == identifiers.BlockNumber &&
-			bRange.EndType == identifiers.BlockNumber
+			bRange.EndType == identifiers.BlockNumber &&
+			len(bRange.Start.Offsets) == 0 && len(bRange.End.Offsets) == 0
 
 		if onlyNumbers && bRange.Start.Number >= bRange.End.Number {
 			return false, errors.New("'stop' must be strictly larger than 'start'")
@@ -89,6 +89,15 @@ func ValidateIdentifiers(chain string, ids []string, validTypes ValidArgumentTyp
 			continue
 		}
 
+		if isBitmaskSet(ValidArgumentSpecialBlock) {
+			if isOffset, err := IsOffsetBlock(chain, identifier); err != nil {
+				return err
+			} else if isOffset {
+				appendBlockId(results, identifier)
+				continue
+			}
+		}
+
 		if isBitmaskSet(ValidArgumentTransHash) && IsTransHash(identifier) {
 			appendTxId(results, identifier)
 			continue
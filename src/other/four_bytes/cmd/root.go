@@ -6,6 +6,7 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/pinning"
 	fourbytes "github.com/TrueBlocks/trueblocks-core/src/other/four_bytes/pkg/fourBytes"
 	"github.com/spf13/cobra"
 )
@@ -21,8 +22,22 @@ var rootCmd = &cobra.Command{
 		if outDir == "" {
 			return errors.New("outdir is required")
 		}
-		fmt.Printf("Generating database (with %d goroutines each step)\n", runtime.GOMAXPROCS(0))
-		return fourbytes.GenerateFromFiles(outDir)
+		manifestOnly, err := cmd.Flags().GetBool("manifest")
+		if err != nil {
+			return
+		}
+		if !manifestOnly {
+			fmt.Printf("Generating database (with %d goroutines each step)\n", runtime.GOMAXPROCS(0))
+			if err = fourbytes.GenerateFromFiles(outDir); err != nil {
+				return
+			}
+		}
+		if !manifestOnly && !pinning.LocalDaemonRunning() {
+			fmt.Println("No IPFS daemon is running. Use --manifest to pin the chunks and write the manifest later.")
+			return nil
+		}
+		fmt.Println("Pinning chunks and writing manifest")
+		return fourbytes.WriteManifest(outDir)
 	},
 }
 
@@ -35,4 +50,5 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().StringP("outdir", "o", "", "Where to save chunks")
+	rootCmd.Flags().BoolP("manifest", "m", false, "Only pin existing chunks and write their manifest")
 }
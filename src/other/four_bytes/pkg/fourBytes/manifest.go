@@ -0,0 +1,68 @@
+package fourbytes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/TrueBlocks/trueblocks-core/src/apps/chifra/pkg/pinning"
+)
+
+// Manifest lists the chunks of the database and their IPFS hashes. chifra reads it (from
+// abis/four_bytes/manifest.json or, failing that, from the Unchained Index where it is published
+// under the name mainnet-four_bytes) to download chunks as they are needed.
+type Manifest struct {
+	Version string          `json:"version"`
+	Chunks  []ManifestChunk `json:"chunks"`
+}
+
+type ManifestChunk struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// WriteManifest pins each chunk in outDir to the local IPFS daemon and writes manifest.json
+// listing their hashes to outDir
+func WriteManifest(outDir string) error {
+	if !pinning.LocalDaemonRunning() {
+		return errors.New("the manifest requires a running IPFS daemon")
+	}
+	service, err := pinning.NewPinningService("mainnet", pinning.Local)
+	if err != nil {
+		return err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(outDir, "??", "????"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	man := Manifest{Version: "v1.0.0"}
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		hash, err := service.PinFile(path, true)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(outDir, path)
+		man.Chunks = append(man.Chunks, ManifestChunk{Name: name, Hash: hash.String(), Size: info.Size()})
+		if (i+1)%1000 == 0 {
+			fmt.Printf("%d... ", i+1)
+		}
+	}
+	fmt.Println()
+
+	contents, err := json.MarshalIndent(man, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "manifest.json"), contents, 0644)
+}